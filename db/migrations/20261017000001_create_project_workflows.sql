@@ -0,0 +1,67 @@
+-- migrate:up
+CREATE TABLE project_statuses (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(20) NOT NULL,
+    position INTEGER NOT NULL,
+    is_done BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (project_id, name)
+);
+
+CREATE TABLE project_status_transitions (
+    project_id UUID NOT NULL,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    PRIMARY KEY (project_id, from_status, to_status),
+    FOREIGN KEY (project_id, from_status) REFERENCES project_statuses(project_id, name) ON DELETE CASCADE,
+    FOREIGN KEY (project_id, to_status) REFERENCES project_statuses(project_id, name) ON DELETE CASCADE
+);
+
+-- Every project starts with the classic four-column workflow where any status
+-- can move to any other, matching the behaviour before workflows existed.
+CREATE OR REPLACE FUNCTION seed_project_workflow() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO project_statuses (project_id, name, position, is_done) VALUES
+    (NEW.id, 'todo', 0, FALSE),
+    (NEW.id, 'in_progress', 1, FALSE),
+    (NEW.id, 'review', 2, FALSE),
+    (NEW.id, 'done', 3, TRUE);
+  INSERT INTO project_status_transitions (project_id, from_status, to_status)
+  SELECT f.project_id, f.name, t.name
+  FROM project_statuses f
+  JOIN project_statuses t ON t.project_id = f.project_id AND t.name <> f.name
+  WHERE f.project_id = NEW.id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER project_workflow_seed_trigger
+  AFTER INSERT ON projects
+  FOR EACH ROW EXECUTE FUNCTION seed_project_workflow();
+
+INSERT INTO project_statuses (project_id, name, position, is_done)
+SELECT p.id, s.name, s.position, s.is_done
+FROM projects p
+CROSS JOIN (VALUES
+    ('todo', 0, FALSE),
+    ('in_progress', 1, FALSE),
+    ('review', 2, FALSE),
+    ('done', 3, TRUE)
+) AS s(name, position, is_done);
+
+INSERT INTO project_status_transitions (project_id, from_status, to_status)
+SELECT f.project_id, f.name, t.name
+FROM project_statuses f
+JOIN project_statuses t ON t.project_id = f.project_id AND t.name <> f.name;
+
+-- Task statuses are now validated against the owning project's workflow.
+ALTER TABLE tasks DROP CONSTRAINT tasks_status_check;
+ALTER TABLE tasks ADD CONSTRAINT tasks_project_status_fkey
+    FOREIGN KEY (project_id, status) REFERENCES project_statuses(project_id, name);
+
+-- migrate:down
+ALTER TABLE tasks DROP CONSTRAINT tasks_project_status_fkey;
+ALTER TABLE tasks ADD CONSTRAINT tasks_status_check CHECK (status IN ('todo', 'in_progress', 'review', 'done'));
+DROP TRIGGER project_workflow_seed_trigger ON projects;
+DROP FUNCTION seed_project_workflow();
+DROP TABLE project_status_transitions;
+DROP TABLE project_statuses;
@@ -0,0 +1,14 @@
+-- migrate:up
+CREATE TABLE task_dependencies (
+    blocker_task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (blocker_task_id, blocked_task_id),
+    CHECK (blocker_task_id <> blocked_task_id)
+);
+
+CREATE INDEX idx_task_dependencies_blocked ON task_dependencies (blocked_task_id);
+
+-- migrate:down
+DROP TABLE task_dependencies;
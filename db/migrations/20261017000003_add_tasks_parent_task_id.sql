@@ -0,0 +1,8 @@
+-- migrate:up
+ALTER TABLE tasks ADD COLUMN parent_task_id UUID REFERENCES tasks(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_tasks_parent_task_id ON tasks (parent_task_id) WHERE parent_task_id IS NOT NULL;
+
+-- migrate:down
+DROP INDEX idx_tasks_parent_task_id;
+ALTER TABLE tasks DROP COLUMN parent_task_id;
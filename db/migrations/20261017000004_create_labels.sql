@@ -0,0 +1,24 @@
+-- migrate:up
+CREATE TABLE labels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id),
+    name VARCHAR(100) NOT NULL,
+    color VARCHAR(7) NOT NULL DEFAULT '#808080' CHECK (color ~ '^#[0-9a-fA-F]{6}$'),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_labels_workspace_name ON labels (workspace_id, lower(name));
+
+CREATE TABLE task_labels (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    label_id UUID NOT NULL REFERENCES labels(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, label_id)
+);
+
+CREATE INDEX idx_task_labels_label_task ON task_labels (label_id, task_id);
+
+-- migrate:down
+DROP TABLE task_labels;
+DROP TABLE labels;
@@ -0,0 +1,19 @@
+-- migrate:up
+-- Title matches outrank description and comment matches (weights A > B).
+ALTER TABLE tasks ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+ALTER TABLE task_comments ADD COLUMN search_vector tsvector
+    GENERATED ALWAYS AS (setweight(to_tsvector('english', content), 'B')) STORED;
+
+CREATE INDEX idx_tasks_search_vector ON tasks USING GIN (search_vector);
+CREATE INDEX idx_task_comments_search_vector ON task_comments USING GIN (search_vector);
+
+-- migrate:down
+DROP INDEX idx_task_comments_search_vector;
+DROP INDEX idx_tasks_search_vector;
+ALTER TABLE task_comments DROP COLUMN search_vector;
+ALTER TABLE tasks DROP COLUMN search_vector;
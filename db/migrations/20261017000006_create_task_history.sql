@@ -0,0 +1,54 @@
+-- migrate:up
+CREATE TABLE task_history (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    operation VARCHAR(20) NOT NULL CHECK (operation IN ('create', 'update', 'delete', 'restore')),
+    actor_id VARCHAR(255),
+    changes JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT clock_timestamp()
+);
+
+CREATE INDEX idx_task_history_task_created ON task_history (task_id, created_at DESC, id DESC);
+
+-- Records a field-level diff ({"field": {"old": ..., "new": ...}}) for every
+-- task write, whichever service or statement made it. Bookkeeping columns are
+-- ignored. The acting user is read from the transaction-local app.actor_id
+-- setting; writes that don't set it are recorded with a NULL actor.
+CREATE OR REPLACE FUNCTION record_task_history() RETURNS trigger AS $$
+DECLARE
+  ignored TEXT[] := ARRAY['id', 'workspace_id', 'created_at', 'updated_at', 'search_vector'];
+  old_row JSONB := CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) ELSE '{}'::jsonb END;
+  diff JSONB;
+  op TEXT := 'update';
+BEGIN
+  SELECT COALESCE(jsonb_object_agg(n.key, jsonb_build_object('old', old_row -> n.key, 'new', n.value)), '{}'::jsonb)
+    INTO diff
+  FROM jsonb_each(to_jsonb(NEW)) n
+  WHERE n.key <> ALL (ignored)
+    AND (old_row -> n.key) IS DISTINCT FROM n.value
+    AND NOT (TG_OP = 'INSERT' AND n.value = 'null'::jsonb);
+
+  IF TG_OP = 'INSERT' THEN
+    op := 'create';
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    op := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    op := 'restore';
+  ELSIF diff = '{}'::jsonb THEN
+    RETURN NEW;
+  END IF;
+
+  INSERT INTO task_history (task_id, operation, actor_id, changes)
+  VALUES (NEW.id, op, NULLIF(current_setting('app.actor_id', true), ''), diff);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_history_trigger
+  AFTER INSERT OR UPDATE ON tasks
+  FOR EACH ROW EXECUTE FUNCTION record_task_history();
+
+-- migrate:down
+DROP TRIGGER task_history_trigger ON tasks;
+DROP FUNCTION record_task_history();
+DROP TABLE task_history;
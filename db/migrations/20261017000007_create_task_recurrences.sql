@@ -0,0 +1,15 @@
+-- migrate:up
+-- One row per recurring series. task_id always points at the latest
+-- occurrence; the worker moves it forward each time it creates the next one.
+CREATE TABLE task_recurrences (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL UNIQUE REFERENCES tasks(id) ON DELETE CASCADE,
+    rrule TEXT NOT NULL,
+    dtstart DATE NOT NULL,
+    occurrence INTEGER NOT NULL DEFAULT 1 CHECK (occurrence > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- migrate:down
+DROP TABLE task_recurrences;
@@ -0,0 +1,28 @@
+-- migrate:up
+-- Per-workspace reminder thresholds, in hours relative to the end of a task's
+-- due date. Workspaces without a row use the defaults below.
+CREATE TABLE workspace_reminder_settings (
+    workspace_id UUID PRIMARY KEY REFERENCES workspaces(id) ON DELETE CASCADE,
+    due_soon_hours INTEGER[] NOT NULL DEFAULT '{24}',
+    overdue_hours INTEGER[] NOT NULL DEFAULT '{0}',
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- One row per reminder sent. The primary key is what makes each reminder
+-- fire exactly once per task, threshold and due date; moving the due date
+-- re-arms the reminders.
+CREATE TABLE task_due_notifications (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    event_type VARCHAR(100) NOT NULL CHECK (event_type IN ('task.due_soon', 'task.overdue')),
+    threshold_hours INTEGER NOT NULL,
+    due_date DATE NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, event_type, threshold_hours, due_date)
+);
+
+CREATE INDEX idx_tasks_due_date ON tasks (due_date) WHERE deleted_at IS NULL AND due_date IS NOT NULL;
+
+-- migrate:down
+DROP INDEX idx_tasks_due_date;
+DROP TABLE task_due_notifications;
+DROP TABLE workspace_reminder_settings;
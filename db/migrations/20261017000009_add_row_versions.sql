@@ -0,0 +1,96 @@
+-- migrate:up
+ALTER TABLE workspaces ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE projects ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE tasks ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
+
+-- Every write bumps the row version, whichever statement made it, so a
+-- client holding an older version can detect that it would overwrite
+-- someone else's change.
+CREATE OR REPLACE FUNCTION bump_row_version() RETURNS trigger AS $$
+BEGIN
+  NEW.version := OLD.version + 1;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workspaces_version_trigger
+  BEFORE UPDATE ON workspaces
+  FOR EACH ROW EXECUTE FUNCTION bump_row_version();
+
+CREATE TRIGGER projects_version_trigger
+  BEFORE UPDATE ON projects
+  FOR EACH ROW EXECUTE FUNCTION bump_row_version();
+
+CREATE TRIGGER tasks_version_trigger
+  BEFORE UPDATE ON tasks
+  FOR EACH ROW EXECUTE FUNCTION bump_row_version();
+
+-- The version is bookkeeping, not a change worth recording in task history.
+CREATE OR REPLACE FUNCTION record_task_history() RETURNS trigger AS $$
+DECLARE
+  ignored TEXT[] := ARRAY['id', 'workspace_id', 'created_at', 'updated_at', 'version', 'search_vector'];
+  old_row JSONB := CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) ELSE '{}'::jsonb END;
+  diff JSONB;
+  op TEXT := 'update';
+BEGIN
+  SELECT COALESCE(jsonb_object_agg(n.key, jsonb_build_object('old', old_row -> n.key, 'new', n.value)), '{}'::jsonb)
+    INTO diff
+  FROM jsonb_each(to_jsonb(NEW)) n
+  WHERE n.key <> ALL (ignored)
+    AND (old_row -> n.key) IS DISTINCT FROM n.value
+    AND NOT (TG_OP = 'INSERT' AND n.value = 'null'::jsonb);
+
+  IF TG_OP = 'INSERT' THEN
+    op := 'create';
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    op := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    op := 'restore';
+  ELSIF diff = '{}'::jsonb THEN
+    RETURN NEW;
+  END IF;
+
+  INSERT INTO task_history (task_id, operation, actor_id, changes)
+  VALUES (NEW.id, op, NULLIF(current_setting('app.actor_id', true), ''), diff);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- migrate:down
+CREATE OR REPLACE FUNCTION record_task_history() RETURNS trigger AS $$
+DECLARE
+  ignored TEXT[] := ARRAY['id', 'workspace_id', 'created_at', 'updated_at', 'search_vector'];
+  old_row JSONB := CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) ELSE '{}'::jsonb END;
+  diff JSONB;
+  op TEXT := 'update';
+BEGIN
+  SELECT COALESCE(jsonb_object_agg(n.key, jsonb_build_object('old', old_row -> n.key, 'new', n.value)), '{}'::jsonb)
+    INTO diff
+  FROM jsonb_each(to_jsonb(NEW)) n
+  WHERE n.key <> ALL (ignored)
+    AND (old_row -> n.key) IS DISTINCT FROM n.value
+    AND NOT (TG_OP = 'INSERT' AND n.value = 'null'::jsonb);
+
+  IF TG_OP = 'INSERT' THEN
+    op := 'create';
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    op := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    op := 'restore';
+  ELSIF diff = '{}'::jsonb THEN
+    RETURN NEW;
+  END IF;
+
+  INSERT INTO task_history (task_id, operation, actor_id, changes)
+  VALUES (NEW.id, op, NULLIF(current_setting('app.actor_id', true), ''), diff);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER tasks_version_trigger ON tasks;
+DROP TRIGGER projects_version_trigger ON projects;
+DROP TRIGGER workspaces_version_trigger ON workspaces;
+DROP FUNCTION bump_row_version();
+ALTER TABLE tasks DROP COLUMN version;
+ALTER TABLE projects DROP COLUMN version;
+ALTER TABLE workspaces DROP COLUMN version;
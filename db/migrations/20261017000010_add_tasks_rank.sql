@@ -0,0 +1,84 @@
+-- migrate:up
+ALTER TABLE tasks ADD COLUMN rank TEXT COLLATE "C";
+
+-- Ranks are fractional-index keys (base-62 digits compared byte-wise) scoped
+-- to a (project, status) column. Seed existing columns in their current
+-- newest-first order with evenly spaced keys; triggers are disabled so the
+-- backfill neither bumps versions nor emits task events.
+ALTER TABLE tasks DISABLE TRIGGER USER;
+UPDATE tasks t SET rank = lpad(r.n::text, 10, '0') || 'V'
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY project_id, status ORDER BY created_at DESC, id DESC) AS n
+    FROM tasks
+) r
+WHERE t.id = r.id;
+ALTER TABLE tasks ENABLE TRIGGER USER;
+
+ALTER TABLE tasks ALTER COLUMN rank SET NOT NULL;
+
+CREATE INDEX idx_tasks_project_status_rank ON tasks (project_id, status, rank) WHERE deleted_at IS NULL;
+
+-- Reordering within a column is not a change worth recording in task history.
+CREATE OR REPLACE FUNCTION record_task_history() RETURNS trigger AS $$
+DECLARE
+  ignored TEXT[] := ARRAY['id', 'workspace_id', 'created_at', 'updated_at', 'version', 'rank', 'search_vector'];
+  old_row JSONB := CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) ELSE '{}'::jsonb END;
+  diff JSONB;
+  op TEXT := 'update';
+BEGIN
+  SELECT COALESCE(jsonb_object_agg(n.key, jsonb_build_object('old', old_row -> n.key, 'new', n.value)), '{}'::jsonb)
+    INTO diff
+  FROM jsonb_each(to_jsonb(NEW)) n
+  WHERE n.key <> ALL (ignored)
+    AND (old_row -> n.key) IS DISTINCT FROM n.value
+    AND NOT (TG_OP = 'INSERT' AND n.value = 'null'::jsonb);
+
+  IF TG_OP = 'INSERT' THEN
+    op := 'create';
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    op := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    op := 'restore';
+  ELSIF diff = '{}'::jsonb THEN
+    RETURN NEW;
+  END IF;
+
+  INSERT INTO task_history (task_id, operation, actor_id, changes)
+  VALUES (NEW.id, op, NULLIF(current_setting('app.actor_id', true), ''), diff);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- migrate:down
+CREATE OR REPLACE FUNCTION record_task_history() RETURNS trigger AS $$
+DECLARE
+  ignored TEXT[] := ARRAY['id', 'workspace_id', 'created_at', 'updated_at', 'version', 'search_vector'];
+  old_row JSONB := CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) ELSE '{}'::jsonb END;
+  diff JSONB;
+  op TEXT := 'update';
+BEGIN
+  SELECT COALESCE(jsonb_object_agg(n.key, jsonb_build_object('old', old_row -> n.key, 'new', n.value)), '{}'::jsonb)
+    INTO diff
+  FROM jsonb_each(to_jsonb(NEW)) n
+  WHERE n.key <> ALL (ignored)
+    AND (old_row -> n.key) IS DISTINCT FROM n.value
+    AND NOT (TG_OP = 'INSERT' AND n.value = 'null'::jsonb);
+
+  IF TG_OP = 'INSERT' THEN
+    op := 'create';
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    op := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    op := 'restore';
+  ELSIF diff = '{}'::jsonb THEN
+    RETURN NEW;
+  END IF;
+
+  INSERT INTO task_history (task_id, operation, actor_id, changes)
+  VALUES (NEW.id, op, NULLIF(current_setting('app.actor_id', true), ''), diff);
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP INDEX idx_tasks_project_status_rank;
+ALTER TABLE tasks DROP COLUMN rank;
@@ -0,0 +1,11 @@
+-- migrate:up
+-- Soft-deleted rows are listed per workspace, most recently deleted first,
+-- and scanned by age when the worker purges expired trash.
+CREATE INDEX idx_workspaces_deleted ON workspaces (deleted_at, id) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_projects_deleted ON projects (workspace_id, deleted_at, id) WHERE deleted_at IS NOT NULL;
+CREATE INDEX idx_tasks_deleted ON tasks (workspace_id, deleted_at, id) WHERE deleted_at IS NOT NULL;
+
+-- migrate:down
+DROP INDEX idx_tasks_deleted;
+DROP INDEX idx_projects_deleted;
+DROP INDEX idx_workspaces_deleted;
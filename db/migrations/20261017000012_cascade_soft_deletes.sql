@@ -0,0 +1,14 @@
+-- migrate:up
+-- Deletes now cascade down the hierarchy. Bring rows left live under an
+-- already deleted parent in line, stamping them with the parent's
+-- deleted_at so they are restored together with it.
+UPDATE projects p SET deleted_at = w.deleted_at, updated_at = NOW()
+FROM workspaces w
+WHERE w.id = p.workspace_id AND w.deleted_at IS NOT NULL AND p.deleted_at IS NULL;
+
+UPDATE tasks t SET deleted_at = p.deleted_at, updated_at = NOW()
+FROM projects p
+WHERE p.id = t.project_id AND p.deleted_at IS NOT NULL AND t.deleted_at IS NULL;
+
+-- migrate:down
+-- The backfill cannot be told apart from deletes made since; nothing to undo.
@@ -0,0 +1,110 @@
+-- migrate:up
+CREATE TABLE task_watchers (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    reason VARCHAR(20) NOT NULL DEFAULT 'manual' CHECK (reason IN ('manual', 'assignee', 'commenter')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE TABLE project_watchers (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (project_id, user_id)
+);
+
+-- NULL for workspace-wide events; set for notifications addressed to one user.
+ALTER TABLE notification_queue ADD COLUMN recipient_id VARCHAR(255);
+
+CREATE INDEX idx_notification_queue_recipient ON notification_queue (workspace_id, recipient_id, created_at DESC)
+    WHERE recipient_id IS NOT NULL;
+
+-- Assignees watch the tasks they are given. Fires before task_history_trigger
+-- (triggers run in name order), so a new assignee hears about the assignment.
+CREATE OR REPLACE FUNCTION watch_task_assignee() RETURNS trigger AS $$
+BEGIN
+  IF NEW.assigned_to IS NOT NULL
+     AND (TG_OP = 'INSERT' OR NEW.assigned_to IS DISTINCT FROM OLD.assigned_to) THEN
+    INSERT INTO task_watchers (task_id, user_id, reason)
+    VALUES (NEW.id, NEW.assigned_to, 'assignee')
+    ON CONFLICT DO NOTHING;
+  END IF;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_assignee_watch_trigger
+  AFTER INSERT OR UPDATE OF assigned_to ON tasks
+  FOR EACH ROW EXECUTE FUNCTION watch_task_assignee();
+
+-- Every task_history row is a change worth telling watchers about: queue one
+-- notification per watcher of the task or its project, except the actor.
+-- Operations are create/update/delete/restore, so the event types are
+-- task.created, task.updated, task.deleted and task.restored.
+CREATE OR REPLACE FUNCTION notify_task_watchers() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO notification_queue (workspace_id, recipient_id, event_type, payload, status, created_at)
+  SELECT t.workspace_id, w.user_id, 'task.' || NEW.operation || 'd',
+         jsonb_build_object('task_id', t.id, 'title', t.title, 'actor_id', NEW.actor_id, 'changes', NEW.changes),
+         'pending', NOW()
+  FROM tasks t
+  JOIN (
+      SELECT user_id FROM task_watchers WHERE task_id = NEW.task_id
+      UNION
+      SELECT pw.user_id FROM project_watchers pw
+      JOIN tasks pt ON pt.project_id = pw.project_id WHERE pt.id = NEW.task_id
+  ) w ON w.user_id IS DISTINCT FROM NEW.actor_id
+  WHERE t.id = NEW.task_id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_history_notify_trigger
+  AFTER INSERT ON task_history
+  FOR EACH ROW EXECUTE FUNCTION notify_task_watchers();
+
+-- Commenters watch the task they comment on, and the task's other watchers
+-- are told about the comment.
+CREATE OR REPLACE FUNCTION notify_task_comment() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO task_watchers (task_id, user_id, reason)
+  VALUES (NEW.task_id, NEW.author_id, 'commenter')
+  ON CONFLICT DO NOTHING;
+
+  INSERT INTO notification_queue (workspace_id, recipient_id, event_type, payload, status, created_at)
+  SELECT t.workspace_id, w.user_id, 'task.commented',
+         jsonb_build_object('task_id', t.id, 'title', t.title, 'comment_id', NEW.id, 'author_id', NEW.author_id),
+         'pending', NOW()
+  FROM tasks t
+  JOIN (
+      SELECT user_id FROM task_watchers WHERE task_id = NEW.task_id
+      UNION
+      SELECT pw.user_id FROM project_watchers pw
+      JOIN tasks pt ON pt.project_id = pw.project_id WHERE pt.id = NEW.task_id
+  ) w ON w.user_id <> NEW.author_id
+  WHERE t.id = NEW.task_id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_comment_notify_trigger
+  AFTER INSERT ON task_comments
+  FOR EACH ROW EXECUTE FUNCTION notify_task_comment();
+
+-- Existing assignees start out watching their tasks.
+INSERT INTO task_watchers (task_id, user_id, reason)
+SELECT id, assigned_to, 'assignee' FROM tasks
+WHERE assigned_to IS NOT NULL AND deleted_at IS NULL;
+
+-- migrate:down
+DROP TRIGGER task_comment_notify_trigger ON task_comments;
+DROP FUNCTION notify_task_comment();
+DROP TRIGGER task_history_notify_trigger ON task_history;
+DROP FUNCTION notify_task_watchers();
+DROP TRIGGER task_assignee_watch_trigger ON tasks;
+DROP FUNCTION watch_task_assignee();
+DROP INDEX idx_notification_queue_recipient;
+ALTER TABLE notification_queue DROP COLUMN recipient_id;
+DROP TABLE project_watchers;
+DROP TABLE task_watchers;
@@ -0,0 +1,28 @@
+-- migrate:up
+-- Estimates are in seconds; NULL means the task has not been estimated.
+ALTER TABLE tasks
+    ADD COLUMN original_estimate_seconds INTEGER CHECK (original_estimate_seconds >= 0),
+    ADD COLUMN remaining_estimate_seconds INTEGER CHECK (remaining_estimate_seconds >= 0);
+
+CREATE TABLE time_entries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    started_at TIMESTAMPTZ NOT NULL,
+    duration_seconds INTEGER NOT NULL CHECK (duration_seconds > 0),
+    note TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A task's entries, and a workspace's entries in a date range (listing and
+-- totals); the user filter is applied to the range scan.
+CREATE INDEX idx_time_entries_task_started ON time_entries (task_id, started_at DESC, id DESC);
+CREATE INDEX idx_time_entries_workspace_started ON time_entries (workspace_id, started_at DESC, id DESC);
+
+-- migrate:down
+DROP TABLE time_entries;
+ALTER TABLE tasks
+    DROP COLUMN remaining_estimate_seconds,
+    DROP COLUMN original_estimate_seconds;
@@ -0,0 +1,16 @@
+-- migrate:up
+-- Typed keys a project's task metadata must follow. Projects without rows
+-- accept any metadata.
+CREATE TABLE project_custom_fields (
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    key VARCHAR(64) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'number', 'date', 'enum', 'user')),
+    required BOOLEAN NOT NULL DEFAULT false,
+    options TEXT[] NOT NULL DEFAULT '{}',
+    position INTEGER NOT NULL,
+    PRIMARY KEY (project_id, key),
+    CHECK ((type = 'enum') = (cardinality(options) > 0))
+);
+
+-- migrate:down
+DROP TABLE project_custom_fields;
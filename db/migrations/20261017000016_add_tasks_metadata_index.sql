@@ -0,0 +1,9 @@
+-- migrate:up
+-- Metadata filters on ListTasks test key presence (?) and containment (@>),
+-- both served by the default jsonb_ops operator class. A substring
+-- "contains" on a string value is not indexable: the index only narrows it
+-- to the tasks that have the key.
+CREATE INDEX idx_tasks_metadata ON tasks USING GIN (metadata) WHERE deleted_at IS NULL;
+
+-- migrate:down
+DROP INDEX idx_tasks_metadata;
@@ -0,0 +1,13 @@
+-- migrate:up
+-- ListTasks date filters within a workspace: due-date ranges, overdue and
+-- undated tasks (IS NULL is an index condition), created ranges and
+-- updated-since. The created_at index also serves the default newest-first
+-- order.
+CREATE INDEX idx_tasks_workspace_due ON tasks (workspace_id, due_date) WHERE deleted_at IS NULL;
+CREATE INDEX idx_tasks_workspace_created ON tasks (workspace_id, created_at DESC) WHERE deleted_at IS NULL;
+CREATE INDEX idx_tasks_workspace_updated ON tasks (workspace_id, updated_at DESC) WHERE deleted_at IS NULL;
+
+-- migrate:down
+DROP INDEX idx_tasks_workspace_updated;
+DROP INDEX idx_tasks_workspace_created;
+DROP INDEX idx_tasks_workspace_due;
@@ -0,0 +1,41 @@
+-- migrate:up
+-- A task may have several assignees, kept in the order they were given.
+-- tasks.assigned_to stays in sync with the first one for older clients.
+CREATE TABLE task_assignees (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id VARCHAR(255) NOT NULL,
+    position INT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+-- Assignee filters look tasks up by user.
+CREATE INDEX idx_task_assignees_user_id ON task_assignees (user_id);
+
+INSERT INTO task_assignees (task_id, user_id, position, created_at)
+SELECT id, assigned_to, 0, created_at FROM tasks
+WHERE assigned_to IS NOT NULL;
+
+-- Filters now go through task_assignees.
+DROP INDEX idx_tasks_assigned_to;
+
+-- Every assignee watches the task, not only the first one (which
+-- task_assignee_watch_trigger already covers).
+CREATE OR REPLACE FUNCTION watch_task_assignees() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO task_watchers (task_id, user_id, reason)
+  VALUES (NEW.task_id, NEW.user_id, 'assignee')
+  ON CONFLICT DO NOTHING;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_assignees_watch_trigger
+  AFTER INSERT ON task_assignees
+  FOR EACH ROW EXECUTE FUNCTION watch_task_assignees();
+
+-- migrate:down
+DROP TRIGGER task_assignees_watch_trigger ON task_assignees;
+DROP FUNCTION watch_task_assignees();
+CREATE INDEX idx_tasks_assigned_to ON tasks (assigned_to) WHERE assigned_to IS NOT NULL;
+DROP TABLE task_assignees;
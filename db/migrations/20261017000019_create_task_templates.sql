@@ -0,0 +1,23 @@
+-- migrate:up
+-- Reusable task blueprints. Title and descriptions may contain {{name}}
+-- placeholders, filled in when the template is instantiated. subtasks is
+-- an array of {title, description, priority, metadata} objects.
+CREATE TABLE task_templates (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workspace_id UUID NOT NULL REFERENCES workspaces(id) ON DELETE CASCADE,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    title VARCHAR(500) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    priority VARCHAR(20) NOT NULL DEFAULT 'medium' CHECK (priority IN ('low', 'medium', 'high', 'critical')),
+    metadata JSONB NOT NULL DEFAULT '{}',
+    subtasks JSONB NOT NULL DEFAULT '[]' CHECK (jsonb_typeof(subtasks) = 'array'),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Names are unique per project, ignoring case; also serves listing by name.
+CREATE UNIQUE INDEX idx_task_templates_project_name ON task_templates (project_id, lower(name));
+
+-- migrate:down
+DROP TABLE task_templates;
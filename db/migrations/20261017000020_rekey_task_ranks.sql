@@ -0,0 +1,93 @@
+-- migrate:up
+-- Rank keys now start with an integer part whose first letter gives its
+-- length ('a' + 1 digit, 'b' + 2 digits, ...), so appending only counts the
+-- integer up instead of growing a fraction. Re-key every column in its
+-- current order as 'd' + 4 base-62 digits; triggers are disabled so the
+-- rewrite neither bumps versions nor emits task events.
+ALTER TABLE tasks DISABLE TRIGGER USER;
+UPDATE tasks t SET rank = 'd'
+    || substr(r.digits, ((r.n / 238328) % 62)::int + 1, 1)
+    || substr(r.digits, ((r.n / 3844) % 62)::int + 1, 1)
+    || substr(r.digits, ((r.n / 62) % 62)::int + 1, 1)
+    || substr(r.digits, (r.n % 62)::int + 1, 1)
+FROM (
+    SELECT id, '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz' AS digits,
+           row_number() OVER (PARTITION BY project_id, status ORDER BY rank, id) - 1 AS n
+    FROM tasks
+) r
+WHERE t.id = r.id;
+ALTER TABLE tasks ENABLE TRIGGER USER;
+
+-- task_rank_after returns the first integer key after a rank, mirroring
+-- rank.After for callers that append outside the Go service.
+CREATE OR REPLACE FUNCTION task_rank_after(key TEXT) RETURNS TEXT AS $$
+DECLARE
+  digits CONSTANT TEXT := '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz';
+  head INT;
+  len INT;
+  int_digits TEXT;
+  d INT;
+BEGIN
+  IF key IS NULL OR key = '' THEN
+    RETURN 'a0';
+  END IF;
+  -- Compare code points: the database collation does not order letters
+  -- the way rank keys do.
+  head := ascii(key);
+  IF head >= ascii('a') THEN
+    len := head - ascii('a') + 1;
+  ELSE
+    len := ascii('Z') - head + 1;
+  END IF;
+  int_digits := substr(key, 2, len);
+
+  FOR i IN REVERSE len..1 LOOP
+    d := strpos(digits, substr(int_digits, i, 1));
+    IF d < 62 THEN
+      RETURN chr(head) || substr(int_digits, 1, i - 1) || substr(digits, d + 1, 1) || repeat('0', len - i);
+    END IF;
+  END LOOP;
+
+  -- Every digit carried over: move to the next length.
+  IF head = ascii('Z') THEN
+    RETURN 'a0';
+  ELSIF head = ascii('z') THEN
+    RAISE EXCEPTION 'rank % has no integer after it', key;
+  ELSIF head >= ascii('a') THEN
+    RETURN chr(head + 1) || repeat('0', len + 1);
+  END IF;
+  RETURN chr(head + 1) || repeat('0', len - 1);
+END;
+$$ LANGUAGE plpgsql IMMUTABLE;
+
+-- Inserts that leave rank out (the TypeScript service) go to the end of
+-- their column. The column's project_statuses row is locked like the Go
+-- service does, so concurrent inserts never share a rank.
+CREATE OR REPLACE FUNCTION set_task_rank() RETURNS trigger AS $$
+BEGIN
+  PERFORM 1 FROM project_statuses
+  WHERE project_id = NEW.project_id AND name = NEW.status
+  FOR UPDATE;
+  NEW.rank := task_rank_after(
+    (SELECT MAX(rank) FROM tasks WHERE project_id = NEW.project_id AND status = NEW.status));
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_rank_trigger
+  BEFORE INSERT ON tasks
+  FOR EACH ROW WHEN (NEW.rank IS NULL) EXECUTE FUNCTION set_task_rank();
+
+-- migrate:down
+DROP TRIGGER task_rank_trigger ON tasks;
+DROP FUNCTION set_task_rank();
+DROP FUNCTION task_rank_after(TEXT);
+
+ALTER TABLE tasks DISABLE TRIGGER USER;
+UPDATE tasks t SET rank = lpad(r.n::text, 10, '0') || 'V'
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY project_id, status ORDER BY rank, id) AS n
+    FROM tasks
+) r
+WHERE t.id = r.id;
+ALTER TABLE tasks ENABLE TRIGGER USER;
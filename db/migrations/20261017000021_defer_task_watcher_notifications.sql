@@ -0,0 +1,15 @@
+-- migrate:up
+-- Fan task history out to watchers when the transaction commits rather than
+-- as each row is written, so watchers subscribed later in the same
+-- transaction, such as the assignees of a new task, hear about it too.
+DROP TRIGGER task_history_notify_trigger ON task_history;
+CREATE CONSTRAINT TRIGGER task_history_notify_trigger
+  AFTER INSERT ON task_history
+  DEFERRABLE INITIALLY DEFERRED
+  FOR EACH ROW EXECUTE FUNCTION notify_task_watchers();
+
+-- migrate:down
+DROP TRIGGER task_history_notify_trigger ON task_history;
+CREATE TRIGGER task_history_notify_trigger
+  AFTER INSERT ON task_history
+  FOR EACH ROW EXECUTE FUNCTION notify_task_watchers();
@@ -0,0 +1,52 @@
+-- migrate:up
+-- Comments copied onto a cloned task are not new: while the transaction-local
+-- setting app.copying_comments is on, they neither subscribe their authors
+-- nor notify the copy's watchers.
+CREATE OR REPLACE FUNCTION notify_task_comment() RETURNS trigger AS $$
+BEGIN
+  IF current_setting('app.copying_comments', true) = 'on' THEN
+    RETURN NEW;
+  END IF;
+
+  INSERT INTO task_watchers (task_id, user_id, reason)
+  VALUES (NEW.task_id, NEW.author_id, 'commenter')
+  ON CONFLICT DO NOTHING;
+
+  INSERT INTO notification_queue (workspace_id, recipient_id, event_type, payload, status, created_at)
+  SELECT t.workspace_id, w.user_id, 'task.commented',
+         jsonb_build_object('task_id', t.id, 'title', t.title, 'comment_id', NEW.id, 'author_id', NEW.author_id),
+         'pending', NOW()
+  FROM tasks t
+  JOIN (
+      SELECT user_id FROM task_watchers WHERE task_id = NEW.task_id
+      UNION
+      SELECT pw.user_id FROM project_watchers pw
+      JOIN tasks pt ON pt.project_id = pw.project_id WHERE pt.id = NEW.task_id
+  ) w ON w.user_id <> NEW.author_id
+  WHERE t.id = NEW.task_id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+-- migrate:down
+CREATE OR REPLACE FUNCTION notify_task_comment() RETURNS trigger AS $$
+BEGIN
+  INSERT INTO task_watchers (task_id, user_id, reason)
+  VALUES (NEW.task_id, NEW.author_id, 'commenter')
+  ON CONFLICT DO NOTHING;
+
+  INSERT INTO notification_queue (workspace_id, recipient_id, event_type, payload, status, created_at)
+  SELECT t.workspace_id, w.user_id, 'task.commented',
+         jsonb_build_object('task_id', t.id, 'title', t.title, 'comment_id', NEW.id, 'author_id', NEW.author_id),
+         'pending', NOW()
+  FROM tasks t
+  JOIN (
+      SELECT user_id FROM task_watchers WHERE task_id = NEW.task_id
+      UNION
+      SELECT pw.user_id FROM project_watchers pw
+      JOIN tasks pt ON pt.project_id = pw.project_id WHERE pt.id = NEW.task_id
+  ) w ON w.user_id <> NEW.author_id
+  WHERE t.id = NEW.task_id;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,8 @@
+-- migrate:up
+-- The trash purge deletes the queued notifications of purged tasks by
+-- payload->>'task_id'; index it so a purge run does not scan the whole queue.
+CREATE INDEX idx_notification_queue_task ON notification_queue ((payload->>'task_id'))
+  WHERE payload->>'task_id' IS NOT NULL;
+
+-- migrate:down
+DROP INDEX idx_notification_queue_task;
@@ -0,0 +1,56 @@
+-- migrate:up
+-- The TypeScript and Kotlin services only write tasks.assigned_to. Mirror
+-- those writes into task_assignees so assignee filters and lists see them:
+-- clearing assigned_to removes every assignee, and a new value replaces the
+-- first one. The Go service writes task_assignees first, so its own updates
+-- find them already in step.
+CREATE OR REPLACE FUNCTION sync_task_assignees() RETURNS trigger AS $$
+DECLARE
+  first VARCHAR(255);
+BEGIN
+  IF NEW.assigned_to IS NULL THEN
+    DELETE FROM task_assignees WHERE task_id = NEW.id;
+    RETURN NEW;
+  END IF;
+  SELECT user_id INTO first FROM task_assignees
+  WHERE task_id = NEW.id ORDER BY position LIMIT 1;
+  IF first IS NOT DISTINCT FROM NEW.assigned_to THEN
+    RETURN NEW;
+  END IF;
+
+  -- A writer that only knows assigned_to replaced the first assignee.
+  DELETE FROM task_assignees WHERE task_id = NEW.id AND user_id = first;
+  INSERT INTO task_assignees (task_id, user_id, position, created_at)
+  SELECT NEW.id, NEW.assigned_to, COALESCE(MIN(position), 1) - 1, NOW()
+  FROM task_assignees WHERE task_id = NEW.id
+  ON CONFLICT (task_id, user_id) DO UPDATE SET position = EXCLUDED.position;
+  RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER task_assigned_to_sync_trigger
+  AFTER INSERT OR UPDATE OF assigned_to ON tasks
+  FOR EACH ROW EXECUTE FUNCTION sync_task_assignees();
+
+-- Catch up with what those services wrote since task_assignees was created.
+DELETE FROM task_assignees ta USING tasks t
+WHERE ta.task_id = t.id
+  AND (t.assigned_to IS NULL
+       OR (ta.user_id <> t.assigned_to
+           AND ta.position = (SELECT MIN(f.position) FROM task_assignees f WHERE f.task_id = t.id)));
+INSERT INTO task_assignees (task_id, user_id, position, created_at)
+SELECT t.id, t.assigned_to,
+       COALESCE((SELECT MIN(ta.position) FROM task_assignees ta WHERE ta.task_id = t.id), 1) - 1, NOW()
+FROM tasks t
+WHERE t.assigned_to IS NOT NULL
+  AND t.assigned_to IS DISTINCT FROM
+      (SELECT ta.user_id FROM task_assignees ta WHERE ta.task_id = t.id ORDER BY ta.position LIMIT 1)
+ON CONFLICT (task_id, user_id) DO UPDATE SET position = EXCLUDED.position;
+
+-- Those services still filter on assigned_to.
+CREATE INDEX idx_tasks_assigned_to ON tasks (assigned_to) WHERE assigned_to IS NOT NULL;
+
+-- migrate:down
+DROP INDEX idx_tasks_assigned_to;
+DROP TRIGGER task_assigned_to_sync_trigger ON tasks;
+DROP FUNCTION sync_task_assignees();
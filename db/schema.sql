@@ -29,6 +29,53 @@ SET row_security = off;
 COMMENT ON SCHEMA public IS '';
 
 
+--
+-- Name: bump_row_version(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.bump_row_version() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  NEW.version := OLD.version + 1;
+  RETURN NEW;
+END;
+$$;
+
+
+--
+-- Name: notify_task_comment(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.notify_task_comment() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  IF current_setting('app.copying_comments', true) = 'on' THEN
+    RETURN NEW;
+  END IF;
+
+  INSERT INTO task_watchers (task_id, user_id, reason)
+  VALUES (NEW.task_id, NEW.author_id, 'commenter')
+  ON CONFLICT DO NOTHING;
+
+  INSERT INTO notification_queue (workspace_id, recipient_id, event_type, payload, status, created_at)
+  SELECT t.workspace_id, w.user_id, 'task.commented',
+         jsonb_build_object('task_id', t.id, 'title', t.title, 'comment_id', NEW.id, 'author_id', NEW.author_id),
+         'pending', NOW()
+  FROM tasks t
+  JOIN (
+      SELECT user_id FROM task_watchers WHERE task_id = NEW.task_id
+      UNION
+      SELECT pw.user_id FROM project_watchers pw
+      JOIN tasks pt ON pt.project_id = pw.project_id WHERE pt.id = NEW.task_id
+  ) w ON w.user_id <> NEW.author_id
+  WHERE t.id = NEW.task_id;
+  RETURN NEW;
+END;
+$$;
+
+
 --
 -- Name: notify_task_event(); Type: FUNCTION; Schema: public; Owner: -
 --
@@ -49,331 +96,1175 @@ END;
 $$;
 
 
-SET default_tablespace = '';
+--
+-- Name: notify_task_watchers(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.notify_task_watchers() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO notification_queue (workspace_id, recipient_id, event_type, payload, status, created_at)
+  SELECT t.workspace_id, w.user_id, 'task.' || NEW.operation || 'd',
+         jsonb_build_object('task_id', t.id, 'title', t.title, 'actor_id', NEW.actor_id, 'changes', NEW.changes),
+         'pending', NOW()
+  FROM tasks t
+  JOIN (
+      SELECT user_id FROM task_watchers WHERE task_id = NEW.task_id
+      UNION
+      SELECT pw.user_id FROM project_watchers pw
+      JOIN tasks pt ON pt.project_id = pw.project_id WHERE pt.id = NEW.task_id
+  ) w ON w.user_id IS DISTINCT FROM NEW.actor_id
+  WHERE t.id = NEW.task_id;
+  RETURN NEW;
+END;
+$$;
+
+
+--
+-- Name: record_task_history(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.record_task_history() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+  ignored TEXT[] := ARRAY['id', 'workspace_id', 'created_at', 'updated_at', 'version', 'rank', 'search_vector'];
+  old_row JSONB := CASE WHEN TG_OP = 'UPDATE' THEN to_jsonb(OLD) ELSE '{}'::jsonb END;
+  diff JSONB;
+  op TEXT := 'update';
+BEGIN
+  SELECT COALESCE(jsonb_object_agg(n.key, jsonb_build_object('old', old_row -> n.key, 'new', n.value)), '{}'::jsonb)
+    INTO diff
+  FROM jsonb_each(to_jsonb(NEW)) n
+  WHERE n.key <> ALL (ignored)
+    AND (old_row -> n.key) IS DISTINCT FROM n.value
+    AND NOT (TG_OP = 'INSERT' AND n.value = 'null'::jsonb);
+
+  IF TG_OP = 'INSERT' THEN
+    op := 'create';
+  ELSIF OLD.deleted_at IS NULL AND NEW.deleted_at IS NOT NULL THEN
+    op := 'delete';
+  ELSIF OLD.deleted_at IS NOT NULL AND NEW.deleted_at IS NULL THEN
+    op := 'restore';
+  ELSIF diff = '{}'::jsonb THEN
+    RETURN NEW;
+  END IF;
+
+  INSERT INTO task_history (task_id, operation, actor_id, changes)
+  VALUES (NEW.id, op, NULLIF(current_setting('app.actor_id', true), ''), diff);
+  RETURN NEW;
+END;
+$$;
+
+
+--
+-- Name: seed_project_workflow(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.seed_project_workflow() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO project_statuses (project_id, name, position, is_done) VALUES
+    (NEW.id, 'todo', 0, FALSE),
+    (NEW.id, 'in_progress', 1, FALSE),
+    (NEW.id, 'review', 2, FALSE),
+    (NEW.id, 'done', 3, TRUE);
+  INSERT INTO project_status_transitions (project_id, from_status, to_status)
+  SELECT f.project_id, f.name, t.name
+  FROM project_statuses f
+  JOIN project_statuses t ON t.project_id = f.project_id AND t.name <> f.name
+  WHERE f.project_id = NEW.id;
+  RETURN NEW;
+END;
+$$;
+
+
+SET default_tablespace = '';
+
+SET default_table_access_method = heap;
+
+--
+-- Name: set_task_rank(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.set_task_rank() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  PERFORM 1 FROM project_statuses
+  WHERE project_id = NEW.project_id AND name = NEW.status
+  FOR UPDATE;
+  NEW.rank := task_rank_after(
+    (SELECT MAX(rank) FROM tasks WHERE project_id = NEW.project_id AND status = NEW.status));
+  RETURN NEW;
+END;
+$$;
+
+
+--
+-- Name: sync_task_assignees(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.sync_task_assignees() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+DECLARE
+  first VARCHAR(255);
+BEGIN
+  IF NEW.assigned_to IS NULL THEN
+    DELETE FROM task_assignees WHERE task_id = NEW.id;
+    RETURN NEW;
+  END IF;
+  SELECT user_id INTO first FROM task_assignees
+  WHERE task_id = NEW.id ORDER BY position LIMIT 1;
+  IF first IS NOT DISTINCT FROM NEW.assigned_to THEN
+    RETURN NEW;
+  END IF;
+
+  -- A writer that only knows assigned_to replaced the first assignee.
+  DELETE FROM task_assignees WHERE task_id = NEW.id AND user_id = first;
+  INSERT INTO task_assignees (task_id, user_id, position, created_at)
+  SELECT NEW.id, NEW.assigned_to, COALESCE(MIN(position), 1) - 1, NOW()
+  FROM task_assignees WHERE task_id = NEW.id
+  ON CONFLICT (task_id, user_id) DO UPDATE SET position = EXCLUDED.position;
+  RETURN NEW;
+END;
+$$;
+
+
+--
+-- Name: task_rank_after(text); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.task_rank_after(key text) RETURNS text
+    LANGUAGE plpgsql IMMUTABLE
+    AS $$
+DECLARE
+  digits CONSTANT TEXT := '0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz';
+  head INT;
+  len INT;
+  int_digits TEXT;
+  d INT;
+BEGIN
+  IF key IS NULL OR key = '' THEN
+    RETURN 'a0';
+  END IF;
+  -- Compare code points: the database collation does not order letters
+  -- the way rank keys do.
+  head := ascii(key);
+  IF head >= ascii('a') THEN
+    len := head - ascii('a') + 1;
+  ELSE
+    len := ascii('Z') - head + 1;
+  END IF;
+  int_digits := substr(key, 2, len);
+
+  FOR i IN REVERSE len..1 LOOP
+    d := strpos(digits, substr(int_digits, i, 1));
+    IF d < 62 THEN
+      RETURN chr(head) || substr(int_digits, 1, i - 1) || substr(digits, d + 1, 1) || repeat('0', len - i);
+    END IF;
+  END LOOP;
+
+  -- Every digit carried over: move to the next length.
+  IF head = ascii('Z') THEN
+    RETURN 'a0';
+  ELSIF head = ascii('z') THEN
+    RAISE EXCEPTION 'rank % has no integer after it', key;
+  ELSIF head >= ascii('a') THEN
+    RETURN chr(head + 1) || repeat('0', len + 1);
+  END IF;
+  RETURN chr(head + 1) || repeat('0', len - 1);
+END;
+$$;
+
+
+--
+-- Name: watch_task_assignee(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.watch_task_assignee() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  IF NEW.assigned_to IS NOT NULL
+     AND (TG_OP = 'INSERT' OR NEW.assigned_to IS DISTINCT FROM OLD.assigned_to) THEN
+    INSERT INTO task_watchers (task_id, user_id, reason)
+    VALUES (NEW.id, NEW.assigned_to, 'assignee')
+    ON CONFLICT DO NOTHING;
+  END IF;
+  RETURN NEW;
+END;
+$$;
+
+
+--
+-- Name: watch_task_assignees(); Type: FUNCTION; Schema: public; Owner: -
+--
+
+CREATE FUNCTION public.watch_task_assignees() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO task_watchers (task_id, user_id, reason)
+  VALUES (NEW.task_id, NEW.user_id, 'assignee')
+  ON CONFLICT DO NOTHING;
+  RETURN NEW;
+END;
+$$;
+
+
+--
+-- Name: attachments; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.attachments (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    task_id uuid NOT NULL,
+    file_url text NOT NULL,
+    file_name character varying(500) NOT NULL,
+    file_size bigint NOT NULL,
+    uploaded_by character varying(255) NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: labels; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.labels (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    workspace_id uuid NOT NULL,
+    name character varying(100) NOT NULL,
+    color character varying(7) DEFAULT '#808080'::character varying NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT labels_color_check CHECK (((color)::text ~ '^#[0-9a-fA-F]{6}$'::text))
+);
+
+
+--
+-- Name: notification_queue; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.notification_queue (
+    id bigint NOT NULL,
+    workspace_id uuid NOT NULL,
+    event_type character varying(100) NOT NULL,
+    payload jsonb NOT NULL,
+    status character varying(20) DEFAULT 'pending'::character varying NOT NULL,
+    retry_count integer DEFAULT 0 NOT NULL,
+    max_retries integer DEFAULT 3 NOT NULL,
+    next_retry_at timestamp with time zone,
+    last_error text,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    processed_at timestamp with time zone,
+    recipient_id character varying(255),
+    CONSTRAINT notification_queue_status_check CHECK (((status)::text = ANY ((ARRAY['pending'::character varying, 'processing'::character varying, 'processed'::character varying, 'failed'::character varying])::text[])))
+);
+
+
+--
+-- Name: notification_queue_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+--
+
+CREATE SEQUENCE public.notification_queue_id_seq
+    START WITH 1
+    INCREMENT BY 1
+    NO MINVALUE
+    NO MAXVALUE
+    CACHE 1;
+
+
+--
+-- Name: notification_queue_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+--
+
+ALTER SEQUENCE public.notification_queue_id_seq OWNED BY public.notification_queue.id;
+
+
+--
+-- Name: project_custom_fields; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.project_custom_fields (
+    project_id uuid NOT NULL,
+    key character varying(64) NOT NULL,
+    type character varying(20) NOT NULL,
+    required boolean DEFAULT false NOT NULL,
+    options text[] DEFAULT '{}'::text[] NOT NULL,
+    "position" integer NOT NULL,
+    CONSTRAINT project_custom_fields_check CHECK ((((type)::text = 'enum'::text) = (cardinality(options) > 0))),
+    CONSTRAINT project_custom_fields_type_check CHECK (((type)::text = ANY ((ARRAY['text'::character varying, 'number'::character varying, 'date'::character varying, 'enum'::character varying, 'user'::character varying])::text[])))
+);
+
+
+--
+-- Name: project_status_transitions; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.project_status_transitions (
+    project_id uuid NOT NULL,
+    from_status character varying(20) NOT NULL,
+    to_status character varying(20) NOT NULL
+);
+
+
+--
+-- Name: project_statuses; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.project_statuses (
+    project_id uuid NOT NULL,
+    name character varying(20) NOT NULL,
+    "position" integer NOT NULL,
+    is_done boolean DEFAULT false NOT NULL
+);
+
+
+--
+-- Name: project_watchers; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.project_watchers (
+    project_id uuid NOT NULL,
+    user_id character varying(255) NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: projects; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.projects (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    workspace_id uuid NOT NULL,
+    name character varying(255) NOT NULL,
+    description text,
+    status character varying(20) DEFAULT 'active'::character varying NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    deleted_at timestamp with time zone,
+    version integer DEFAULT 1 NOT NULL,
+    CONSTRAINT projects_status_check CHECK (((status)::text = ANY ((ARRAY['active'::character varying, 'archived'::character varying])::text[])))
+);
+
+
+--
+-- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.schema_migrations (
+    version character varying NOT NULL
+);
+
+
+--
+-- Name: task_assignees; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.task_assignees (
+    task_id uuid NOT NULL,
+    user_id character varying(255) NOT NULL,
+    "position" integer NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: task_comments; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.task_comments (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    task_id uuid NOT NULL,
+    author_id character varying(255) NOT NULL,
+    content text NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    search_vector tsvector GENERATED ALWAYS AS (setweight(to_tsvector('english'::regconfig, content), 'B'::"char")) STORED
+);
+
+
+--
+-- Name: task_dependencies; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.task_dependencies (
+    blocker_task_id uuid NOT NULL,
+    blocked_task_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT task_dependencies_check CHECK ((blocker_task_id <> blocked_task_id))
+);
+
+
+--
+-- Name: task_due_notifications; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.task_due_notifications (
+    task_id uuid NOT NULL,
+    event_type character varying(100) NOT NULL,
+    threshold_hours integer NOT NULL,
+    due_date date NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT task_due_notifications_event_type_check CHECK (((event_type)::text = ANY ((ARRAY['task.due_soon'::character varying, 'task.overdue'::character varying])::text[])))
+);
+
+
+--
+-- Name: task_history; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.task_history (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    task_id uuid NOT NULL,
+    operation character varying(20) NOT NULL,
+    actor_id character varying(255),
+    changes jsonb DEFAULT '{}'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT clock_timestamp() NOT NULL,
+    CONSTRAINT task_history_operation_check CHECK (((operation)::text = ANY ((ARRAY['create'::character varying, 'update'::character varying, 'delete'::character varying, 'restore'::character varying])::text[])))
+);
+
+
+--
+-- Name: task_labels; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.task_labels (
+    task_id uuid NOT NULL,
+    label_id uuid NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: task_recurrences; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.task_recurrences (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    task_id uuid NOT NULL,
+    rrule text NOT NULL,
+    dtstart date NOT NULL,
+    occurrence integer DEFAULT 1 NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT task_recurrences_occurrence_check CHECK ((occurrence > 0))
+);
+
+
+--
+-- Name: task_templates; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.task_templates (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    workspace_id uuid NOT NULL,
+    project_id uuid NOT NULL,
+    name character varying(255) NOT NULL,
+    title character varying(500) NOT NULL,
+    description text DEFAULT ''::text NOT NULL,
+    priority character varying(20) DEFAULT 'medium'::character varying NOT NULL,
+    metadata jsonb DEFAULT '{}'::jsonb NOT NULL,
+    subtasks jsonb DEFAULT '[]'::jsonb NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT task_templates_priority_check CHECK (((priority)::text = ANY ((ARRAY['low'::character varying, 'medium'::character varying, 'high'::character varying, 'critical'::character varying])::text[]))),
+    CONSTRAINT task_templates_subtasks_check CHECK ((jsonb_typeof(subtasks) = 'array'::text))
+);
+
+
+--
+-- Name: task_watchers; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.task_watchers (
+    task_id uuid NOT NULL,
+    user_id character varying(255) NOT NULL,
+    reason character varying(20) DEFAULT 'manual'::character varying NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT task_watchers_reason_check CHECK (((reason)::text = ANY ((ARRAY['manual'::character varying, 'assignee'::character varying, 'commenter'::character varying])::text[])))
+);
+
+
+--
+-- Name: tasks; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.tasks (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    workspace_id uuid NOT NULL,
+    project_id uuid NOT NULL,
+    title character varying(500) NOT NULL,
+    description text,
+    status character varying(20) DEFAULT 'todo'::character varying NOT NULL,
+    priority character varying(20) DEFAULT 'medium'::character varying NOT NULL,
+    assigned_to character varying(255),
+    due_date date,
+    metadata jsonb DEFAULT '{}'::jsonb,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    deleted_at timestamp with time zone,
+    parent_task_id uuid,
+    search_vector tsvector GENERATED ALWAYS AS ((setweight(to_tsvector('english'::regconfig, (COALESCE(title, ''::character varying))::text), 'A'::"char") || setweight(to_tsvector('english'::regconfig, COALESCE(description, ''::text)), 'B'::"char"))) STORED,
+    version integer DEFAULT 1 NOT NULL,
+    rank text COLLATE pg_catalog."C" NOT NULL,
+    original_estimate_seconds integer,
+    remaining_estimate_seconds integer,
+    CONSTRAINT tasks_original_estimate_seconds_check CHECK ((original_estimate_seconds >= 0)),
+    CONSTRAINT tasks_priority_check CHECK (((priority)::text = ANY ((ARRAY['low'::character varying, 'medium'::character varying, 'high'::character varying, 'critical'::character varying])::text[]))),
+    CONSTRAINT tasks_remaining_estimate_seconds_check CHECK ((remaining_estimate_seconds >= 0))
+);
+
+
+--
+-- Name: time_entries; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.time_entries (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    task_id uuid NOT NULL,
+    workspace_id uuid NOT NULL,
+    user_id character varying(255) NOT NULL,
+    started_at timestamp with time zone NOT NULL,
+    duration_seconds integer NOT NULL,
+    note text DEFAULT ''::text NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    CONSTRAINT time_entries_duration_seconds_check CHECK ((duration_seconds > 0))
+);
+
+
+--
+-- Name: workspace_reminder_settings; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.workspace_reminder_settings (
+    workspace_id uuid NOT NULL,
+    due_soon_hours integer[] DEFAULT '{24}'::integer[] NOT NULL,
+    overdue_hours integer[] DEFAULT '{0}'::integer[] NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL
+);
+
+
+--
+-- Name: workspaces; Type: TABLE; Schema: public; Owner: -
+--
+
+CREATE TABLE public.workspaces (
+    id uuid DEFAULT gen_random_uuid() NOT NULL,
+    name character varying(255) NOT NULL,
+    slug character varying(255) NOT NULL,
+    created_at timestamp with time zone DEFAULT now() NOT NULL,
+    updated_at timestamp with time zone DEFAULT now() NOT NULL,
+    deleted_at timestamp with time zone,
+    version integer DEFAULT 1 NOT NULL
+);
+
+
+--
+-- Name: notification_queue id; Type: DEFAULT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification_queue ALTER COLUMN id SET DEFAULT nextval('public.notification_queue_id_seq'::regclass);
+
+
+--
+-- Name: attachments attachments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.attachments
+    ADD CONSTRAINT attachments_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: labels labels_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.labels
+    ADD CONSTRAINT labels_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: notification_queue notification_queue_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.notification_queue
+    ADD CONSTRAINT notification_queue_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: project_custom_fields project_custom_fields_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.project_custom_fields
+    ADD CONSTRAINT project_custom_fields_pkey PRIMARY KEY (project_id, key);
+
+
+--
+-- Name: project_status_transitions project_status_transitions_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.project_status_transitions
+    ADD CONSTRAINT project_status_transitions_pkey PRIMARY KEY (project_id, from_status, to_status);
+
+
+--
+-- Name: project_statuses project_statuses_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.project_statuses
+    ADD CONSTRAINT project_statuses_pkey PRIMARY KEY (project_id, name);
+
+
+--
+-- Name: project_watchers project_watchers_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.project_watchers
+    ADD CONSTRAINT project_watchers_pkey PRIMARY KEY (project_id, user_id);
+
+
+--
+-- Name: projects projects_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.projects
+    ADD CONSTRAINT projects_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.schema_migrations
+    ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
+
+
+--
+-- Name: task_assignees task_assignees_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_assignees
+    ADD CONSTRAINT task_assignees_pkey PRIMARY KEY (task_id, user_id);
+
+
+--
+-- Name: task_comments task_comments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_comments
+    ADD CONSTRAINT task_comments_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: task_dependencies task_dependencies_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_dependencies
+    ADD CONSTRAINT task_dependencies_pkey PRIMARY KEY (blocker_task_id, blocked_task_id);
+
+
+--
+-- Name: task_due_notifications task_due_notifications_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_due_notifications
+    ADD CONSTRAINT task_due_notifications_pkey PRIMARY KEY (task_id, event_type, threshold_hours, due_date);
+
+
+--
+-- Name: task_history task_history_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_history
+    ADD CONSTRAINT task_history_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: task_labels task_labels_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_labels
+    ADD CONSTRAINT task_labels_pkey PRIMARY KEY (task_id, label_id);
+
+
+--
+-- Name: task_recurrences task_recurrences_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_recurrences
+    ADD CONSTRAINT task_recurrences_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: task_recurrences task_recurrences_task_id_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_recurrences
+    ADD CONSTRAINT task_recurrences_task_id_key UNIQUE (task_id);
+
+
+--
+-- Name: task_templates task_templates_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_templates
+    ADD CONSTRAINT task_templates_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: task_watchers task_watchers_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_watchers
+    ADD CONSTRAINT task_watchers_pkey PRIMARY KEY (task_id, user_id);
+
+
+--
+-- Name: tasks tasks_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tasks
+    ADD CONSTRAINT tasks_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: time_entries time_entries_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.time_entries
+    ADD CONSTRAINT time_entries_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: workspace_reminder_settings workspace_reminder_settings_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.workspace_reminder_settings
+    ADD CONSTRAINT workspace_reminder_settings_pkey PRIMARY KEY (workspace_id);
+
+
+--
+-- Name: workspaces workspaces_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.workspaces
+    ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
+
+
+--
+-- Name: workspaces workspaces_slug_key; Type: CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.workspaces
+    ADD CONSTRAINT workspaces_slug_key UNIQUE (slug);
+
+
+--
+-- Name: idx_attachments_task_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_attachments_task_id ON public.attachments USING btree (task_id);
+
+
+--
+-- Name: idx_labels_workspace_name; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_labels_workspace_name ON public.labels USING btree (workspace_id, lower((name)::text));
+
+
+--
+-- Name: idx_notification_queue_actionable; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notification_queue_actionable ON public.notification_queue USING btree (status, next_retry_at) WHERE ((status)::text = ANY ((ARRAY['pending'::character varying, 'failed'::character varying])::text[]));
+
+
+--
+-- Name: idx_notification_queue_created_at; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notification_queue_created_at ON public.notification_queue USING btree (created_at);
+
+
+--
+-- Name: idx_notification_queue_recipient; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notification_queue_recipient ON public.notification_queue USING btree (workspace_id, recipient_id, created_at DESC) WHERE (recipient_id IS NOT NULL);
+
+
+--
+-- Name: idx_notification_queue_task; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notification_queue_task ON public.notification_queue USING btree (((payload ->> 'task_id'::text))) WHERE ((payload ->> 'task_id'::text) IS NOT NULL);
+
+
+--
+-- Name: idx_notification_queue_workspace_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_notification_queue_workspace_id ON public.notification_queue USING btree (workspace_id);
+
+
+--
+-- Name: idx_projects_deleted; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_projects_deleted ON public.projects USING btree (workspace_id, deleted_at, id) WHERE (deleted_at IS NOT NULL);
+
+
+--
+-- Name: idx_projects_not_deleted; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_projects_not_deleted ON public.projects USING btree (id) WHERE (deleted_at IS NULL);
+
+
+--
+-- Name: idx_projects_workspace_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_projects_workspace_id ON public.projects USING btree (workspace_id);
+
+
+--
+-- Name: idx_task_assignees_user_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_task_assignees_user_id ON public.task_assignees USING btree (user_id);
+
+
+--
+-- Name: idx_task_comments_search_vector; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_task_comments_search_vector ON public.task_comments USING gin (search_vector);
+
+
+--
+-- Name: idx_task_comments_task_created; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_task_comments_task_created ON public.task_comments USING btree (task_id, created_at);
+
+
+--
+-- Name: idx_task_comments_task_id; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_task_comments_task_id ON public.task_comments USING btree (task_id);
+
+
+--
+-- Name: idx_task_dependencies_blocked; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_task_dependencies_blocked ON public.task_dependencies USING btree (blocked_task_id);
+
+
+--
+-- Name: idx_task_history_task_created; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_task_history_task_created ON public.task_history USING btree (task_id, created_at DESC, id DESC);
+
+
+--
+-- Name: idx_task_labels_label_task; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_task_labels_label_task ON public.task_labels USING btree (label_id, task_id);
+
+
+--
+-- Name: idx_task_templates_project_name; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE UNIQUE INDEX idx_task_templates_project_name ON public.task_templates USING btree (project_id, lower((name)::text));
+
+
+--
+-- Name: idx_tasks_assigned_to; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_tasks_assigned_to ON public.tasks USING btree (assigned_to) WHERE (assigned_to IS NOT NULL);
+
+
+--
+-- Name: idx_tasks_deleted; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_tasks_deleted ON public.tasks USING btree (workspace_id, deleted_at, id) WHERE (deleted_at IS NOT NULL);
+
+
+--
+-- Name: idx_tasks_due_date; Type: INDEX; Schema: public; Owner: -
+--
+
+CREATE INDEX idx_tasks_due_date ON public.tasks USING btree (due_date) WHERE ((deleted_at IS NULL) AND (due_date IS NOT NULL));
 
-SET default_table_access_method = heap;
 
 --
--- Name: attachments; Type: TABLE; Schema: public; Owner: -
+-- Name: idx_tasks_metadata; Type: INDEX; Schema: public; Owner: -
 --
 
-CREATE TABLE public.attachments (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    task_id uuid NOT NULL,
-    file_url text NOT NULL,
-    file_name character varying(500) NOT NULL,
-    file_size bigint NOT NULL,
-    uploaded_by character varying(255) NOT NULL,
-    created_at timestamp with time zone DEFAULT now() NOT NULL
-);
+CREATE INDEX idx_tasks_metadata ON public.tasks USING gin (metadata) WHERE (deleted_at IS NULL);
 
 
 --
--- Name: notification_queue; Type: TABLE; Schema: public; Owner: -
+-- Name: idx_tasks_not_deleted; Type: INDEX; Schema: public; Owner: -
 --
 
-CREATE TABLE public.notification_queue (
-    id bigint NOT NULL,
-    workspace_id uuid NOT NULL,
-    event_type character varying(100) NOT NULL,
-    payload jsonb NOT NULL,
-    status character varying(20) DEFAULT 'pending'::character varying NOT NULL,
-    retry_count integer DEFAULT 0 NOT NULL,
-    max_retries integer DEFAULT 3 NOT NULL,
-    next_retry_at timestamp with time zone,
-    last_error text,
-    created_at timestamp with time zone DEFAULT now() NOT NULL,
-    processed_at timestamp with time zone,
-    CONSTRAINT notification_queue_status_check CHECK (((status)::text = ANY ((ARRAY['pending'::character varying, 'processing'::character varying, 'processed'::character varying, 'failed'::character varying])::text[])))
-);
+CREATE INDEX idx_tasks_not_deleted ON public.tasks USING btree (id) WHERE (deleted_at IS NULL);
 
 
 --
--- Name: notification_queue_id_seq; Type: SEQUENCE; Schema: public; Owner: -
+-- Name: idx_tasks_parent_task_id; Type: INDEX; Schema: public; Owner: -
 --
 
-CREATE SEQUENCE public.notification_queue_id_seq
-    START WITH 1
-    INCREMENT BY 1
-    NO MINVALUE
-    NO MAXVALUE
-    CACHE 1;
+CREATE INDEX idx_tasks_parent_task_id ON public.tasks USING btree (parent_task_id) WHERE (parent_task_id IS NOT NULL);
 
 
 --
--- Name: notification_queue_id_seq; Type: SEQUENCE OWNED BY; Schema: public; Owner: -
+-- Name: idx_tasks_project_id; Type: INDEX; Schema: public; Owner: -
 --
 
-ALTER SEQUENCE public.notification_queue_id_seq OWNED BY public.notification_queue.id;
+CREATE INDEX idx_tasks_project_id ON public.tasks USING btree (project_id);
 
 
 --
--- Name: projects; Type: TABLE; Schema: public; Owner: -
+-- Name: idx_tasks_project_status_rank; Type: INDEX; Schema: public; Owner: -
 --
 
-CREATE TABLE public.projects (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    workspace_id uuid NOT NULL,
-    name character varying(255) NOT NULL,
-    description text,
-    status character varying(20) DEFAULT 'active'::character varying NOT NULL,
-    created_at timestamp with time zone DEFAULT now() NOT NULL,
-    updated_at timestamp with time zone DEFAULT now() NOT NULL,
-    deleted_at timestamp with time zone,
-    CONSTRAINT projects_status_check CHECK (((status)::text = ANY ((ARRAY['active'::character varying, 'archived'::character varying])::text[])))
-);
+CREATE INDEX idx_tasks_project_status_rank ON public.tasks USING btree (project_id, status, rank) WHERE (deleted_at IS NULL);
 
 
 --
--- Name: schema_migrations; Type: TABLE; Schema: public; Owner: -
+-- Name: idx_tasks_search_vector; Type: INDEX; Schema: public; Owner: -
 --
 
-CREATE TABLE public.schema_migrations (
-    version character varying NOT NULL
-);
+CREATE INDEX idx_tasks_search_vector ON public.tasks USING gin (search_vector);
 
 
 --
--- Name: task_comments; Type: TABLE; Schema: public; Owner: -
+-- Name: idx_tasks_status_created; Type: INDEX; Schema: public; Owner: -
 --
 
-CREATE TABLE public.task_comments (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    task_id uuid NOT NULL,
-    author_id character varying(255) NOT NULL,
-    content text NOT NULL,
-    created_at timestamp with time zone DEFAULT now() NOT NULL,
-    updated_at timestamp with time zone DEFAULT now() NOT NULL
-);
+CREATE INDEX idx_tasks_status_created ON public.tasks USING btree (status, created_at DESC);
 
 
 --
--- Name: tasks; Type: TABLE; Schema: public; Owner: -
+-- Name: idx_tasks_workspace_created; Type: INDEX; Schema: public; Owner: -
 --
 
-CREATE TABLE public.tasks (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    workspace_id uuid NOT NULL,
-    project_id uuid NOT NULL,
-    title character varying(500) NOT NULL,
-    description text,
-    status character varying(20) DEFAULT 'todo'::character varying NOT NULL,
-    priority character varying(20) DEFAULT 'medium'::character varying NOT NULL,
-    assigned_to character varying(255),
-    due_date date,
-    metadata jsonb DEFAULT '{}'::jsonb,
-    created_at timestamp with time zone DEFAULT now() NOT NULL,
-    updated_at timestamp with time zone DEFAULT now() NOT NULL,
-    deleted_at timestamp with time zone,
-    CONSTRAINT tasks_priority_check CHECK (((priority)::text = ANY ((ARRAY['low'::character varying, 'medium'::character varying, 'high'::character varying, 'critical'::character varying])::text[]))),
-    CONSTRAINT tasks_status_check CHECK (((status)::text = ANY ((ARRAY['todo'::character varying, 'in_progress'::character varying, 'review'::character varying, 'done'::character varying])::text[])))
-);
+CREATE INDEX idx_tasks_workspace_created ON public.tasks USING btree (workspace_id, created_at DESC) WHERE (deleted_at IS NULL);
 
 
 --
--- Name: workspaces; Type: TABLE; Schema: public; Owner: -
+-- Name: idx_tasks_workspace_due; Type: INDEX; Schema: public; Owner: -
 --
 
-CREATE TABLE public.workspaces (
-    id uuid DEFAULT gen_random_uuid() NOT NULL,
-    name character varying(255) NOT NULL,
-    slug character varying(255) NOT NULL,
-    created_at timestamp with time zone DEFAULT now() NOT NULL,
-    updated_at timestamp with time zone DEFAULT now() NOT NULL,
-    deleted_at timestamp with time zone
-);
+CREATE INDEX idx_tasks_workspace_due ON public.tasks USING btree (workspace_id, due_date) WHERE (deleted_at IS NULL);
 
 
 --
--- Name: notification_queue id; Type: DEFAULT; Schema: public; Owner: -
+-- Name: idx_tasks_workspace_id; Type: INDEX; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.notification_queue ALTER COLUMN id SET DEFAULT nextval('public.notification_queue_id_seq'::regclass);
+CREATE INDEX idx_tasks_workspace_id ON public.tasks USING btree (workspace_id);
 
 
 --
--- Name: attachments attachments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: idx_tasks_workspace_updated; Type: INDEX; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.attachments
-    ADD CONSTRAINT attachments_pkey PRIMARY KEY (id);
+CREATE INDEX idx_tasks_workspace_updated ON public.tasks USING btree (workspace_id, updated_at DESC) WHERE (deleted_at IS NULL);
 
 
 --
--- Name: notification_queue notification_queue_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: idx_time_entries_task_started; Type: INDEX; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.notification_queue
-    ADD CONSTRAINT notification_queue_pkey PRIMARY KEY (id);
+CREATE INDEX idx_time_entries_task_started ON public.time_entries USING btree (task_id, started_at DESC, id DESC);
 
 
 --
--- Name: projects projects_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: idx_time_entries_workspace_started; Type: INDEX; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.projects
-    ADD CONSTRAINT projects_pkey PRIMARY KEY (id);
+CREATE INDEX idx_time_entries_workspace_started ON public.time_entries USING btree (workspace_id, started_at DESC, id DESC);
 
 
 --
--- Name: schema_migrations schema_migrations_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: idx_workspaces_deleted; Type: INDEX; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.schema_migrations
-    ADD CONSTRAINT schema_migrations_pkey PRIMARY KEY (version);
+CREATE INDEX idx_workspaces_deleted ON public.workspaces USING btree (deleted_at, id) WHERE (deleted_at IS NOT NULL);
 
 
 --
--- Name: task_comments task_comments_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: idx_workspaces_not_deleted; Type: INDEX; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.task_comments
-    ADD CONSTRAINT task_comments_pkey PRIMARY KEY (id);
+CREATE INDEX idx_workspaces_not_deleted ON public.workspaces USING btree (id) WHERE (deleted_at IS NULL);
 
 
 --
--- Name: tasks tasks_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: projects project_workflow_seed_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.tasks
-    ADD CONSTRAINT tasks_pkey PRIMARY KEY (id);
+CREATE TRIGGER project_workflow_seed_trigger AFTER INSERT ON public.projects FOR EACH ROW EXECUTE FUNCTION public.seed_project_workflow();
 
 
 --
--- Name: workspaces workspaces_pkey; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: projects projects_version_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.workspaces
-    ADD CONSTRAINT workspaces_pkey PRIMARY KEY (id);
+CREATE TRIGGER projects_version_trigger BEFORE UPDATE ON public.projects FOR EACH ROW EXECUTE FUNCTION public.bump_row_version();
 
 
 --
--- Name: workspaces workspaces_slug_key; Type: CONSTRAINT; Schema: public; Owner: -
+-- Name: task_assignees task_assignees_watch_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.workspaces
-    ADD CONSTRAINT workspaces_slug_key UNIQUE (slug);
+CREATE TRIGGER task_assignees_watch_trigger AFTER INSERT ON public.task_assignees FOR EACH ROW EXECUTE FUNCTION public.watch_task_assignees();
 
 
 --
--- Name: idx_attachments_task_id; Type: INDEX; Schema: public; Owner: -
+-- Name: task_comments task_comment_notify_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_attachments_task_id ON public.attachments USING btree (task_id);
+CREATE TRIGGER task_comment_notify_trigger AFTER INSERT ON public.task_comments FOR EACH ROW EXECUTE FUNCTION public.notify_task_comment();
 
 
 --
--- Name: idx_notification_queue_actionable; Type: INDEX; Schema: public; Owner: -
+-- Name: task_history task_history_notify_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_notification_queue_actionable ON public.notification_queue USING btree (status, next_retry_at) WHERE ((status)::text = ANY ((ARRAY['pending'::character varying, 'failed'::character varying])::text[]));
+CREATE CONSTRAINT TRIGGER task_history_notify_trigger AFTER INSERT ON public.task_history DEFERRABLE INITIALLY DEFERRED FOR EACH ROW EXECUTE FUNCTION public.notify_task_watchers();
 
 
 --
--- Name: idx_notification_queue_created_at; Type: INDEX; Schema: public; Owner: -
+-- Name: tasks task_assigned_to_sync_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_notification_queue_created_at ON public.notification_queue USING btree (created_at);
+CREATE TRIGGER task_assigned_to_sync_trigger AFTER INSERT OR UPDATE OF assigned_to ON public.tasks FOR EACH ROW EXECUTE FUNCTION public.sync_task_assignees();
 
 
 --
--- Name: idx_notification_queue_workspace_id; Type: INDEX; Schema: public; Owner: -
+-- Name: tasks task_assignee_watch_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_notification_queue_workspace_id ON public.notification_queue USING btree (workspace_id);
+CREATE TRIGGER task_assignee_watch_trigger AFTER INSERT OR UPDATE OF assigned_to ON public.tasks FOR EACH ROW EXECUTE FUNCTION public.watch_task_assignee();
 
 
 --
--- Name: idx_projects_not_deleted; Type: INDEX; Schema: public; Owner: -
+-- Name: tasks task_events_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_projects_not_deleted ON public.projects USING btree (id) WHERE (deleted_at IS NULL);
+CREATE TRIGGER task_events_trigger AFTER INSERT OR UPDATE ON public.tasks FOR EACH ROW EXECUTE FUNCTION public.notify_task_event();
 
 
 --
--- Name: idx_projects_workspace_id; Type: INDEX; Schema: public; Owner: -
+-- Name: tasks task_history_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_projects_workspace_id ON public.projects USING btree (workspace_id);
+CREATE TRIGGER task_history_trigger AFTER INSERT OR UPDATE ON public.tasks FOR EACH ROW EXECUTE FUNCTION public.record_task_history();
 
 
 --
--- Name: idx_task_comments_task_created; Type: INDEX; Schema: public; Owner: -
+-- Name: tasks task_rank_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_task_comments_task_created ON public.task_comments USING btree (task_id, created_at);
+CREATE TRIGGER task_rank_trigger BEFORE INSERT ON public.tasks FOR EACH ROW WHEN ((new.rank IS NULL)) EXECUTE FUNCTION public.set_task_rank();
 
 
 --
--- Name: idx_task_comments_task_id; Type: INDEX; Schema: public; Owner: -
+-- Name: tasks tasks_version_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_task_comments_task_id ON public.task_comments USING btree (task_id);
+CREATE TRIGGER tasks_version_trigger BEFORE UPDATE ON public.tasks FOR EACH ROW EXECUTE FUNCTION public.bump_row_version();
 
 
 --
--- Name: idx_tasks_assigned_to; Type: INDEX; Schema: public; Owner: -
+-- Name: workspaces workspaces_version_trigger; Type: TRIGGER; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_tasks_assigned_to ON public.tasks USING btree (assigned_to) WHERE (assigned_to IS NOT NULL);
+CREATE TRIGGER workspaces_version_trigger BEFORE UPDATE ON public.workspaces FOR EACH ROW EXECUTE FUNCTION public.bump_row_version();
 
 
 --
--- Name: idx_tasks_not_deleted; Type: INDEX; Schema: public; Owner: -
+-- Name: attachments attachments_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_tasks_not_deleted ON public.tasks USING btree (id) WHERE (deleted_at IS NULL);
+ALTER TABLE ONLY public.attachments
+    ADD CONSTRAINT attachments_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id);
 
 
 --
--- Name: idx_tasks_project_id; Type: INDEX; Schema: public; Owner: -
+-- Name: labels labels_workspace_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_tasks_project_id ON public.tasks USING btree (project_id);
+ALTER TABLE ONLY public.labels
+    ADD CONSTRAINT labels_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES public.workspaces(id);
 
 
 --
--- Name: idx_tasks_status_created; Type: INDEX; Schema: public; Owner: -
+-- Name: notification_queue notification_queue_workspace_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_tasks_status_created ON public.tasks USING btree (status, created_at DESC);
+ALTER TABLE ONLY public.notification_queue
+    ADD CONSTRAINT notification_queue_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES public.workspaces(id);
 
 
 --
--- Name: idx_tasks_workspace_id; Type: INDEX; Schema: public; Owner: -
+-- Name: project_custom_fields project_custom_fields_project_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_tasks_workspace_id ON public.tasks USING btree (workspace_id);
+ALTER TABLE ONLY public.project_custom_fields
+    ADD CONSTRAINT project_custom_fields_project_id_fkey FOREIGN KEY (project_id) REFERENCES public.projects(id) ON DELETE CASCADE;
 
 
 --
--- Name: idx_workspaces_not_deleted; Type: INDEX; Schema: public; Owner: -
+-- Name: project_status_transitions project_status_transitions_project_id_from_status_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE INDEX idx_workspaces_not_deleted ON public.workspaces USING btree (id) WHERE (deleted_at IS NULL);
+ALTER TABLE ONLY public.project_status_transitions
+    ADD CONSTRAINT project_status_transitions_project_id_from_status_fkey FOREIGN KEY (project_id, from_status) REFERENCES public.project_statuses(project_id, name) ON DELETE CASCADE;
 
 
 --
--- Name: tasks task_events_trigger; Type: TRIGGER; Schema: public; Owner: -
+-- Name: project_status_transitions project_status_transitions_project_id_to_status_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
-CREATE TRIGGER task_events_trigger AFTER INSERT OR UPDATE ON public.tasks FOR EACH ROW EXECUTE FUNCTION public.notify_task_event();
+ALTER TABLE ONLY public.project_status_transitions
+    ADD CONSTRAINT project_status_transitions_project_id_to_status_fkey FOREIGN KEY (project_id, to_status) REFERENCES public.project_statuses(project_id, name) ON DELETE CASCADE;
 
 
 --
--- Name: attachments attachments_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: project_statuses project_statuses_project_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.attachments
-    ADD CONSTRAINT attachments_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id);
+ALTER TABLE ONLY public.project_statuses
+    ADD CONSTRAINT project_statuses_project_id_fkey FOREIGN KEY (project_id) REFERENCES public.projects(id) ON DELETE CASCADE;
 
 
 --
--- Name: notification_queue notification_queue_workspace_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+-- Name: project_watchers project_watchers_project_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
 
-ALTER TABLE ONLY public.notification_queue
-    ADD CONSTRAINT notification_queue_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES public.workspaces(id);
+ALTER TABLE ONLY public.project_watchers
+    ADD CONSTRAINT project_watchers_project_id_fkey FOREIGN KEY (project_id) REFERENCES public.projects(id) ON DELETE CASCADE;
 
 
 --
@@ -384,6 +1275,14 @@ ALTER TABLE ONLY public.projects
     ADD CONSTRAINT projects_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES public.workspaces(id);
 
 
+--
+-- Name: task_assignees task_assignees_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_assignees
+    ADD CONSTRAINT task_assignees_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id) ON DELETE CASCADE;
+
+
 --
 -- Name: task_comments task_comments_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -392,6 +1291,102 @@ ALTER TABLE ONLY public.task_comments
     ADD CONSTRAINT task_comments_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id);
 
 
+--
+-- Name: task_dependencies task_dependencies_blocked_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_dependencies
+    ADD CONSTRAINT task_dependencies_blocked_task_id_fkey FOREIGN KEY (blocked_task_id) REFERENCES public.tasks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: task_dependencies task_dependencies_blocker_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_dependencies
+    ADD CONSTRAINT task_dependencies_blocker_task_id_fkey FOREIGN KEY (blocker_task_id) REFERENCES public.tasks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: task_dependencies task_dependencies_workspace_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_dependencies
+    ADD CONSTRAINT task_dependencies_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES public.workspaces(id);
+
+
+--
+-- Name: task_due_notifications task_due_notifications_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_due_notifications
+    ADD CONSTRAINT task_due_notifications_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: task_history task_history_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_history
+    ADD CONSTRAINT task_history_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: task_labels task_labels_label_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_labels
+    ADD CONSTRAINT task_labels_label_id_fkey FOREIGN KEY (label_id) REFERENCES public.labels(id) ON DELETE CASCADE;
+
+
+--
+-- Name: task_labels task_labels_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_labels
+    ADD CONSTRAINT task_labels_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: task_recurrences task_recurrences_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_recurrences
+    ADD CONSTRAINT task_recurrences_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: task_templates task_templates_project_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_templates
+    ADD CONSTRAINT task_templates_project_id_fkey FOREIGN KEY (project_id) REFERENCES public.projects(id) ON DELETE CASCADE;
+
+
+--
+-- Name: task_templates task_templates_workspace_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_templates
+    ADD CONSTRAINT task_templates_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES public.workspaces(id) ON DELETE CASCADE;
+
+
+--
+-- Name: task_watchers task_watchers_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.task_watchers
+    ADD CONSTRAINT task_watchers_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: tasks tasks_parent_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tasks
+    ADD CONSTRAINT tasks_parent_task_id_fkey FOREIGN KEY (parent_task_id) REFERENCES public.tasks(id) ON DELETE SET NULL;
+
+
 --
 -- Name: tasks tasks_project_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -400,6 +1395,14 @@ ALTER TABLE ONLY public.tasks
     ADD CONSTRAINT tasks_project_id_fkey FOREIGN KEY (project_id) REFERENCES public.projects(id);
 
 
+--
+-- Name: tasks tasks_project_status_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.tasks
+    ADD CONSTRAINT tasks_project_status_fkey FOREIGN KEY (project_id, status) REFERENCES public.project_statuses(project_id, name);
+
+
 --
 -- Name: tasks tasks_workspace_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
 --
@@ -408,6 +1411,30 @@ ALTER TABLE ONLY public.tasks
     ADD CONSTRAINT tasks_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES public.workspaces(id);
 
 
+--
+-- Name: time_entries time_entries_task_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.time_entries
+    ADD CONSTRAINT time_entries_task_id_fkey FOREIGN KEY (task_id) REFERENCES public.tasks(id) ON DELETE CASCADE;
+
+
+--
+-- Name: time_entries time_entries_workspace_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.time_entries
+    ADD CONSTRAINT time_entries_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES public.workspaces(id) ON DELETE CASCADE;
+
+
+--
+-- Name: workspace_reminder_settings workspace_reminder_settings_workspace_id_fkey; Type: FK CONSTRAINT; Schema: public; Owner: -
+--
+
+ALTER TABLE ONLY public.workspace_reminder_settings
+    ADD CONSTRAINT workspace_reminder_settings_workspace_id_fkey FOREIGN KEY (workspace_id) REFERENCES public.workspaces(id) ON DELETE CASCADE;
+
+
 --
 -- PostgreSQL database dump complete
 --
@@ -425,4 +1452,28 @@ INSERT INTO public.schema_migrations (version) VALUES
     ('20260208000003'),
     ('20260208000004'),
     ('20260208000005'),
-    ('20260208000006');
+    ('20260208000006'),
+    ('20261017000001'),
+    ('20261017000002'),
+    ('20261017000003'),
+    ('20261017000004'),
+    ('20261017000005'),
+    ('20261017000006'),
+    ('20261017000007'),
+    ('20261017000008'),
+    ('20261017000009'),
+    ('20261017000010'),
+    ('20261017000011'),
+    ('20261017000012'),
+    ('20261017000013'),
+    ('20261017000014'),
+    ('20261017000015'),
+    ('20261017000016'),
+    ('20261017000017'),
+    ('20261017000018'),
+    ('20261017000019'),
+    ('20261017000020'),
+    ('20261017000021'),
+    ('20261017000022'),
+    ('20261017000023'),
+    ('20261017000024');
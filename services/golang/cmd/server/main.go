@@ -15,9 +15,13 @@ import (
 	"golang.org/x/net/http2/h2c"
 
 	"github.com/igorrmotta/api-corestack/services/golang/gen/comment/v1/commentv1connect"
+	"github.com/igorrmotta/api-corestack/services/golang/gen/label/v1/labelv1connect"
 	"github.com/igorrmotta/api-corestack/services/golang/gen/notification/v1/notificationv1connect"
 	"github.com/igorrmotta/api-corestack/services/golang/gen/project/v1/projectv1connect"
+	"github.com/igorrmotta/api-corestack/services/golang/gen/search/v1/searchv1connect"
 	"github.com/igorrmotta/api-corestack/services/golang/gen/task/v1/taskv1connect"
+	"github.com/igorrmotta/api-corestack/services/golang/gen/template/v1/templatev1connect"
+	"github.com/igorrmotta/api-corestack/services/golang/gen/timeentry/v1/timeentryv1connect"
 	"github.com/igorrmotta/api-corestack/services/golang/gen/workspace/v1/workspacev1connect"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/config"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/handler"
@@ -65,29 +69,51 @@ func main() {
 
 	// Initialize repositories
 	workspaceRepo := repository.NewWorkspaceRepo(pool)
+	reminderRepo := repository.NewReminderRepo(pool)
 	projectRepo := repository.NewProjectRepo(pool)
+	workflowRepo := repository.NewWorkflowRepo(pool)
 	taskRepo := repository.NewTaskRepo(pool)
+	depRepo := repository.NewDependencyRepo(pool)
+	historyRepo := repository.NewHistoryRepo(pool)
+	recurrenceRepo := repository.NewRecurrenceRepo(pool)
+	labelRepo := repository.NewLabelRepo(pool)
 	commentRepo := repository.NewCommentRepo(pool)
 	notifRepo := repository.NewNotificationRepo(pool)
+	searchRepo := repository.NewSearchRepo(pool)
+	watcherRepo := repository.NewWatcherRepo(pool)
+	timeEntryRepo := repository.NewTimeEntryRepo(pool)
+	customFieldRepo := repository.NewCustomFieldRepo(pool)
+	templateRepo := repository.NewTemplateRepo(pool)
 
 	// Initialize services
-	workspaceSvc := service.NewWorkspaceService(workspaceRepo)
-	projectSvc := service.NewProjectService(projectRepo)
-	taskSvc := service.NewTaskService(taskRepo, notifRepo)
+	workspaceSvc := service.NewWorkspaceService(workspaceRepo, reminderRepo)
+	projectSvc := service.NewProjectService(projectRepo, workflowRepo, customFieldRepo)
+	taskSvc := service.NewTaskService(taskRepo, projectRepo, workflowRepo, depRepo, historyRepo, customFieldRepo)
+	labelSvc := service.NewLabelService(labelRepo, taskRepo)
 	commentSvc := service.NewCommentService(commentRepo)
-	importSvc := service.NewImportService(taskRepo, notifRepo, cfg.RiverConcurrency, 100)
+	searchSvc := service.NewSearchService(searchRepo)
+	recurrenceSvc := service.NewRecurrenceService(recurrenceRepo, taskRepo)
+	importSvc := service.NewImportService(taskRepo, customFieldRepo, cfg.RiverConcurrency, 100)
+	watcherSvc := service.NewWatcherService(watcherRepo)
+	timeEntrySvc := service.NewTimeEntryService(timeEntryRepo)
+	templateSvc := service.NewTemplateService(templateRepo, taskRepo, customFieldRepo)
 
 	// Initialize handlers
 	workspaceHandler := handler.NewWorkspaceHandler(workspaceSvc)
-	projectHandler := handler.NewProjectHandler(projectSvc)
-	taskHandler := handler.NewTaskHandler(taskSvc, importSvc)
+	projectHandler := handler.NewProjectHandler(projectSvc, watcherSvc)
+	taskHandler := handler.NewTaskHandler(taskSvc, importSvc, recurrenceSvc, watcherSvc)
+	labelHandler := handler.NewLabelHandler(labelSvc)
 	commentHandler := handler.NewCommentHandler(commentSvc)
 	notificationHandler := handler.NewNotificationHandler(notifRepo)
+	searchHandler := handler.NewSearchHandler(searchSvc)
+	timeEntryHandler := handler.NewTimeEntryHandler(timeEntrySvc)
+	templateHandler := handler.NewTemplateHandler(templateSvc)
 
 	// Connect RPC interceptors
 	interceptors := connect.WithInterceptors(
 		middleware.NewLoggingInterceptor(),
 		middleware.NewRecoveryInterceptor(),
+		middleware.NewIdentityInterceptor(),
 	)
 
 	// Register Connect RPC routes
@@ -102,12 +128,24 @@ func main() {
 	path, h = taskv1connect.NewTaskServiceHandler(taskHandler, interceptors)
 	mux.Handle(path, h)
 
+	path, h = labelv1connect.NewLabelServiceHandler(labelHandler, interceptors)
+	mux.Handle(path, h)
+
 	path, h = commentv1connect.NewCommentServiceHandler(commentHandler, interceptors)
 	mux.Handle(path, h)
 
 	path, h = notificationv1connect.NewNotificationServiceHandler(notificationHandler, interceptors)
 	mux.Handle(path, h)
 
+	path, h = searchv1connect.NewSearchServiceHandler(searchHandler, interceptors)
+	mux.Handle(path, h)
+
+	path, h = timeentryv1connect.NewTimeEntryServiceHandler(timeEntryHandler, interceptors)
+	mux.Handle(path, h)
+
+	path, h = templatev1connect.NewTemplateServiceHandler(templateHandler, interceptors)
+	mux.Handle(path, h)
+
 	// Health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
@@ -14,6 +14,7 @@ import (
 
 	"github.com/igorrmotta/api-corestack/services/golang/internal/config"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/service"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/worker"
 )
 
@@ -72,19 +73,54 @@ func main() {
 	// Initialize repositories
 	taskRepo := repository.NewTaskRepo(pool)
 	notifRepo := repository.NewNotificationRepo(pool)
+	recurrenceRepo := repository.NewRecurrenceRepo(pool)
+	reminderRepo := repository.NewReminderRepo(pool)
+	trashRepo := repository.NewTrashRepo(pool)
+
+	// Initialize services
+	recurrenceSvc := service.NewRecurrenceService(recurrenceRepo, taskRepo)
 
 	// Register River workers
 	workers := river.NewWorkers()
 	river.AddWorker(workers, worker.NewNotificationWorker(notifRepo))
 	river.AddWorker(workers, worker.NewNotificationBatchWorker(notifRepo))
-	river.AddWorker(workers, worker.NewImportWorker(taskRepo, notifRepo))
+	river.AddWorker(workers, worker.NewImportWorker(taskRepo))
+	river.AddWorker(workers, worker.NewRecurrenceWorker(recurrenceSvc))
+	river.AddWorker(workers, worker.NewDueReminderWorker(reminderRepo))
+	river.AddWorker(workers, worker.NewTrashPurgeWorker(trashRepo, cfg.TrashRetention))
+
+	// Periodic jobs
+	periodicJobs := []*river.PeriodicJob{
+		river.NewPeriodicJob(
+			river.PeriodicInterval(cfg.RecurrenceInterval),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return worker.RecurrenceJobArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+		river.NewPeriodicJob(
+			river.PeriodicInterval(cfg.ReminderInterval),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return worker.DueReminderJobArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+		river.NewPeriodicJob(
+			river.PeriodicInterval(cfg.TrashPurgeInterval),
+			func() (river.JobArgs, *river.InsertOpts) {
+				return worker.TrashPurgeJobArgs{}, nil
+			},
+			&river.PeriodicJobOpts{RunOnStart: true},
+		),
+	}
 
 	// Initialize River client
 	riverClient, err := river.NewClient(riverpgxv5.New(pool), &river.Config{
 		Queues: map[string]river.QueueConfig{
 			river.QueueDefault: {MaxWorkers: cfg.RiverConcurrency},
 		},
-		Workers: workers,
+		Workers:      workers,
+		PeriodicJobs: periodicJobs,
 	})
 	if err != nil {
 		slog.Error("failed to create river client", "error", err)
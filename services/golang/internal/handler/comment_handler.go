@@ -7,9 +7,9 @@ import (
 	"github.com/google/uuid"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
-	commonv1 "github.com/igorrmotta/api-corestack/services/golang/gen/common/v1"
 	commentv1 "github.com/igorrmotta/api-corestack/services/golang/gen/comment/v1"
 	"github.com/igorrmotta/api-corestack/services/golang/gen/comment/v1/commentv1connect"
+	commonv1 "github.com/igorrmotta/api-corestack/services/golang/gen/common/v1"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/service"
 )
@@ -41,13 +41,21 @@ func (h *CommentHandler) CreateComment(ctx context.Context, req *connect.Request
 	}), nil
 }
 
+var commentSortFields = map[commentv1.CommentSortField]string{
+	commentv1.CommentSortField_COMMENT_SORT_FIELD_UNSPECIFIED: "",
+	commentv1.CommentSortField_COMMENT_SORT_FIELD_CREATED_AT:  "created_at",
+	commentv1.CommentSortField_COMMENT_SORT_FIELD_UPDATED_AT:  "updated_at",
+}
+
 func (h *CommentHandler) ListComments(ctx context.Context, req *connect.Request[commentv1.ListCommentsRequest]) (*connect.Response[commentv1.ListCommentsResponse], error) {
 	taskID, err := uuid.Parse(req.Msg.TaskId)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
-	var params repository.ListCommentsParams
-	params.TaskID = taskID
+	params := repository.ListCommentsParams{
+		TaskID: taskID,
+		Sort:   repository.Sort{Field: commentSortFields[req.Msg.SortBy], Desc: sortDesc(req.Msg.SortOrder)},
+	}
 	if req.Msg.Pagination != nil {
 		params.PageSize = req.Msg.Pagination.PageSize
 		params.PageToken = req.Msg.Pagination.PageToken
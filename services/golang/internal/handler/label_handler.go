@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	commonv1 "github.com/igorrmotta/api-corestack/services/golang/gen/common/v1"
+	labelv1 "github.com/igorrmotta/api-corestack/services/golang/gen/label/v1"
+	"github.com/igorrmotta/api-corestack/services/golang/gen/label/v1/labelv1connect"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/service"
+)
+
+type LabelHandler struct {
+	labelv1connect.UnimplementedLabelServiceHandler
+	svc *service.LabelService
+}
+
+func NewLabelHandler(svc *service.LabelService) *LabelHandler {
+	return &LabelHandler{svc: svc}
+}
+
+func (h *LabelHandler) CreateLabel(ctx context.Context, req *connect.Request[labelv1.CreateLabelRequest]) (*connect.Response[labelv1.CreateLabelResponse], error) {
+	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	l, err := h.svc.Create(ctx, repository.CreateLabelParams{
+		WorkspaceID: workspaceID,
+		Name:        req.Msg.Name,
+		Color:       req.Msg.Color,
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&labelv1.CreateLabelResponse{
+		Label: labelToProto(l),
+	}), nil
+}
+
+func (h *LabelHandler) GetLabel(ctx context.Context, req *connect.Request[labelv1.GetLabelRequest]) (*connect.Response[labelv1.GetLabelResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	l, err := h.svc.GetByID(ctx, id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&labelv1.GetLabelResponse{
+		Label: labelToProto(l),
+	}), nil
+}
+
+var labelSortFields = map[labelv1.LabelSortField]string{
+	labelv1.LabelSortField_LABEL_SORT_FIELD_UNSPECIFIED: "",
+	labelv1.LabelSortField_LABEL_SORT_FIELD_NAME:        "name",
+	labelv1.LabelSortField_LABEL_SORT_FIELD_CREATED_AT:  "created_at",
+	labelv1.LabelSortField_LABEL_SORT_FIELD_UPDATED_AT:  "updated_at",
+}
+
+func (h *LabelHandler) ListLabels(ctx context.Context, req *connect.Request[labelv1.ListLabelsRequest]) (*connect.Response[labelv1.ListLabelsResponse], error) {
+	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.ListLabelsParams{
+		WorkspaceID: workspaceID,
+		Sort:        repository.Sort{Field: labelSortFields[req.Msg.SortBy], Desc: sortDesc(req.Msg.SortOrder)},
+	}
+	if req.Msg.Pagination != nil {
+		params.PageSize = req.Msg.Pagination.PageSize
+		params.PageToken = req.Msg.Pagination.PageToken
+	}
+	list, err := h.svc.List(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	labels := make([]*labelv1.Label, len(list.Labels))
+	for i, l := range list.Labels {
+		labels[i] = labelToProto(&l)
+	}
+	return connect.NewResponse(&labelv1.ListLabelsResponse{
+		Labels: labels,
+		Pagination: &commonv1.PaginationResponse{
+			NextPageToken: list.NextPageToken,
+			TotalCount:    list.TotalCount,
+		},
+	}), nil
+}
+
+func (h *LabelHandler) UpdateLabel(ctx context.Context, req *connect.Request[labelv1.UpdateLabelRequest]) (*connect.Response[labelv1.UpdateLabelResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	mask, err := updateMaskPaths(req.Msg.UpdateMask, "name", "color")
+	if err != nil {
+		return nil, err
+	}
+	l, err := h.svc.Update(ctx, repository.UpdateLabelParams{
+		ID:         id,
+		Name:       req.Msg.Name,
+		Color:      req.Msg.Color,
+		UpdateMask: mask,
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&labelv1.UpdateLabelResponse{
+		Label: labelToProto(l),
+	}), nil
+}
+
+func (h *LabelHandler) DeleteLabel(ctx context.Context, req *connect.Request[labelv1.DeleteLabelRequest]) (*connect.Response[labelv1.DeleteLabelResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := h.svc.Delete(ctx, id); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&labelv1.DeleteLabelResponse{}), nil
+}
+
+func (h *LabelHandler) AddTaskLabel(ctx context.Context, req *connect.Request[labelv1.AddTaskLabelRequest]) (*connect.Response[labelv1.AddTaskLabelResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	labelID, err := uuid.Parse(req.Msg.LabelId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := h.svc.AddToTask(ctx, taskID, labelID); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&labelv1.AddTaskLabelResponse{}), nil
+}
+
+func (h *LabelHandler) RemoveTaskLabel(ctx context.Context, req *connect.Request[labelv1.RemoveTaskLabelRequest]) (*connect.Response[labelv1.RemoveTaskLabelResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	labelID, err := uuid.Parse(req.Msg.LabelId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := h.svc.RemoveFromTask(ctx, taskID, labelID); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&labelv1.RemoveTaskLabelResponse{}), nil
+}
+
+func labelToProto(l *repository.Label) *labelv1.Label {
+	return &labelv1.Label{
+		Id:          l.ID.String(),
+		WorkspaceId: l.WorkspaceID.String(),
+		Name:        l.Name,
+		Color:       l.Color,
+		CreatedAt:   timestamppb.New(l.CreatedAt),
+		UpdatedAt:   timestamppb.New(l.UpdatedAt),
+	}
+}
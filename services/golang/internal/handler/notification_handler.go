@@ -32,6 +32,8 @@ func (h *NotificationHandler) ListNotifications(ctx context.Context, req *connec
 	var params repository.ListNotificationsParams
 	params.WorkspaceID = workspaceID
 	params.Status = req.Msg.Status
+	params.RecipientID = req.Msg.RecipientId
+	params.Sort.Desc = sortDesc(req.Msg.SortOrder)
 	if req.Msg.Pagination != nil {
 		params.PageSize = req.Msg.Pagination.PageSize
 		params.PageToken = req.Msg.Pagination.PageToken
@@ -75,6 +77,7 @@ func notificationToProto(n *repository.Notification) (*notificationv1.Notificati
 	proto := &notificationv1.Notification{
 		Id:          n.ID,
 		WorkspaceId: n.WorkspaceID.String(),
+		RecipientId: n.RecipientID,
 		EventType:   n.EventType,
 		Status:      n.Status,
 		RetryCount:  n.RetryCount,
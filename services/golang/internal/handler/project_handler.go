@@ -16,11 +16,12 @@ import (
 
 type ProjectHandler struct {
 	projectv1connect.UnimplementedProjectServiceHandler
-	svc *service.ProjectService
+	svc        *service.ProjectService
+	watcherSvc *service.WatcherService
 }
 
-func NewProjectHandler(svc *service.ProjectService) *ProjectHandler {
-	return &ProjectHandler{svc: svc}
+func NewProjectHandler(svc *service.ProjectService, watcherSvc *service.WatcherService) *ProjectHandler {
+	return &ProjectHandler{svc: svc, watcherSvc: watcherSvc}
 }
 
 func (h *ProjectHandler) CreateProject(ctx context.Context, req *connect.Request[projectv1.CreateProjectRequest]) (*connect.Response[projectv1.CreateProjectResponse], error) {
@@ -55,13 +56,23 @@ func (h *ProjectHandler) GetProject(ctx context.Context, req *connect.Request[pr
 	}), nil
 }
 
+var projectSortFields = map[projectv1.ProjectSortField]string{
+	projectv1.ProjectSortField_PROJECT_SORT_FIELD_UNSPECIFIED: "",
+	projectv1.ProjectSortField_PROJECT_SORT_FIELD_CREATED_AT:  "created_at",
+	projectv1.ProjectSortField_PROJECT_SORT_FIELD_UPDATED_AT:  "updated_at",
+	projectv1.ProjectSortField_PROJECT_SORT_FIELD_NAME:        "name",
+	projectv1.ProjectSortField_PROJECT_SORT_FIELD_STATUS:      "status",
+}
+
 func (h *ProjectHandler) ListProjects(ctx context.Context, req *connect.Request[projectv1.ListProjectsRequest]) (*connect.Response[projectv1.ListProjectsResponse], error) {
 	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
-	var params repository.ListProjectsParams
-	params.WorkspaceID = workspaceID
+	params := repository.ListProjectsParams{
+		WorkspaceID: workspaceID,
+		Sort:        repository.Sort{Field: projectSortFields[req.Msg.SortBy], Desc: sortDesc(req.Msg.SortOrder)},
+	}
 	if req.Msg.Pagination != nil {
 		params.PageSize = req.Msg.Pagination.PageSize
 		params.PageToken = req.Msg.Pagination.PageToken
@@ -88,11 +99,17 @@ func (h *ProjectHandler) UpdateProject(ctx context.Context, req *connect.Request
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
+	mask, err := updateMaskPaths(req.Msg.UpdateMask, "name", "description", "status")
+	if err != nil {
+		return nil, err
+	}
 	p, err := h.svc.Update(ctx, repository.UpdateProjectParams{
 		ID:          id,
 		Name:        req.Msg.Name,
 		Description: req.Msg.Description,
 		Status:      req.Msg.Status,
+		UpdateMask:  mask,
+		Version:     req.Msg.Version,
 	})
 	if err != nil {
 		return nil, toConnectError(err)
@@ -107,20 +124,235 @@ func (h *ProjectHandler) DeleteProject(ctx context.Context, req *connect.Request
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
-	if err := h.svc.Delete(ctx, id); err != nil {
+	if err := h.svc.Delete(ctx, id, req.Msg.Version); err != nil {
 		return nil, toConnectError(err)
 	}
 	return connect.NewResponse(&projectv1.DeleteProjectResponse{}), nil
 }
 
+func (h *ProjectHandler) ListDeletedProjects(ctx context.Context, req *connect.Request[projectv1.ListDeletedProjectsRequest]) (*connect.Response[projectv1.ListDeletedProjectsResponse], error) {
+	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.ListProjectsParams{WorkspaceID: workspaceID}
+	if req.Msg.Pagination != nil {
+		params.PageSize = req.Msg.Pagination.PageSize
+		params.PageToken = req.Msg.Pagination.PageToken
+	}
+	list, err := h.svc.ListDeleted(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	projects := make([]*projectv1.Project, len(list.Projects))
+	for i, p := range list.Projects {
+		projects[i] = projectToProto(&p)
+	}
+	return connect.NewResponse(&projectv1.ListDeletedProjectsResponse{
+		Projects: projects,
+		Pagination: &commonv1.PaginationResponse{
+			NextPageToken: list.NextPageToken,
+			TotalCount:    list.TotalCount,
+		},
+	}), nil
+}
+
+func (h *ProjectHandler) RestoreProject(ctx context.Context, req *connect.Request[projectv1.RestoreProjectRequest]) (*connect.Response[projectv1.RestoreProjectResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	p, err := h.svc.Restore(ctx, id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&projectv1.RestoreProjectResponse{
+		Project: projectToProto(p),
+	}), nil
+}
+
+func (h *ProjectHandler) GetProjectWorkflow(ctx context.Context, req *connect.Request[projectv1.GetProjectWorkflowRequest]) (*connect.Response[projectv1.GetProjectWorkflowResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	w, err := h.svc.GetWorkflow(ctx, projectID)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&projectv1.GetProjectWorkflowResponse{
+		Workflow: workflowToProto(w),
+	}), nil
+}
+
+func (h *ProjectHandler) UpdateProjectWorkflow(ctx context.Context, req *connect.Request[projectv1.UpdateProjectWorkflowRequest]) (*connect.Response[projectv1.UpdateProjectWorkflowResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.ReplaceWorkflowParams{
+		ProjectID:   projectID,
+		Statuses:    make([]repository.WorkflowStatus, len(req.Msg.Statuses)),
+		Transitions: make([]repository.WorkflowTransition, len(req.Msg.Transitions)),
+	}
+	for i, s := range req.Msg.Statuses {
+		params.Statuses[i] = repository.WorkflowStatus{Name: s.Name, IsDone: s.IsDone}
+	}
+	for i, t := range req.Msg.Transitions {
+		params.Transitions[i] = repository.WorkflowTransition{From: t.FromStatus, To: t.ToStatus}
+	}
+	w, err := h.svc.UpdateWorkflow(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&projectv1.UpdateProjectWorkflowResponse{
+		Workflow: workflowToProto(w),
+	}), nil
+}
+
+func (h *ProjectHandler) GetProjectCustomFields(ctx context.Context, req *connect.Request[projectv1.GetProjectCustomFieldsRequest]) (*connect.Response[projectv1.GetProjectCustomFieldsResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	schema, err := h.svc.GetCustomFields(ctx, projectID)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&projectv1.GetProjectCustomFieldsResponse{
+		Fields: customFieldsToProto(schema.Fields),
+	}), nil
+}
+
+func (h *ProjectHandler) UpdateProjectCustomFields(ctx context.Context, req *connect.Request[projectv1.UpdateProjectCustomFieldsRequest]) (*connect.Response[projectv1.UpdateProjectCustomFieldsResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.ReplaceCustomFieldsParams{
+		ProjectID: projectID,
+		Fields:    make([]repository.CustomField, len(req.Msg.Fields)),
+	}
+	for i, f := range req.Msg.Fields {
+		params.Fields[i] = repository.CustomField{
+			Key:      f.Key,
+			Type:     customFieldTypeName(f.Type),
+			Required: f.Required,
+			Options:  f.Options,
+		}
+	}
+	schema, err := h.svc.UpdateCustomFields(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&projectv1.UpdateProjectCustomFieldsResponse{
+		Fields: customFieldsToProto(schema.Fields),
+	}), nil
+}
+
+func (h *ProjectHandler) WatchProject(ctx context.Context, req *connect.Request[projectv1.WatchProjectRequest]) (*connect.Response[projectv1.WatchProjectResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	w, err := h.watcherSvc.WatchProject(ctx, projectID, req.Msg.UserId)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&projectv1.WatchProjectResponse{
+		Watcher: watcherToProto(w),
+	}), nil
+}
+
+func (h *ProjectHandler) UnwatchProject(ctx context.Context, req *connect.Request[projectv1.UnwatchProjectRequest]) (*connect.Response[projectv1.UnwatchProjectResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := h.watcherSvc.UnwatchProject(ctx, projectID, req.Msg.UserId); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&projectv1.UnwatchProjectResponse{}), nil
+}
+
+func (h *ProjectHandler) ListProjectWatchers(ctx context.Context, req *connect.Request[projectv1.ListProjectWatchersRequest]) (*connect.Response[projectv1.ListProjectWatchersResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	watchers, err := h.watcherSvc.ListProjectWatchers(ctx, projectID)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	resp := &projectv1.ListProjectWatchersResponse{
+		Watchers: make([]*commonv1.Watcher, len(watchers)),
+	}
+	for i := range watchers {
+		resp.Watchers[i] = watcherToProto(&watchers[i])
+	}
+	return connect.NewResponse(resp), nil
+}
+
 func projectToProto(p *repository.Project) *projectv1.Project {
-	return &projectv1.Project{
+	proto := &projectv1.Project{
 		Id:          p.ID.String(),
 		WorkspaceId: p.WorkspaceID.String(),
 		Name:        p.Name,
 		Description: p.Description,
 		Status:      p.Status,
+		Version:     p.Version,
 		CreatedAt:   timestamppb.New(p.CreatedAt),
 		UpdatedAt:   timestamppb.New(p.UpdatedAt),
 	}
+	if p.DeletedAt != nil {
+		proto.DeletedAt = timestamppb.New(*p.DeletedAt)
+	}
+	return proto
+}
+
+func workflowToProto(w *repository.Workflow) *projectv1.Workflow {
+	proto := &projectv1.Workflow{
+		ProjectId:   w.ProjectID.String(),
+		Statuses:    make([]*projectv1.WorkflowStatus, len(w.Statuses)),
+		Transitions: make([]*projectv1.WorkflowTransition, len(w.Transitions)),
+	}
+	for i, s := range w.Statuses {
+		proto.Statuses[i] = &projectv1.WorkflowStatus{Name: s.Name, IsDone: s.IsDone}
+	}
+	for i, t := range w.Transitions {
+		proto.Transitions[i] = &projectv1.WorkflowTransition{FromStatus: t.From, ToStatus: t.To}
+	}
+	return proto
+}
+
+var customFieldTypes = map[string]projectv1.CustomFieldType{
+	"text":   projectv1.CustomFieldType_CUSTOM_FIELD_TYPE_TEXT,
+	"number": projectv1.CustomFieldType_CUSTOM_FIELD_TYPE_NUMBER,
+	"date":   projectv1.CustomFieldType_CUSTOM_FIELD_TYPE_DATE,
+	"enum":   projectv1.CustomFieldType_CUSTOM_FIELD_TYPE_ENUM,
+	"user":   projectv1.CustomFieldType_CUSTOM_FIELD_TYPE_USER,
+}
+
+// customFieldTypeName returns the stored name of t, or "" when t is
+// unspecified or unknown.
+func customFieldTypeName(t projectv1.CustomFieldType) string {
+	for name, v := range customFieldTypes {
+		if v == t {
+			return name
+		}
+	}
+	return ""
+}
+
+func customFieldsToProto(fields []repository.CustomField) []*projectv1.CustomField {
+	out := make([]*projectv1.CustomField, len(fields))
+	for i, f := range fields {
+		out[i] = &projectv1.CustomField{
+			Key:      f.Key,
+			Type:     customFieldTypes[f.Type],
+			Required: f.Required,
+			Options:  f.Options,
+		}
+	}
+	return out
 }
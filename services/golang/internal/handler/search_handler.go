@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+
+	commonv1 "github.com/igorrmotta/api-corestack/services/golang/gen/common/v1"
+	searchv1 "github.com/igorrmotta/api-corestack/services/golang/gen/search/v1"
+	"github.com/igorrmotta/api-corestack/services/golang/gen/search/v1/searchv1connect"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/service"
+)
+
+type SearchHandler struct {
+	searchv1connect.UnimplementedSearchServiceHandler
+	svc *service.SearchService
+}
+
+func NewSearchHandler(svc *service.SearchService) *SearchHandler {
+	return &SearchHandler{svc: svc}
+}
+
+func (h *SearchHandler) Search(ctx context.Context, req *connect.Request[searchv1.SearchRequest]) (*connect.Response[searchv1.SearchResponse], error) {
+	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.SearchParams{
+		WorkspaceID: workspaceID,
+		Query:       req.Msg.Query,
+	}
+	if req.Msg.ProjectId != "" {
+		projectID, err := uuid.Parse(req.Msg.ProjectId)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		params.ProjectID = projectID
+	}
+	if req.Msg.Pagination != nil {
+		params.PageSize = req.Msg.Pagination.PageSize
+		params.PageToken = req.Msg.Pagination.PageToken
+	}
+
+	list, err := h.svc.Search(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	results := make([]*searchv1.SearchResult, len(list.Results))
+	for i, r := range list.Results {
+		results[i] = searchResultToProto(&r)
+	}
+	return connect.NewResponse(&searchv1.SearchResponse{
+		Results: results,
+		Pagination: &commonv1.PaginationResponse{
+			NextPageToken: list.NextPageToken,
+			TotalCount:    list.TotalCount,
+		},
+	}), nil
+}
+
+func searchResultToProto(r *repository.SearchResult) *searchv1.SearchResult {
+	proto := &searchv1.SearchResult{
+		Type:      searchv1.SearchResultType_SEARCH_RESULT_TYPE_TASK,
+		TaskId:    r.TaskID.String(),
+		TaskTitle: r.TaskTitle,
+		Snippet:   r.Snippet,
+		Rank:      r.Rank,
+	}
+	if r.CommentID != nil {
+		proto.Type = searchv1.SearchResultType_SEARCH_RESULT_TYPE_COMMENT
+		proto.CommentId = r.CommentID.String()
+	}
+	return proto
+}
@@ -3,9 +3,13 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
 
 	"connectrpc.com/connect"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
@@ -14,16 +18,19 @@ import (
 	"github.com/igorrmotta/api-corestack/services/golang/gen/task/v1/taskv1connect"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/service"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/taskquery"
 )
 
 type TaskHandler struct {
 	taskv1connect.UnimplementedTaskServiceHandler
-	svc       *service.TaskService
-	importSvc *service.ImportService
+	svc           *service.TaskService
+	importSvc     *service.ImportService
+	recurrenceSvc *service.RecurrenceService
+	watcherSvc    *service.WatcherService
 }
 
-func NewTaskHandler(svc *service.TaskService, importSvc *service.ImportService) *TaskHandler {
-	return &TaskHandler{svc: svc, importSvc: importSvc}
+func NewTaskHandler(svc *service.TaskService, importSvc *service.ImportService, recurrenceSvc *service.RecurrenceService, watcherSvc *service.WatcherService) *TaskHandler {
+	return &TaskHandler{svc: svc, importSvc: importSvc, recurrenceSvc: recurrenceSvc, watcherSvc: watcherSvc}
 }
 
 func (h *TaskHandler) CreateTask(ctx context.Context, req *connect.Request[taskv1.CreateTaskRequest]) (*connect.Response[taskv1.CreateTaskResponse], error) {
@@ -42,7 +49,14 @@ func (h *TaskHandler) CreateTask(ctx context.Context, req *connect.Request[taskv
 		Title:       req.Msg.Title,
 		Description: req.Msg.Description,
 		Priority:    req.Msg.Priority,
-		AssignedTo:  req.Msg.AssignedTo,
+		Assignees:   taskAssignees(req.Msg.AssignedTo, req.Msg.Assignees),
+	}
+	if req.Msg.ParentTaskId != "" {
+		parentID, err := uuid.Parse(req.Msg.ParentTaskId)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		params.ParentTaskID = &parentID
 	}
 	if req.Msg.DueDate != nil {
 		t := req.Msg.DueDate.AsTime()
@@ -55,6 +69,12 @@ func (h *TaskHandler) CreateTask(ctx context.Context, req *connect.Request[taskv
 		}
 		params.Metadata = b
 	}
+	if params.OriginalEstimate, err = durationSeconds(req.Msg.OriginalEstimate); err != nil {
+		return nil, err
+	}
+	if params.RemainingEstimate, err = durationSeconds(req.Msg.RemainingEstimate); err != nil {
+		return nil, err
+	}
 
 	task, err := h.svc.Create(ctx, params)
 	if err != nil {
@@ -92,23 +112,9 @@ func (h *TaskHandler) ListTasks(ctx context.Context, req *connect.Request[taskv1
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
-
-	params := repository.ListTasksParams{
-		WorkspaceID: workspaceID,
-		Status:      req.Msg.Status,
-		Priority:    req.Msg.Priority,
-		AssignedTo:  req.Msg.AssignedTo,
-	}
-	if req.Msg.ProjectId != "" {
-		projectID, err := uuid.Parse(req.Msg.ProjectId)
-		if err != nil {
-			return nil, connect.NewError(connect.CodeInvalidArgument, err)
-		}
-		params.ProjectID = projectID
-	}
-	if req.Msg.Pagination != nil {
-		params.PageSize = req.Msg.Pagination.PageSize
-		params.PageToken = req.Msg.Pagination.PageToken
+	params, err := listTasksParams(workspaceID, req.Msg)
+	if err != nil {
+		return nil, err
 	}
 
 	list, err := h.svc.List(ctx, params)
@@ -137,6 +143,13 @@ func (h *TaskHandler) UpdateTask(ctx context.Context, req *connect.Request[taskv
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
+	mask, err := updateMaskPaths(req.Msg.UpdateMask,
+		"title", "description", "status", "priority", "assigned_to", "assignees", "due_date", "metadata",
+		"original_estimate", "remaining_estimate")
+	if err != nil {
+		return nil, err
+	}
+	mask, assignees := assigneeMask(mask, req.Msg.AssignedTo, req.Msg.Assignees)
 
 	params := repository.UpdateTaskParams{
 		ID:          id,
@@ -144,7 +157,9 @@ func (h *TaskHandler) UpdateTask(ctx context.Context, req *connect.Request[taskv
 		Description: req.Msg.Description,
 		Status:      req.Msg.Status,
 		Priority:    req.Msg.Priority,
-		AssignedTo:  req.Msg.AssignedTo,
+		Assignees:   assignees,
+		UpdateMask:  mask,
+		Version:     req.Msg.Version,
 	}
 	if req.Msg.DueDate != nil {
 		t := req.Msg.DueDate.AsTime()
@@ -157,6 +172,12 @@ func (h *TaskHandler) UpdateTask(ctx context.Context, req *connect.Request[taskv
 		}
 		params.Metadata = b
 	}
+	if params.OriginalEstimate, err = durationSeconds(req.Msg.OriginalEstimate); err != nil {
+		return nil, err
+	}
+	if params.RemainingEstimate, err = durationSeconds(req.Msg.RemainingEstimate); err != nil {
+		return nil, err
+	}
 
 	task, err := h.svc.Update(ctx, params)
 	if err != nil {
@@ -171,17 +192,198 @@ func (h *TaskHandler) UpdateTask(ctx context.Context, req *connect.Request[taskv
 	}), nil
 }
 
+func (h *TaskHandler) MoveTask(ctx context.Context, req *connect.Request[taskv1.MoveTaskRequest]) (*connect.Response[taskv1.MoveTaskResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.MoveTaskParams{
+		ID:      id,
+		Status:  req.Msg.Status,
+		Version: req.Msg.Version,
+	}
+	if req.Msg.BeforeTaskId != "" {
+		beforeID, err := uuid.Parse(req.Msg.BeforeTaskId)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		params.BeforeID = &beforeID
+	}
+	if req.Msg.AfterTaskId != "" {
+		afterID, err := uuid.Parse(req.Msg.AfterTaskId)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		params.AfterID = &afterID
+	}
+
+	task, err := h.svc.Move(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	proto, err := taskToProto(task)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&taskv1.MoveTaskResponse{
+		Task: proto,
+	}), nil
+}
+
+func (h *TaskHandler) CloneTask(ctx context.Context, req *connect.Request[taskv1.CloneTaskRequest]) (*connect.Response[taskv1.CloneTaskResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.CloneTaskParams{
+		ID:              id,
+		IncludeComments: req.Msg.IncludeComments,
+	}
+	if req.Msg.ProjectId != "" {
+		if params.ProjectID, err = uuid.Parse(req.Msg.ProjectId); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+
+	task, err := h.svc.Clone(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	proto, err := taskToProto(task)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&taskv1.CloneTaskResponse{
+		Task: proto,
+	}), nil
+}
+
+func (h *TaskHandler) MoveTaskToProject(ctx context.Context, req *connect.Request[taskv1.MoveTaskToProjectRequest]) (*connect.Response[taskv1.MoveTaskToProjectResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+
+	task, err := h.svc.MoveToProject(ctx, repository.MoveTaskToProjectParams{
+		ID:        id,
+		ProjectID: projectID,
+		Version:   req.Msg.Version,
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	proto, err := taskToProto(task)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&taskv1.MoveTaskToProjectResponse{
+		Task: proto,
+	}), nil
+}
+
+func (h *TaskHandler) GetProjectBoard(ctx context.Context, req *connect.Request[taskv1.GetProjectBoardRequest]) (*connect.Response[taskv1.GetProjectBoardResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	board, err := h.svc.GetBoard(ctx, projectID, req.Msg.PageSize)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+
+	columns := make([]*taskv1.BoardColumn, len(board.Columns))
+	for i, c := range board.Columns {
+		column := &taskv1.BoardColumn{
+			Status:        c.Status,
+			IsDone:        c.IsDone,
+			TotalCount:    c.TotalCount,
+			Tasks:         make([]*taskv1.Task, len(c.Tasks)),
+			NextPageToken: c.NextPageToken,
+		}
+		for j := range c.Tasks {
+			proto, err := taskToProto(&c.Tasks[j])
+			if err != nil {
+				return nil, connect.NewError(connect.CodeInternal, err)
+			}
+			column.Tasks[j] = proto
+		}
+		columns[i] = column
+	}
+	return connect.NewResponse(&taskv1.GetProjectBoardResponse{
+		Columns: columns,
+	}), nil
+}
+
 func (h *TaskHandler) DeleteTask(ctx context.Context, req *connect.Request[taskv1.DeleteTaskRequest]) (*connect.Response[taskv1.DeleteTaskResponse], error) {
 	id, err := uuid.Parse(req.Msg.Id)
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
-	if err := h.svc.Delete(ctx, id); err != nil {
+	if err := h.svc.Delete(ctx, id, req.Msg.Version); err != nil {
 		return nil, toConnectError(err)
 	}
 	return connect.NewResponse(&taskv1.DeleteTaskResponse{}), nil
 }
 
+func (h *TaskHandler) ListDeletedTasks(ctx context.Context, req *connect.Request[taskv1.ListDeletedTasksRequest]) (*connect.Response[taskv1.ListDeletedTasksResponse], error) {
+	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.ListDeletedTasksParams{WorkspaceID: workspaceID}
+	if req.Msg.ProjectId != "" {
+		if params.ProjectID, err = uuid.Parse(req.Msg.ProjectId); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	if req.Msg.Pagination != nil {
+		params.PageSize = req.Msg.Pagination.PageSize
+		params.PageToken = req.Msg.Pagination.PageToken
+	}
+
+	list, err := h.svc.ListDeleted(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	tasks := make([]*taskv1.Task, len(list.Tasks))
+	for i, t := range list.Tasks {
+		proto, err := taskToProto(&t)
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		tasks[i] = proto
+	}
+	return connect.NewResponse(&taskv1.ListDeletedTasksResponse{
+		Tasks: tasks,
+		Pagination: &commonv1.PaginationResponse{
+			NextPageToken: list.NextPageToken,
+			TotalCount:    list.TotalCount,
+		},
+	}), nil
+}
+
+func (h *TaskHandler) RestoreTask(ctx context.Context, req *connect.Request[taskv1.RestoreTaskRequest]) (*connect.Response[taskv1.RestoreTaskResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	task, err := h.svc.Restore(ctx, id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	proto, err := taskToProto(task)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&taskv1.RestoreTaskResponse{
+		Task: proto,
+	}), nil
+}
+
 func (h *TaskHandler) BulkImportTasks(ctx context.Context, req *connect.Request[taskv1.BulkImportTasksRequest]) (*connect.Response[taskv1.BulkImportTasksResponse], error) {
 	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
 	if err != nil {
@@ -198,7 +400,7 @@ func (h *TaskHandler) BulkImportTasks(ctx context.Context, req *connect.Request[
 			Title:       t.Title,
 			Description: t.Description,
 			Priority:    t.Priority,
-			AssignedTo:  t.AssignedTo,
+			Assignees:   taskAssignees(t.AssignedTo, t.Assignees),
 		}
 		if t.DueDate != nil {
 			d := t.DueDate.AsTime()
@@ -214,13 +416,17 @@ func (h *TaskHandler) BulkImportTasks(ctx context.Context, req *connect.Request[
 		inputs[i] = input
 	}
 
-	result := h.importSvc.BulkImport(ctx, workspaceID, projectID, inputs)
+	result, err := h.importSvc.BulkImport(ctx, workspaceID, projectID, inputs)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
 
 	taskErrors := make([]*taskv1.TaskError, len(result.Errors))
 	for i, e := range result.Errors {
 		taskErrors[i] = &taskv1.TaskError{
-			Index: e.Index,
-			Error: e.Error,
+			Index:           e.Index,
+			Error:           e.Error,
+			FieldViolations: fieldViolationsToProto(e.Violations),
 		}
 	}
 
@@ -232,6 +438,319 @@ func (h *TaskHandler) BulkImportTasks(ctx context.Context, req *connect.Request[
 	}), nil
 }
 
+func (h *TaskHandler) BulkUpdateTasks(ctx context.Context, req *connect.Request[taskv1.BulkUpdateTasksRequest]) (*connect.Response[taskv1.BulkUpdateTasksResponse], error) {
+	sel, err := taskSelection(req.Msg.WorkspaceId, req.Msg.TaskIds, req.Msg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	mask, err := updateMaskPaths(req.Msg.UpdateMask,
+		"status", "priority", "assigned_to", "assignees", "due_date", "metadata")
+	if err != nil {
+		return nil, err
+	}
+	mask, assignees := assigneeMask(mask, req.Msg.AssignedTo, req.Msg.Assignees)
+
+	params := repository.BulkUpdateTasksParams{
+		Selection: *sel,
+		Update: repository.UpdateTaskParams{
+			Status:     req.Msg.Status,
+			Priority:   req.Msg.Priority,
+			Assignees:  assignees,
+			UpdateMask: mask,
+		},
+	}
+	if req.Msg.DueDate != nil {
+		t := req.Msg.DueDate.AsTime()
+		params.Update.DueDate = &t
+	}
+	if req.Msg.Metadata != nil {
+		b, err := json.Marshal(req.Msg.Metadata.AsMap())
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		params.Update.Metadata = b
+	}
+
+	result, err := h.svc.BulkUpdate(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&taskv1.BulkUpdateTasksResponse{
+		Total:     result.Total,
+		Succeeded: result.Succeeded,
+		Failed:    result.Failed,
+		Errors:    bulkErrorsToProto(result.Errors),
+	}), nil
+}
+
+func (h *TaskHandler) BulkDeleteTasks(ctx context.Context, req *connect.Request[taskv1.BulkDeleteTasksRequest]) (*connect.Response[taskv1.BulkDeleteTasksResponse], error) {
+	sel, err := taskSelection(req.Msg.WorkspaceId, req.Msg.TaskIds, req.Msg.Filter)
+	if err != nil {
+		return nil, err
+	}
+	result, err := h.svc.BulkDelete(ctx, *sel)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&taskv1.BulkDeleteTasksResponse{
+		Total:     result.Total,
+		Succeeded: result.Succeeded,
+		Failed:    result.Failed,
+		Errors:    bulkErrorsToProto(result.Errors),
+	}), nil
+}
+
+func (h *TaskHandler) AddTaskDependency(ctx context.Context, req *connect.Request[taskv1.AddTaskDependencyRequest]) (*connect.Response[taskv1.AddTaskDependencyResponse], error) {
+	blockerID, err := uuid.Parse(req.Msg.BlockerTaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	blockedID, err := uuid.Parse(req.Msg.BlockedTaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	d, err := h.svc.AddDependency(ctx, blockerID, blockedID)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&taskv1.AddTaskDependencyResponse{
+		Dependency: dependencyToProto(d),
+	}), nil
+}
+
+func (h *TaskHandler) RemoveTaskDependency(ctx context.Context, req *connect.Request[taskv1.RemoveTaskDependencyRequest]) (*connect.Response[taskv1.RemoveTaskDependencyResponse], error) {
+	blockerID, err := uuid.Parse(req.Msg.BlockerTaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	blockedID, err := uuid.Parse(req.Msg.BlockedTaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := h.svc.RemoveDependency(ctx, blockerID, blockedID); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&taskv1.RemoveTaskDependencyResponse{}), nil
+}
+
+func (h *TaskHandler) ListTaskDependencies(ctx context.Context, req *connect.Request[taskv1.ListTaskDependenciesRequest]) (*connect.Response[taskv1.ListTaskDependenciesResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	deps, err := h.svc.ListDependencies(ctx, taskID)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	resp := &taskv1.ListTaskDependenciesResponse{
+		BlockedBy: make([]*taskv1.TaskDependency, len(deps.BlockedBy)),
+		Blocking:  make([]*taskv1.TaskDependency, len(deps.Blocking)),
+	}
+	for i := range deps.BlockedBy {
+		resp.BlockedBy[i] = dependencyToProto(&deps.BlockedBy[i])
+	}
+	for i := range deps.Blocking {
+		resp.Blocking[i] = dependencyToProto(&deps.Blocking[i])
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func (h *TaskHandler) GetTaskHistory(ctx context.Context, req *connect.Request[taskv1.GetTaskHistoryRequest]) (*connect.Response[taskv1.GetTaskHistoryResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.ListTaskHistoryParams{TaskID: taskID}
+	if req.Msg.Pagination != nil {
+		params.PageSize = req.Msg.Pagination.PageSize
+		params.PageToken = req.Msg.Pagination.PageToken
+	}
+	list, err := h.svc.GetHistory(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	entries := make([]*taskv1.TaskHistoryEntry, len(list.Entries))
+	for i := range list.Entries {
+		entry, err := historyEntryToProto(&list.Entries[i])
+		if err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+		entries[i] = entry
+	}
+	return connect.NewResponse(&taskv1.GetTaskHistoryResponse{
+		Entries: entries,
+		Pagination: &commonv1.PaginationResponse{
+			NextPageToken: list.NextPageToken,
+			TotalCount:    list.TotalCount,
+		},
+	}), nil
+}
+
+func (h *TaskHandler) SetTaskRecurrence(ctx context.Context, req *connect.Request[taskv1.SetTaskRecurrenceRequest]) (*connect.Response[taskv1.SetTaskRecurrenceResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	rec, err := h.recurrenceSvc.Set(ctx, taskID, req.Msg.Rrule)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&taskv1.SetTaskRecurrenceResponse{
+		Recurrence: recurrenceToProto(rec),
+	}), nil
+}
+
+func (h *TaskHandler) GetTaskRecurrence(ctx context.Context, req *connect.Request[taskv1.GetTaskRecurrenceRequest]) (*connect.Response[taskv1.GetTaskRecurrenceResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	rec, err := h.recurrenceSvc.Get(ctx, taskID)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&taskv1.GetTaskRecurrenceResponse{
+		Recurrence: recurrenceToProto(rec),
+	}), nil
+}
+
+func (h *TaskHandler) CancelTaskRecurrence(ctx context.Context, req *connect.Request[taskv1.CancelTaskRecurrenceRequest]) (*connect.Response[taskv1.CancelTaskRecurrenceResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := h.recurrenceSvc.Cancel(ctx, taskID); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&taskv1.CancelTaskRecurrenceResponse{}), nil
+}
+
+func (h *TaskHandler) WatchTask(ctx context.Context, req *connect.Request[taskv1.WatchTaskRequest]) (*connect.Response[taskv1.WatchTaskResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	w, err := h.watcherSvc.WatchTask(ctx, taskID, req.Msg.UserId)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&taskv1.WatchTaskResponse{
+		Watcher: watcherToProto(w),
+	}), nil
+}
+
+func (h *TaskHandler) UnwatchTask(ctx context.Context, req *connect.Request[taskv1.UnwatchTaskRequest]) (*connect.Response[taskv1.UnwatchTaskResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := h.watcherSvc.UnwatchTask(ctx, taskID, req.Msg.UserId); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&taskv1.UnwatchTaskResponse{}), nil
+}
+
+func (h *TaskHandler) ListTaskWatchers(ctx context.Context, req *connect.Request[taskv1.ListTaskWatchersRequest]) (*connect.Response[taskv1.ListTaskWatchersResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	watchers, err := h.watcherSvc.ListTaskWatchers(ctx, taskID)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	resp := &taskv1.ListTaskWatchersResponse{
+		Watchers: make([]*commonv1.Watcher, len(watchers)),
+	}
+	for i := range watchers {
+		resp.Watchers[i] = watcherToProto(&watchers[i])
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func recurrenceToProto(r *repository.TaskRecurrence) *taskv1.TaskRecurrence {
+	proto := &taskv1.TaskRecurrence{
+		TaskId:     r.TaskID.String(),
+		Rrule:      r.RRule,
+		Dtstart:    timestamppb.New(r.DTStart),
+		Occurrence: r.Occurrence,
+		CreatedAt:  timestamppb.New(r.CreatedAt),
+		UpdatedAt:  timestamppb.New(r.UpdatedAt),
+	}
+	if r.NextDueDate != nil {
+		proto.NextDueDate = timestamppb.New(*r.NextDueDate)
+	}
+	return proto
+}
+
+var historyOperations = map[string]taskv1.TaskHistoryOperation{
+	"create":  taskv1.TaskHistoryOperation_TASK_HISTORY_OPERATION_CREATE,
+	"update":  taskv1.TaskHistoryOperation_TASK_HISTORY_OPERATION_UPDATE,
+	"delete":  taskv1.TaskHistoryOperation_TASK_HISTORY_OPERATION_DELETE,
+	"restore": taskv1.TaskHistoryOperation_TASK_HISTORY_OPERATION_RESTORE,
+}
+
+func historyEntryToProto(e *repository.TaskHistoryEntry) (*taskv1.TaskHistoryEntry, error) {
+	var changes map[string]struct {
+		Old any `json:"old"`
+		New any `json:"new"`
+	}
+	if err := json.Unmarshal(e.Changes, &changes); err != nil {
+		return nil, err
+	}
+	fields := make([]string, 0, len(changes))
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	proto := &taskv1.TaskHistoryEntry{
+		Id:        e.ID.String(),
+		TaskId:    e.TaskID.String(),
+		Operation: historyOperations[e.Operation],
+		ActorId:   e.ActorID,
+		CreatedAt: timestamppb.New(e.CreatedAt),
+	}
+	for _, field := range fields {
+		oldValue, err := structpb.NewValue(changes[field].Old)
+		if err != nil {
+			return nil, err
+		}
+		newValue, err := structpb.NewValue(changes[field].New)
+		if err != nil {
+			return nil, err
+		}
+		proto.Changes = append(proto.Changes, &taskv1.TaskFieldChange{
+			Field:    field,
+			OldValue: oldValue,
+			NewValue: newValue,
+		})
+	}
+	return proto, nil
+}
+
+func dependencyToProto(d *repository.TaskDependency) *taskv1.TaskDependency {
+	return &taskv1.TaskDependency{
+		BlockerTaskId: d.BlockerTaskID.String(),
+		BlockedTaskId: d.BlockedTaskID.String(),
+		CreatedAt:     timestamppb.New(d.CreatedAt),
+	}
+}
+
+var watchReasons = map[string]commonv1.WatchReason{
+	"manual":    commonv1.WatchReason_WATCH_REASON_MANUAL,
+	"assignee":  commonv1.WatchReason_WATCH_REASON_ASSIGNEE,
+	"commenter": commonv1.WatchReason_WATCH_REASON_COMMENTER,
+}
+
+func watcherToProto(w *repository.Watcher) *commonv1.Watcher {
+	return &commonv1.Watcher{
+		UserId:    w.UserID,
+		Reason:    watchReasons[w.Reason],
+		CreatedAt: timestamppb.New(w.CreatedAt),
+	}
+}
+
 func taskToProto(t *repository.Task) (*taskv1.Task, error) {
 	proto := &taskv1.Task{
 		Id:          t.ID.String(),
@@ -242,12 +761,40 @@ func taskToProto(t *repository.Task) (*taskv1.Task, error) {
 		Status:      t.Status,
 		Priority:    t.Priority,
 		AssignedTo:  t.AssignedTo,
+		Assignees:   t.Assignees,
+		Rank:        t.Rank,
+		Version:     t.Version,
 		CreatedAt:   timestamppb.New(t.CreatedAt),
 		UpdatedAt:   timestamppb.New(t.UpdatedAt),
+		Progress: &taskv1.TaskProgress{
+			DoneSubtasks:  t.DoneSubtaskCount,
+			TotalSubtasks: t.SubtaskCount,
+		},
+	}
+	if t.ParentTaskID != nil {
+		proto.ParentTaskId = t.ParentTaskID.String()
 	}
 	if t.DueDate != nil {
 		proto.DueDate = timestamppb.New(*t.DueDate)
 	}
+	if t.DeletedAt != nil {
+		proto.DeletedAt = timestamppb.New(*t.DeletedAt)
+	}
+	if t.OriginalEstimate != nil {
+		proto.OriginalEstimate = secondsToProto(*t.OriginalEstimate)
+	}
+	if t.RemainingEstimate != nil {
+		proto.RemainingEstimate = secondsToProto(*t.RemainingEstimate)
+	}
+	for _, id := range t.BlockedBy {
+		proto.BlockedByTaskIds = append(proto.BlockedByTaskIds, id.String())
+	}
+	for _, id := range t.Blocking {
+		proto.BlockingTaskIds = append(proto.BlockingTaskIds, id.String())
+	}
+	for _, id := range t.LabelIDs {
+		proto.LabelIds = append(proto.LabelIds, id.String())
+	}
 	if len(t.Metadata) > 0 {
 		var m map[string]any
 		if err := json.Unmarshal(t.Metadata, &m); err != nil {
@@ -261,3 +808,183 @@ func taskToProto(t *repository.Task) (*taskv1.Task, error) {
 	}
 	return proto, nil
 }
+
+// taskAssignees reads the assignees of a request. Older clients only send
+// assigned_to, which then is the only assignee; assignees wins when both
+// are set.
+func taskAssignees(assignedTo string, assignees []string) []string {
+	if len(assignees) == 0 && assignedTo != "" {
+		return []string{assignedTo}
+	}
+	return assignees
+}
+
+// assigneeMask folds the assigned_to path of an update mask into
+// assignees, returning the mask and the assignees to write. Writing
+// assigned_to replaces every assignee with it.
+func assigneeMask(mask []string, assignedTo string, assignees []string) ([]string, []string) {
+	if len(mask) == 0 {
+		return mask, taskAssignees(assignedTo, assignees)
+	}
+	mask = slices.Clone(mask)
+	i := slices.Index(mask, "assigned_to")
+	switch {
+	case i < 0:
+		return mask, assignees
+	case slices.Contains(mask, "assignees"):
+		return slices.Delete(mask, i, i+1), assignees
+	}
+	mask[i] = "assignees"
+	return mask, taskAssignees(assignedTo, nil)
+}
+
+var taskSortFields = map[taskv1.TaskSortField]string{
+	taskv1.TaskSortField_TASK_SORT_FIELD_UNSPECIFIED: "",
+	taskv1.TaskSortField_TASK_SORT_FIELD_CREATED_AT:  "created_at",
+	taskv1.TaskSortField_TASK_SORT_FIELD_UPDATED_AT:  "updated_at",
+	taskv1.TaskSortField_TASK_SORT_FIELD_DUE_DATE:    "due_date",
+	taskv1.TaskSortField_TASK_SORT_FIELD_PRIORITY:    "priority",
+	taskv1.TaskSortField_TASK_SORT_FIELD_TITLE:       "title",
+	taskv1.TaskSortField_TASK_SORT_FIELD_STATUS:      "status",
+}
+
+// listTasksParams converts the filters, order and pagination of a
+// ListTasksRequest.
+func listTasksParams(workspaceID uuid.UUID, msg *taskv1.ListTasksRequest) (repository.ListTasksParams, error) {
+	params := repository.ListTasksParams{
+		WorkspaceID: workspaceID,
+		Status:      msg.Status,
+		Priority:    msg.Priority,
+		AssignedTo:  msg.AssignedTo,
+	}
+	var err error
+	if msg.ProjectId != "" {
+		if params.ProjectID, err = uuid.Parse(msg.ProjectId); err != nil {
+			return params, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	if msg.ParentTaskId != "" {
+		if params.ParentTaskID, err = uuid.Parse(msg.ParentTaskId); err != nil {
+			return params, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	if params.LabelIDsAny, err = parseUUIDSet(msg.LabelIdsAny); err != nil {
+		return params, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if params.LabelIDsAll, err = parseUUIDSet(msg.LabelIdsAll); err != nil {
+		return params, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	for _, f := range msg.Metadata {
+		filter, err := metadataFilter(f)
+		if err != nil {
+			return params, err
+		}
+		params.Metadata = append(params.Metadata, filter)
+	}
+	if msg.Query != "" {
+		if params.Query, err = taskquery.Parse(msg.Query); err != nil {
+			return params, toConnectError(fmt.Errorf("%w: %w", repository.ErrInvalidInput, err))
+		}
+	}
+	params.Due = timeRange(msg.DueAfter, msg.DueBefore)
+	params.Created = timeRange(msg.CreatedAfter, msg.CreatedBefore)
+	params.Updated = timeRange(msg.UpdatedSince, nil)
+	params.Overdue = msg.Overdue
+	params.NoDueDate = msg.NoDueDate
+	params.Sort = repository.Sort{Field: taskSortFields[msg.SortBy], Desc: sortDesc(msg.SortOrder)}
+	params.OrderByRank = msg.Order == taskv1.TaskOrder_TASK_ORDER_RANK
+	if msg.Pagination != nil {
+		params.PageSize = msg.Pagination.PageSize
+		params.PageToken = msg.Pagination.PageToken
+	}
+	return params, nil
+}
+
+// taskSelection converts the task_ids or filter of a bulk request. The
+// filter's own workspace_id is ignored in favour of the request's.
+func taskSelection(workspaceID string, taskIDs []string, filter *taskv1.ListTasksRequest) (*repository.TaskSelection, error) {
+	wsID, err := uuid.Parse(workspaceID)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	sel := &repository.TaskSelection{WorkspaceID: wsID}
+	if sel.IDs, err = parseUUIDSet(taskIDs); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if filter != nil {
+		params, err := listTasksParams(wsID, filter)
+		if err != nil {
+			return nil, err
+		}
+		sel.Filter = &params
+	}
+	return sel, nil
+}
+
+func metadataFilter(f *taskv1.MetadataFilter) (repository.MetadataFilter, error) {
+	filter := repository.MetadataFilter{Key: f.Key}
+	var value *structpb.Value
+	switch p := f.Predicate.(type) {
+	case *taskv1.MetadataFilter_Equals:
+		filter.Op, value = "equals", p.Equals
+	case *taskv1.MetadataFilter_Contains:
+		filter.Op, value = "contains", p.Contains
+	case *taskv1.MetadataFilter_Exists:
+		filter.Op, filter.Exists = "exists", p.Exists
+	case *taskv1.MetadataFilter_Range:
+		filter.Op = "range"
+		if p.Range != nil {
+			filter.Min, filter.Max = p.Range.Min, p.Range.Max
+		}
+	}
+	if value != nil {
+		b, err := json.Marshal(value.AsInterface())
+		if err != nil {
+			return filter, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+		filter.Value = b
+	}
+	return filter, nil
+}
+
+func bulkErrorsToProto(errs []repository.BulkError) []*taskv1.BulkTaskError {
+	out := make([]*taskv1.BulkTaskError, len(errs))
+	for i, e := range errs {
+		out[i] = &taskv1.BulkTaskError{
+			TaskId:          e.TaskID.String(),
+			Error:           e.Error,
+			FieldViolations: fieldViolationsToProto(e.Violations),
+		}
+	}
+	return out
+}
+
+// parseUUIDSet parses a list of UUID strings, dropping duplicates.
+func parseUUIDSet(values []string) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool, len(values))
+	for _, v := range values {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// durationSeconds converts an optional duration to whole seconds, dropping
+// any fraction. A nil duration yields nil.
+func durationSeconds(d *durationpb.Duration) (*int64, error) {
+	if d == nil {
+		return nil, nil
+	}
+	if err := d.CheckValid(); err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	seconds := d.GetSeconds()
+	return &seconds, nil
+}
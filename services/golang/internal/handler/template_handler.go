@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	commonv1 "github.com/igorrmotta/api-corestack/services/golang/gen/common/v1"
+	taskv1 "github.com/igorrmotta/api-corestack/services/golang/gen/task/v1"
+	templatev1 "github.com/igorrmotta/api-corestack/services/golang/gen/template/v1"
+	"github.com/igorrmotta/api-corestack/services/golang/gen/template/v1/templatev1connect"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/service"
+)
+
+type TemplateHandler struct {
+	templatev1connect.UnimplementedTemplateServiceHandler
+	svc *service.TemplateService
+}
+
+func NewTemplateHandler(svc *service.TemplateService) *TemplateHandler {
+	return &TemplateHandler{svc: svc}
+}
+
+func (h *TemplateHandler) CreateTemplate(ctx context.Context, req *connect.Request[templatev1.CreateTemplateRequest]) (*connect.Response[templatev1.CreateTemplateResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.CreateTemplateParams{
+		ProjectID:   projectID,
+		Name:        req.Msg.Name,
+		Title:       req.Msg.Title,
+		Description: req.Msg.Description,
+		Priority:    req.Msg.Priority,
+	}
+	if params.Metadata, err = structToJSON(req.Msg.Metadata); err != nil {
+		return nil, err
+	}
+	if params.Subtasks, err = templateSubtasks(req.Msg.Subtasks); err != nil {
+		return nil, err
+	}
+	tt, err := h.svc.Create(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	proto, err := templateToProto(tt)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&templatev1.CreateTemplateResponse{Template: proto}), nil
+}
+
+func (h *TemplateHandler) GetTemplate(ctx context.Context, req *connect.Request[templatev1.GetTemplateRequest]) (*connect.Response[templatev1.GetTemplateResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	tt, err := h.svc.GetByID(ctx, id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	proto, err := templateToProto(tt)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&templatev1.GetTemplateResponse{Template: proto}), nil
+}
+
+var templateSortFields = map[templatev1.TemplateSortField]string{
+	templatev1.TemplateSortField_TEMPLATE_SORT_FIELD_UNSPECIFIED: "",
+	templatev1.TemplateSortField_TEMPLATE_SORT_FIELD_NAME:        "name",
+	templatev1.TemplateSortField_TEMPLATE_SORT_FIELD_CREATED_AT:  "created_at",
+	templatev1.TemplateSortField_TEMPLATE_SORT_FIELD_UPDATED_AT:  "updated_at",
+}
+
+func (h *TemplateHandler) ListTemplates(ctx context.Context, req *connect.Request[templatev1.ListTemplatesRequest]) (*connect.Response[templatev1.ListTemplatesResponse], error) {
+	projectID, err := uuid.Parse(req.Msg.ProjectId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.ListTemplatesParams{
+		ProjectID: projectID,
+		Sort:      repository.Sort{Field: templateSortFields[req.Msg.SortBy], Desc: sortDesc(req.Msg.SortOrder)},
+	}
+	if req.Msg.Pagination != nil {
+		params.PageSize = req.Msg.Pagination.PageSize
+		params.PageToken = req.Msg.Pagination.PageToken
+	}
+	list, err := h.svc.List(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	templates := make([]*templatev1.TaskTemplate, len(list.Templates))
+	for i := range list.Templates {
+		if templates[i], err = templateToProto(&list.Templates[i]); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+	return connect.NewResponse(&templatev1.ListTemplatesResponse{
+		Templates: templates,
+		Pagination: &commonv1.PaginationResponse{
+			NextPageToken: list.NextPageToken,
+			TotalCount:    list.TotalCount,
+		},
+	}), nil
+}
+
+func (h *TemplateHandler) UpdateTemplate(ctx context.Context, req *connect.Request[templatev1.UpdateTemplateRequest]) (*connect.Response[templatev1.UpdateTemplateResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	mask, err := updateMaskPaths(req.Msg.UpdateMask, "name", "title", "description", "priority", "metadata", "subtasks")
+	if err != nil {
+		return nil, err
+	}
+	params := repository.UpdateTemplateParams{
+		ID:          id,
+		Name:        req.Msg.Name,
+		Title:       req.Msg.Title,
+		Description: req.Msg.Description,
+		Priority:    req.Msg.Priority,
+		UpdateMask:  mask,
+	}
+	if params.Metadata, err = structToJSON(req.Msg.Metadata); err != nil {
+		return nil, err
+	}
+	if params.Subtasks, err = templateSubtasks(req.Msg.Subtasks); err != nil {
+		return nil, err
+	}
+	tt, err := h.svc.Update(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	proto, err := templateToProto(tt)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	return connect.NewResponse(&templatev1.UpdateTemplateResponse{Template: proto}), nil
+}
+
+func (h *TemplateHandler) DeleteTemplate(ctx context.Context, req *connect.Request[templatev1.DeleteTemplateRequest]) (*connect.Response[templatev1.DeleteTemplateResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := h.svc.Delete(ctx, id); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&templatev1.DeleteTemplateResponse{}), nil
+}
+
+func (h *TemplateHandler) InstantiateTemplate(ctx context.Context, req *connect.Request[templatev1.InstantiateTemplateRequest]) (*connect.Response[templatev1.InstantiateTemplateResponse], error) {
+	id, err := uuid.Parse(req.Msg.TemplateId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	task, subtasks, err := h.svc.Instantiate(ctx, id, req.Msg.Variables)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	resp := &templatev1.InstantiateTemplateResponse{
+		Subtasks: make([]*taskv1.Task, len(subtasks)),
+	}
+	if resp.Task, err = taskToProto(task); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+	for i := range subtasks {
+		if resp.Subtasks[i], err = taskToProto(&subtasks[i]); err != nil {
+			return nil, connect.NewError(connect.CodeInternal, err)
+		}
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func templateToProto(tt *repository.TaskTemplate) (*templatev1.TaskTemplate, error) {
+	proto := &templatev1.TaskTemplate{
+		Id:          tt.ID.String(),
+		WorkspaceId: tt.WorkspaceID.String(),
+		ProjectId:   tt.ProjectID.String(),
+		Name:        tt.Name,
+		Title:       tt.Title,
+		Description: tt.Description,
+		Priority:    tt.Priority,
+		Subtasks:    make([]*templatev1.TemplateSubtask, len(tt.Subtasks)),
+		Variables:   tt.Variables(),
+		CreatedAt:   timestamppb.New(tt.CreatedAt),
+		UpdatedAt:   timestamppb.New(tt.UpdatedAt),
+	}
+	var err error
+	if proto.Metadata, err = jsonToStruct(tt.Metadata); err != nil {
+		return nil, err
+	}
+	for i, sub := range tt.Subtasks {
+		proto.Subtasks[i] = &templatev1.TemplateSubtask{
+			Title:       sub.Title,
+			Description: sub.Description,
+			Priority:    sub.Priority,
+		}
+		if proto.Subtasks[i].Metadata, err = jsonToStruct(sub.Metadata); err != nil {
+			return nil, err
+		}
+	}
+	return proto, nil
+}
+
+func templateSubtasks(subtasks []*templatev1.TemplateSubtask) ([]repository.TemplateSubtask, error) {
+	out := make([]repository.TemplateSubtask, len(subtasks))
+	for i, sub := range subtasks {
+		metadata, err := structToJSON(sub.Metadata)
+		if err != nil {
+			return nil, err
+		}
+		out[i] = repository.TemplateSubtask{
+			Title:       sub.Title,
+			Description: sub.Description,
+			Priority:    sub.Priority,
+			Metadata:    metadata,
+		}
+	}
+	return out, nil
+}
+
+// structToJSON encodes request metadata; nil stays nil.
+func structToJSON(s *structpb.Struct) (json.RawMessage, error) {
+	if s == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	return b, nil
+}
+
+// jsonToStruct decodes stored metadata; empty metadata is nil.
+func jsonToStruct(b json.RawMessage) (*structpb.Struct, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(m)
+}
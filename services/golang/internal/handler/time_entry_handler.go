@@ -0,0 +1,232 @@
+package handler
+
+import (
+	"context"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	commonv1 "github.com/igorrmotta/api-corestack/services/golang/gen/common/v1"
+	timeentryv1 "github.com/igorrmotta/api-corestack/services/golang/gen/timeentry/v1"
+	"github.com/igorrmotta/api-corestack/services/golang/gen/timeentry/v1/timeentryv1connect"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/service"
+)
+
+type TimeEntryHandler struct {
+	timeentryv1connect.UnimplementedTimeEntryServiceHandler
+	svc *service.TimeEntryService
+}
+
+func NewTimeEntryHandler(svc *service.TimeEntryService) *TimeEntryHandler {
+	return &TimeEntryHandler{svc: svc}
+}
+
+func (h *TimeEntryHandler) CreateTimeEntry(ctx context.Context, req *connect.Request[timeentryv1.CreateTimeEntryRequest]) (*connect.Response[timeentryv1.CreateTimeEntryResponse], error) {
+	taskID, err := uuid.Parse(req.Msg.TaskId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.CreateTimeEntryParams{
+		TaskID: taskID,
+		UserID: req.Msg.UserId,
+		Note:   req.Msg.Note,
+	}
+	if req.Msg.StartedAt != nil {
+		params.StartedAt = req.Msg.StartedAt.AsTime()
+	}
+	if params.DurationSeconds, err = entrySeconds(req.Msg.Duration); err != nil {
+		return nil, err
+	}
+	e, err := h.svc.Create(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&timeentryv1.CreateTimeEntryResponse{
+		TimeEntry: timeEntryToProto(e),
+	}), nil
+}
+
+func (h *TimeEntryHandler) GetTimeEntry(ctx context.Context, req *connect.Request[timeentryv1.GetTimeEntryRequest]) (*connect.Response[timeentryv1.GetTimeEntryResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	e, err := h.svc.GetByID(ctx, id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&timeentryv1.GetTimeEntryResponse{
+		TimeEntry: timeEntryToProto(e),
+	}), nil
+}
+
+var timeEntrySortFields = map[timeentryv1.TimeEntrySortField]string{
+	timeentryv1.TimeEntrySortField_TIME_ENTRY_SORT_FIELD_UNSPECIFIED: "",
+	timeentryv1.TimeEntrySortField_TIME_ENTRY_SORT_FIELD_STARTED_AT:  "started_at",
+	timeentryv1.TimeEntrySortField_TIME_ENTRY_SORT_FIELD_DURATION:    "duration",
+	timeentryv1.TimeEntrySortField_TIME_ENTRY_SORT_FIELD_UPDATED_AT:  "updated_at",
+}
+
+func (h *TimeEntryHandler) ListTimeEntries(ctx context.Context, req *connect.Request[timeentryv1.ListTimeEntriesRequest]) (*connect.Response[timeentryv1.ListTimeEntriesResponse], error) {
+	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.ListTimeEntriesParams{
+		WorkspaceID: workspaceID,
+		UserID:      req.Msg.UserId,
+		Range:       timeRange(req.Msg.StartTime, req.Msg.EndTime),
+		Sort:        repository.Sort{Field: timeEntrySortFields[req.Msg.SortBy], Desc: sortDesc(req.Msg.SortOrder)},
+	}
+	if req.Msg.TaskId != "" {
+		if params.TaskID, err = uuid.Parse(req.Msg.TaskId); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	if req.Msg.Pagination != nil {
+		params.PageSize = req.Msg.Pagination.PageSize
+		params.PageToken = req.Msg.Pagination.PageToken
+	}
+	list, err := h.svc.List(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	entries := make([]*timeentryv1.TimeEntry, len(list.TimeEntries))
+	for i := range list.TimeEntries {
+		entries[i] = timeEntryToProto(&list.TimeEntries[i])
+	}
+	return connect.NewResponse(&timeentryv1.ListTimeEntriesResponse{
+		TimeEntries: entries,
+		Pagination: &commonv1.PaginationResponse{
+			NextPageToken: list.NextPageToken,
+			TotalCount:    list.TotalCount,
+		},
+	}), nil
+}
+
+func (h *TimeEntryHandler) UpdateTimeEntry(ctx context.Context, req *connect.Request[timeentryv1.UpdateTimeEntryRequest]) (*connect.Response[timeentryv1.UpdateTimeEntryResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	mask, err := updateMaskPaths(req.Msg.UpdateMask, "started_at", "duration", "note")
+	if err != nil {
+		return nil, err
+	}
+	params := repository.UpdateTimeEntryParams{
+		ID:         id,
+		Note:       req.Msg.Note,
+		UpdateMask: mask,
+	}
+	if req.Msg.StartedAt != nil {
+		params.StartedAt = req.Msg.StartedAt.AsTime()
+	}
+	if params.DurationSeconds, err = entrySeconds(req.Msg.Duration); err != nil {
+		return nil, err
+	}
+	e, err := h.svc.Update(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&timeentryv1.UpdateTimeEntryResponse{
+		TimeEntry: timeEntryToProto(e),
+	}), nil
+}
+
+func (h *TimeEntryHandler) DeleteTimeEntry(ctx context.Context, req *connect.Request[timeentryv1.DeleteTimeEntryRequest]) (*connect.Response[timeentryv1.DeleteTimeEntryResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	if err := h.svc.Delete(ctx, id); err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&timeentryv1.DeleteTimeEntryResponse{}), nil
+}
+
+var timeTotalsGroupBy = map[timeentryv1.TimeTotalsGroupBy]string{
+	timeentryv1.TimeTotalsGroupBy_TIME_TOTALS_GROUP_BY_UNSPECIFIED: "",
+	timeentryv1.TimeTotalsGroupBy_TIME_TOTALS_GROUP_BY_TASK:        "task",
+	timeentryv1.TimeTotalsGroupBy_TIME_TOTALS_GROUP_BY_PROJECT:     "project",
+	timeentryv1.TimeTotalsGroupBy_TIME_TOTALS_GROUP_BY_USER:        "user",
+	timeentryv1.TimeTotalsGroupBy_TIME_TOTALS_GROUP_BY_ASSIGNEE:    "assignee",
+}
+
+func (h *TimeEntryHandler) GetTimeTotals(ctx context.Context, req *connect.Request[timeentryv1.GetTimeTotalsRequest]) (*connect.Response[timeentryv1.GetTimeTotalsResponse], error) {
+	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	params := repository.TimeTotalsParams{
+		WorkspaceID: workspaceID,
+		Range:       timeRange(req.Msg.StartTime, req.Msg.EndTime),
+		GroupBy:     timeTotalsGroupBy[req.Msg.GroupBy],
+	}
+	if req.Msg.ProjectId != "" {
+		if params.ProjectID, err = uuid.Parse(req.Msg.ProjectId); err != nil {
+			return nil, connect.NewError(connect.CodeInvalidArgument, err)
+		}
+	}
+	totals, err := h.svc.Totals(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	resp := &timeentryv1.GetTimeTotalsResponse{
+		Totals:     make([]*timeentryv1.TimeTotal, len(totals.Totals)),
+		Duration:   secondsToProto(totals.DurationSeconds),
+		EntryCount: totals.EntryCount,
+	}
+	for i, t := range totals.Totals {
+		resp.Totals[i] = &timeentryv1.TimeTotal{
+			Key:        t.Key,
+			Duration:   secondsToProto(t.DurationSeconds),
+			EntryCount: t.EntryCount,
+		}
+	}
+	return connect.NewResponse(resp), nil
+}
+
+func timeEntryToProto(e *repository.TimeEntry) *timeentryv1.TimeEntry {
+	return &timeentryv1.TimeEntry{
+		Id:          e.ID.String(),
+		TaskId:      e.TaskID.String(),
+		WorkspaceId: e.WorkspaceID.String(),
+		UserId:      e.UserID,
+		StartedAt:   timestamppb.New(e.StartedAt),
+		Duration:    secondsToProto(e.DurationSeconds),
+		Note:        e.Note,
+		CreatedAt:   timestamppb.New(e.CreatedAt),
+		UpdatedAt:   timestamppb.New(e.UpdatedAt),
+	}
+}
+
+// entrySeconds is durationSeconds for a required duration; nil yields 0,
+// which the service rejects.
+func entrySeconds(d *durationpb.Duration) (int64, error) {
+	seconds, err := durationSeconds(d)
+	if err != nil || seconds == nil {
+		return 0, err
+	}
+	return *seconds, nil
+}
+
+func secondsToProto(seconds int64) *durationpb.Duration {
+	return durationpb.New(time.Duration(seconds) * time.Second)
+}
+
+func timeRange(start, end *timestamppb.Timestamp) repository.TimeRange {
+	var r repository.TimeRange
+	if start != nil {
+		t := start.AsTime()
+		r.From = &t
+	}
+	if end != nil {
+		t := end.AsTime()
+		r.To = &t
+	}
+	return r
+}
@@ -3,9 +3,12 @@ package handler
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 
 	"connectrpc.com/connect"
 	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	commonv1 "github.com/igorrmotta/api-corestack/services/golang/gen/common/v1"
@@ -13,6 +16,7 @@ import (
 	"github.com/igorrmotta/api-corestack/services/golang/gen/workspace/v1/workspacev1connect"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/service"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/taskquery"
 )
 
 type WorkspaceHandler struct {
@@ -51,8 +55,17 @@ func (h *WorkspaceHandler) GetWorkspace(ctx context.Context, req *connect.Reques
 	}), nil
 }
 
+var workspaceSortFields = map[workspacev1.WorkspaceSortField]string{
+	workspacev1.WorkspaceSortField_WORKSPACE_SORT_FIELD_UNSPECIFIED: "",
+	workspacev1.WorkspaceSortField_WORKSPACE_SORT_FIELD_CREATED_AT:  "created_at",
+	workspacev1.WorkspaceSortField_WORKSPACE_SORT_FIELD_UPDATED_AT:  "updated_at",
+	workspacev1.WorkspaceSortField_WORKSPACE_SORT_FIELD_NAME:        "name",
+}
+
 func (h *WorkspaceHandler) ListWorkspaces(ctx context.Context, req *connect.Request[workspacev1.ListWorkspacesRequest]) (*connect.Response[workspacev1.ListWorkspacesResponse], error) {
-	var params repository.ListWorkspacesParams
+	params := repository.ListWorkspacesParams{
+		Sort: repository.Sort{Field: workspaceSortFields[req.Msg.SortBy], Desc: sortDesc(req.Msg.SortOrder)},
+	}
 	if req.Msg.Pagination != nil {
 		params.PageSize = req.Msg.Pagination.PageSize
 		params.PageToken = req.Msg.Pagination.PageToken
@@ -79,10 +92,16 @@ func (h *WorkspaceHandler) UpdateWorkspace(ctx context.Context, req *connect.Req
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
+	mask, err := updateMaskPaths(req.Msg.UpdateMask, "name", "slug")
+	if err != nil {
+		return nil, err
+	}
 	w, err := h.svc.Update(ctx, repository.UpdateWorkspaceParams{
-		ID:   id,
-		Name: req.Msg.Name,
-		Slug: req.Msg.Slug,
+		ID:         id,
+		Name:       req.Msg.Name,
+		Slug:       req.Msg.Slug,
+		UpdateMask: mask,
+		Version:    req.Msg.Version,
 	})
 	if err != nil {
 		return nil, toConnectError(err)
@@ -97,24 +116,132 @@ func (h *WorkspaceHandler) DeleteWorkspace(ctx context.Context, req *connect.Req
 	if err != nil {
 		return nil, connect.NewError(connect.CodeInvalidArgument, err)
 	}
-	if err := h.svc.Delete(ctx, id); err != nil {
+	if err := h.svc.Delete(ctx, id, req.Msg.Version); err != nil {
 		return nil, toConnectError(err)
 	}
 	return connect.NewResponse(&workspacev1.DeleteWorkspaceResponse{}), nil
 }
 
+func (h *WorkspaceHandler) ListDeletedWorkspaces(ctx context.Context, req *connect.Request[workspacev1.ListDeletedWorkspacesRequest]) (*connect.Response[workspacev1.ListDeletedWorkspacesResponse], error) {
+	var params repository.ListWorkspacesParams
+	if req.Msg.Pagination != nil {
+		params.PageSize = req.Msg.Pagination.PageSize
+		params.PageToken = req.Msg.Pagination.PageToken
+	}
+	list, err := h.svc.ListDeleted(ctx, params)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	workspaces := make([]*workspacev1.Workspace, len(list.Workspaces))
+	for i, w := range list.Workspaces {
+		workspaces[i] = workspaceToProto(&w)
+	}
+	return connect.NewResponse(&workspacev1.ListDeletedWorkspacesResponse{
+		Workspaces: workspaces,
+		Pagination: &commonv1.PaginationResponse{
+			NextPageToken: list.NextPageToken,
+			TotalCount:    list.TotalCount,
+		},
+	}), nil
+}
+
+func (h *WorkspaceHandler) RestoreWorkspace(ctx context.Context, req *connect.Request[workspacev1.RestoreWorkspaceRequest]) (*connect.Response[workspacev1.RestoreWorkspaceResponse], error) {
+	id, err := uuid.Parse(req.Msg.Id)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	w, err := h.svc.Restore(ctx, id)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&workspacev1.RestoreWorkspaceResponse{
+		Workspace: workspaceToProto(w),
+	}), nil
+}
+
+func (h *WorkspaceHandler) GetReminderSettings(ctx context.Context, req *connect.Request[workspacev1.GetReminderSettingsRequest]) (*connect.Response[workspacev1.GetReminderSettingsResponse], error) {
+	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	settings, err := h.svc.GetReminderSettings(ctx, workspaceID)
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&workspacev1.GetReminderSettingsResponse{
+		Settings: reminderSettingsToProto(settings),
+	}), nil
+}
+
+func (h *WorkspaceHandler) UpdateReminderSettings(ctx context.Context, req *connect.Request[workspacev1.UpdateReminderSettingsRequest]) (*connect.Response[workspacev1.UpdateReminderSettingsResponse], error) {
+	workspaceID, err := uuid.Parse(req.Msg.WorkspaceId)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	settings, err := h.svc.UpdateReminderSettings(ctx, repository.ReminderSettings{
+		WorkspaceID:  workspaceID,
+		DueSoonHours: req.Msg.DueSoonHours,
+		OverdueHours: req.Msg.OverdueHours,
+	})
+	if err != nil {
+		return nil, toConnectError(err)
+	}
+	return connect.NewResponse(&workspacev1.UpdateReminderSettingsResponse{
+		Settings: reminderSettingsToProto(settings),
+	}), nil
+}
+
+func reminderSettingsToProto(s *repository.ReminderSettings) *workspacev1.ReminderSettings {
+	proto := &workspacev1.ReminderSettings{
+		WorkspaceId:  s.WorkspaceID.String(),
+		DueSoonHours: s.DueSoonHours,
+		OverdueHours: s.OverdueHours,
+	}
+	if s.UpdatedAt != nil {
+		proto.UpdatedAt = timestamppb.New(*s.UpdatedAt)
+	}
+	return proto
+}
+
 func workspaceToProto(w *repository.Workspace) *workspacev1.Workspace {
-	return &workspacev1.Workspace{
+	proto := &workspacev1.Workspace{
 		Id:        w.ID.String(),
 		Name:      w.Name,
 		Slug:      w.Slug,
+		Version:   w.Version,
 		CreatedAt: timestamppb.New(w.CreatedAt),
 		UpdatedAt: timestamppb.New(w.UpdatedAt),
 	}
+	if w.DeletedAt != nil {
+		proto.DeletedAt = timestamppb.New(*w.DeletedAt)
+	}
+	return proto
 }
 
-// toConnectError maps repository errors to Connect RPC error codes.
+// toConnectError maps repository errors to Connect RPC error codes. Field
+// violations and query parse errors travel as error details.
 func toConnectError(err error) error {
+	var verr *repository.ValidationError
+	if errors.As(err, &verr) {
+		cerr := connect.NewError(connect.CodeInvalidArgument, err)
+		if detail, derr := connect.NewErrorDetail(&commonv1.FieldViolations{
+			Violations: fieldViolationsToProto(verr.Violations),
+		}); derr == nil {
+			cerr.AddDetail(detail)
+		}
+		return cerr
+	}
+	var qerr *taskquery.Error
+	if errors.As(err, &qerr) {
+		cerr := connect.NewError(connect.CodeInvalidArgument, err)
+		if detail, derr := connect.NewErrorDetail(&commonv1.QueryError{
+			Position:    int32(qerr.Pos),
+			Description: qerr.Msg,
+		}); derr == nil {
+			cerr.AddDetail(detail)
+		}
+		return cerr
+	}
 	if errors.Is(err, repository.ErrNotFound) {
 		return connect.NewError(connect.CodeNotFound, err)
 	}
@@ -124,5 +251,52 @@ func toConnectError(err error) error {
 	if errors.Is(err, repository.ErrConflict) {
 		return connect.NewError(connect.CodeAlreadyExists, err)
 	}
+	if errors.Is(err, repository.ErrFailedPrecondition) {
+		return connect.NewError(connect.CodeFailedPrecondition, err)
+	}
+	if errors.Is(err, repository.ErrStaleVersion) {
+		return connect.NewError(connect.CodeAborted, err)
+	}
 	return connect.NewError(connect.CodeInternal, err)
 }
+
+func fieldViolationsToProto(violations []repository.FieldViolation) []*commonv1.FieldViolation {
+	if len(violations) == 0 {
+		return nil
+	}
+	out := make([]*commonv1.FieldViolation, len(violations))
+	for i, v := range violations {
+		out[i] = &commonv1.FieldViolation{Field: v.Field, Description: v.Description}
+	}
+	return out
+}
+
+// sortDesc converts a sort order to repository.Sort's Desc; unspecified
+// keeps the sort field's default direction.
+func sortDesc(order commonv1.SortOrder) *bool {
+	var desc bool
+	switch order {
+	case commonv1.SortOrder_SORT_ORDER_ASC:
+	case commonv1.SortOrder_SORT_ORDER_DESC:
+		desc = true
+	default:
+		return nil
+	}
+	return &desc
+}
+
+// updateMaskPaths returns the normalised paths of an update mask, rejecting
+// any path not in allowed. A nil or empty mask yields nil, meaning a full
+// update.
+func updateMaskPaths(mask *fieldmaskpb.FieldMask, allowed ...string) ([]string, error) {
+	if len(mask.GetPaths()) == 0 {
+		return nil, nil
+	}
+	mask.Normalize()
+	for _, path := range mask.GetPaths() {
+		if !slices.Contains(allowed, path) {
+			return nil, connect.NewError(connect.CodeInvalidArgument, fmt.Errorf("unknown update_mask path: %q", path))
+		}
+	}
+	return mask.GetPaths(), nil
+}
@@ -0,0 +1,27 @@
+// Package identity carries the calling user's ID through a request context.
+//
+// There is no authentication yet: the ID is whatever the client sends in the
+// X-User-Id header, and is used for attribution only (e.g. task history).
+package identity
+
+import "context"
+
+// Header is the request header the caller's user ID is read from.
+const Header = "X-User-Id"
+
+// MaxUserIDLength is the size, in characters, of the columns user IDs are
+// stored in.
+const MaxUserIDLength = 255
+
+type contextKey struct{}
+
+// WithUserID returns a copy of ctx carrying the given user ID.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, contextKey{}, userID)
+}
+
+// UserID returns the user ID stored in ctx, or "" if there is none.
+func UserID(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
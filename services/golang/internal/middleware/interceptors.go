@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"connectrpc.com/connect"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/identity"
 )
 
 // NewLoggingInterceptor returns a Connect interceptor that logs request details.
@@ -58,3 +62,21 @@ func NewRecoveryInterceptor() connect.UnaryInterceptorFunc {
 		}
 	}
 }
+
+// NewIdentityInterceptor returns a Connect interceptor that stores the
+// caller's user ID from the X-User-Id header in the request context. IDs
+// too long to be stored are rejected.
+func NewIdentityInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if userID := strings.TrimSpace(req.Header().Get(identity.Header)); userID != "" {
+				if utf8.RuneCountInString(userID) > identity.MaxUserIDLength {
+					return nil, connect.NewError(connect.CodeInvalidArgument,
+						fmt.Errorf("%s header must be at most %d characters", identity.Header, identity.MaxUserIDLength))
+				}
+				ctx = identity.WithUserID(ctx, userID)
+			}
+			return next(ctx, req)
+		}
+	}
+}
@@ -0,0 +1,38 @@
+// Package placeholder finds and fills the {{name}} placeholders of task
+// templates.
+//
+// A name starts with a letter or underscore followed by letters, digits or
+// underscores, and may be surrounded by spaces inside the braces, as in
+// "{{ customer }}". Anything else between double braces is plain text.
+package placeholder
+
+import (
+	"regexp"
+	"slices"
+)
+
+var pattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// Names returns the distinct placeholder names used in texts, sorted.
+func Names(texts ...string) []string {
+	var names []string
+	for _, text := range texts {
+		for _, m := range pattern.FindAllStringSubmatch(text, -1) {
+			names = append(names, m[1])
+		}
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// Fill replaces every placeholder of text with its value. Placeholders
+// without a value are left as they are; callers check Names first. Values
+// are inserted verbatim and never expanded themselves.
+func Fill(text string, values map[string]string) string {
+	return pattern.ReplaceAllStringFunc(text, func(m string) string {
+		if v, ok := values[pattern.FindStringSubmatch(m)[1]]; ok {
+			return v
+		}
+		return m
+	})
+}
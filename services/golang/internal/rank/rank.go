@@ -0,0 +1,219 @@
+// Package rank generates order keys for manual ordering.
+//
+// A key is an integer part followed by an optional fraction, all in base-62
+// digits (0-9, A-Z, a-z), so keys sort correctly with a plain byte-wise
+// comparison (COLLATE "C" in Postgres). The first character of the integer
+// part gives its length: 'a' to 'z' are followed by 1 to 26 digits, and 'Z'
+// down to 'A' by 1 to 26 digits for integers below "a0". A key after or
+// before every other one only counts the integer up or down, so appending to
+// a column grows keys with the logarithm of its size. A key between two
+// others extends the fraction, which never ends in '0' so there is always
+// room for another.
+package rank
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid is wrapped by every error returned for malformed bounds.
+var ErrInvalid = errors.New("invalid rank")
+
+const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// First is the key of the first task of an empty column.
+const First = "a0"
+
+// smallest is the lowest integer part; no key can sort before it, so it is
+// never handed out on its own.
+var smallest = "A" + strings.Repeat("0", 26)
+
+// Between returns a key that sorts strictly after a and strictly before b.
+// An empty a means "before everything" and an empty b "after everything",
+// so Between("", "") is a valid first key.
+func Between(a, b string) (string, error) {
+	if err := validate(a); err != nil {
+		return "", err
+	}
+	if err := validate(b); err != nil {
+		return "", err
+	}
+	if a != "" && b != "" && a >= b {
+		return "", fmt.Errorf("%w: %q is not before %q", ErrInvalid, a, b)
+	}
+
+	switch {
+	case a == "" && b == "":
+		return First, nil
+	case a == "":
+		ib := b[:integerLength(b[0])]
+		if ib == smallest {
+			return ib + midpoint("", b[len(ib):]), nil
+		}
+		if ib < b {
+			return ib, nil
+		}
+		if d := decrement(ib); d != smallest {
+			return d, nil
+		}
+		return smallest + midpoint("", ""), nil
+	case b == "":
+		ia := a[:integerLength(a[0])]
+		if i, ok := increment(ia); ok {
+			return i, nil
+		}
+		return ia + midpoint(a[len(ia):], ""), nil
+	default:
+		ia, ib := a[:integerLength(a[0])], b[:integerLength(b[0])]
+		if ia == ib {
+			return ia + midpoint(a[len(ia):], b[len(ib):]), nil
+		}
+		if i, ok := increment(ia); ok && i < b {
+			return i, nil
+		}
+		return ia + midpoint(a[len(ia):], ""), nil
+	}
+}
+
+// After returns a key that sorts strictly after a.
+func After(a string) (string, error) {
+	return Between(a, "")
+}
+
+func validate(key string) error {
+	if key == "" {
+		return nil
+	}
+	for i := 0; i < len(key); i++ {
+		if strings.IndexByte(digits, key[i]) < 0 {
+			return fmt.Errorf("%w: unexpected character %q in %q", ErrInvalid, key[i], key)
+		}
+	}
+	n := integerLength(key[0])
+	if n == 0 {
+		return fmt.Errorf("%w: %q does not start with a letter", ErrInvalid, key)
+	}
+	if len(key) < n {
+		return fmt.Errorf("%w: %q is shorter than its integer part", ErrInvalid, key)
+	}
+	if key[:n] == smallest && len(key) == n {
+		return fmt.Errorf("%w: %q is the smallest integer", ErrInvalid, key)
+	}
+	if len(key) > n && strings.HasSuffix(key, "0") {
+		return fmt.Errorf("%w: %q ends in 0", ErrInvalid, key)
+	}
+	return nil
+}
+
+// integerLength returns the length of an integer part, head included, from
+// its head, or 0 if head is not a letter.
+func integerLength(head byte) int {
+	switch {
+	case head >= 'a' && head <= 'z':
+		return int(head-'a') + 2
+	case head >= 'A' && head <= 'Z':
+		return int('Z'-head) + 2
+	}
+	return 0
+}
+
+// increment returns the integer part after x; ok is false for the largest.
+func increment(x string) (string, bool) {
+	head, digs := x[0], []byte(x[1:])
+	for i := len(digs) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, digs[i]) + 1
+		if d < len(digits) {
+			digs[i] = digits[d]
+			return string(head) + string(digs), true
+		}
+		digs[i] = digits[0]
+	}
+	// Every digit carried over: move to the next length.
+	switch head {
+	case 'Z':
+		return "a" + digits[:1], true
+	case 'z':
+		return "", false
+	}
+	head++
+	if head > 'a' {
+		digs = append(digs, digits[0])
+	} else {
+		digs = digs[:len(digs)-1]
+	}
+	return string(head) + string(digs), true
+}
+
+// decrement returns the integer part before x, which is not the smallest.
+func decrement(x string) string {
+	top := digits[len(digits)-1]
+	head, digs := x[0], []byte(x[1:])
+	for i := len(digs) - 1; i >= 0; i-- {
+		d := strings.IndexByte(digits, digs[i]) - 1
+		if d >= 0 {
+			digs[i] = digits[d]
+			return string(head) + string(digs)
+		}
+		digs[i] = top
+	}
+	// Every digit borrowed: move to the previous length.
+	if head == 'a' {
+		return "Z" + string(top)
+	}
+	head--
+	if head < 'Z' {
+		digs = append(digs, top)
+	} else {
+		digs = digs[:len(digs)-1]
+	}
+	return string(head) + string(digs)
+}
+
+// midpoint returns a fraction between fractions a and b, assuming a < b and
+// treating "" as 0 for a and 1 for b.
+func midpoint(a, b string) string {
+	if b != "" {
+		// Copy the common prefix, padding a with zeros.
+		n := 0
+		for n < len(b) && digitAt(a, n) == b[n] {
+			n++
+		}
+		if n > 0 {
+			rest := ""
+			if n < len(a) {
+				rest = a[n:]
+			}
+			return b[:n] + midpoint(rest, b[n:])
+		}
+	}
+
+	da := 0
+	if a != "" {
+		da = strings.IndexByte(digits, a[0])
+	}
+	db := len(digits)
+	if b != "" {
+		db = strings.IndexByte(digits, b[0])
+	}
+	if db-da > 1 {
+		return string(digits[(da+db)/2])
+	}
+	// The first digits are adjacent. If b has more digits, its first digit
+	// alone already sorts between a and b.
+	if len(b) > 1 {
+		return b[:1]
+	}
+	rest := ""
+	if len(a) > 1 {
+		rest = a[1:]
+	}
+	return string(digits[da]) + midpoint(rest, "")
+}
+
+func digitAt(s string, i int) byte {
+	if i < len(s) {
+		return s[i]
+	}
+	return '0'
+}
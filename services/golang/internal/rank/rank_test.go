@@ -0,0 +1,124 @@
+package rank
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestBetween(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", "a0"},
+		{"a0", "", "a1"},
+		{"az", "", "b00"},
+		{"z" + strings.Repeat("z", 26), "", "z" + strings.Repeat("z", 26) + "V"},
+		{"", "a0", "Zz"},
+		{"", "b00", "az"},
+		{"", smallest[:26] + "1", smallest + "V"},
+		{"", smallest + "1", smallest + "0V"},
+		{"a0", "a1", "a0V"},
+		{"a0", "a0V", "a0F"},
+		{"a0V", "a1", "a0k"},
+		{"a0", "a2", "a1"},
+		{"a1", "b00", "a2"},
+		{"Zz", "a01", "a0"},
+		{"a0", "a001", "a000V"},
+	}
+	for _, tt := range tests {
+		got, err := Between(tt.a, tt.b)
+		if err != nil {
+			t.Errorf("Between(%q, %q): %v", tt.a, tt.b, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Between(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+		if (tt.a != "" && got <= tt.a) || (tt.b != "" && got >= tt.b) {
+			t.Errorf("Between(%q, %q) = %q is out of order", tt.a, tt.b, got)
+		}
+	}
+}
+
+func TestBetweenInvalid(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"a1", "a0"},
+		{"a1", "a1"},
+		{"a0-", ""},
+		{"0V", ""},
+		{"b0", ""},
+		{"a10", ""},
+		{"", smallest},
+	}
+	for _, tt := range tests {
+		if got, err := Between(tt.a, tt.b); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Between(%q, %q) = %q, %v; want ErrInvalid", tt.a, tt.b, got, err)
+		}
+	}
+}
+
+// Appending to or prepending to a column must only grow keys with the
+// logarithm of its size, so the index on rank never outgrows a btree row.
+func TestKeyLengthAtEnds(t *testing.T) {
+	const n = 300000
+	last, first := "", ""
+	for i := 0; i < n; i++ {
+		next, err := After(last)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if last != "" && next <= last {
+			t.Fatalf("After(%q) = %q is not after it", last, next)
+		}
+		last = next
+
+		prev, err := Between("", first)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first != "" && prev >= first {
+			t.Fatalf("Between(\"\", %q) = %q is not before it", first, prev)
+		}
+		first = prev
+	}
+	// 62^3 < n < 62^4: four digits and a head.
+	if len(last) != 5 || len(first) != 5 {
+		t.Errorf("after %d keys: last %q, first %q; want 5 characters each", n, last, first)
+	}
+}
+
+// Inserting at random positions keeps every key valid and in order.
+func TestBetweenRandom(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var keys []string
+	for i := 0; i < 5000; i++ {
+		pos := rng.Intn(len(keys) + 1)
+		var a, b string
+		if pos > 0 {
+			a = keys[pos-1]
+		}
+		if pos < len(keys) {
+			b = keys[pos]
+		}
+		k, err := Between(a, b)
+		if err != nil {
+			t.Fatalf("Between(%q, %q): %v", a, b, err)
+		}
+		if err := validate(k); err != nil {
+			t.Fatalf("Between(%q, %q) = %q: %v", a, b, k, err)
+		}
+		keys = slices.Insert(keys, pos, k)
+	}
+	if !slices.IsSorted(keys) {
+		t.Fatal("keys are out of order")
+	}
+	for i := 1; i < len(keys); i++ {
+		if keys[i] == keys[i-1] {
+			t.Fatalf("duplicate key %q", keys[i])
+		}
+	}
+}
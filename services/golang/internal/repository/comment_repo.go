@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -31,6 +30,13 @@ func (r *CommentRepo) Create(ctx context.Context, params CreateCommentParams) (*
 	return &c, nil
 }
 
+// commentSortKeys are the orders List supports.
+var commentSortKeys = map[string]sortKey{
+	"":           {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"created_at": {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"updated_at": {expr: "%[1]s.updated_at", typ: "timestamptz", desc: true},
+}
+
 func (r *CommentRepo) List(ctx context.Context, params ListCommentsParams) (*CommentList, error) {
 	pageSize := params.PageSize
 	if pageSize <= 0 || pageSize > 100 {
@@ -46,43 +52,42 @@ func (r *CommentRepo) List(ctx context.Context, params ListCommentsParams) (*Com
 		return nil, fmt.Errorf("count comments: %w", err)
 	}
 
-	var rows pgx.Rows
-	if params.PageToken != "" {
-		cursorID, parseErr := uuid.Parse(params.PageToken)
-		if parseErr != nil {
-			return nil, fmt.Errorf("invalid page token: %w", parseErr)
-		}
-		rows, err = r.pool.Query(ctx,
-			`SELECT id, task_id, author_id, content, created_at, updated_at
-			 FROM task_comments WHERE task_id = $1 AND id < $2
-			 ORDER BY created_at DESC, id DESC LIMIT $3`,
-			params.TaskID, cursorID, pageSize+1,
-		)
-	} else {
-		rows, err = r.pool.Query(ctx,
-			`SELECT id, task_id, author_id, content, created_at, updated_at
-			 FROM task_comments WHERE task_id = $1
-			 ORDER BY created_at DESC, id DESC LIMIT $2`,
-			params.TaskID, pageSize+1,
-		)
+	orderBy, sortValue, cursorCond, err := keyset(commentSortKeys, params.Sort, "c", 2)
+	if err != nil {
+		return nil, err
 	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.pool.Query(ctx,
+		`SELECT c.id, c.task_id, c.author_id, c.content, c.created_at, c.updated_at, `+sortValue+`
+		 FROM task_comments c WHERE c.task_id = $1
+		   AND ($3::uuid IS NULL OR `+cursorCond+`)
+		 ORDER BY `+orderBy+` LIMIT $4`,
+		params.TaskID, cursorValue, cursorID, pageSize+1,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("list comments: %w", err)
 	}
 	defer rows.Close()
 
 	var comments []Comment
+	var value string
 	for rows.Next() {
 		var c Comment
-		if err := rows.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Content, &c.CreatedAt, &c.UpdatedAt); err != nil {
+		if err := rows.Scan(&c.ID, &c.TaskID, &c.AuthorID, &c.Content, &c.CreatedAt, &c.UpdatedAt, &value); err != nil {
 			return nil, fmt.Errorf("scan comment: %w", err)
 		}
 		comments = append(comments, c)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
 
 	var nextPageToken string
 	if len(comments) > int(pageSize) {
-		nextPageToken = comments[pageSize].ID.String()
+		nextPageToken = encodeCursor(value, comments[pageSize].ID.String())
 		comments = comments[:pageSize]
 	}
 
@@ -23,6 +23,7 @@ type CreateCommentParams struct {
 
 type ListCommentsParams struct {
 	TaskID    uuid.UUID
+	Sort      Sort // created_at or updated_at
 	PageSize  int32
 	PageToken string
 }
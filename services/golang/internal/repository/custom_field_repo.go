@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type CustomFieldRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewCustomFieldRepo(pool *pgxpool.Pool) *CustomFieldRepo {
+	return &CustomFieldRepo{pool: pool}
+}
+
+func (r *CustomFieldRepo) GetByProjectID(ctx context.Context, projectID uuid.UUID) (*CustomFieldSchema, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM projects WHERE id = $1 AND deleted_at IS NULL)`,
+		projectID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("get custom fields: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return loadCustomFields(ctx, r.pool, projectID)
+}
+
+// Replace swaps the project's custom fields for the given ones. Existing
+// task metadata is not revalidated; it is checked the next time it is
+// written.
+func (r *CustomFieldRepo) Replace(ctx context.Context, params ReplaceCustomFieldsParams) (*CustomFieldSchema, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var projectID uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT id FROM projects WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+		params.ProjectID,
+	).Scan(&projectID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("lock project: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM project_custom_fields WHERE project_id = $1`, params.ProjectID); err != nil {
+		return nil, fmt.Errorf("delete custom fields: %w", err)
+	}
+	for i, f := range params.Fields {
+		options := f.Options
+		if options == nil {
+			options = []string{}
+		}
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO project_custom_fields (project_id, key, type, required, options, position)
+			 VALUES ($1, $2, $3, $4, $5, $6)`,
+			params.ProjectID, f.Key, f.Type, f.Required, options, i); err != nil {
+			return nil, fmt.Errorf("insert custom field: %w", err)
+		}
+	}
+
+	schema, err := loadCustomFields(ctx, tx, params.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return schema, nil
+}
+
+func loadCustomFields(ctx context.Context, q DBTX, projectID uuid.UUID) (*CustomFieldSchema, error) {
+	rows, err := q.Query(ctx,
+		`SELECT key, type, required, options FROM project_custom_fields
+		 WHERE project_id = $1 ORDER BY position, key`,
+		projectID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list custom fields: %w", err)
+	}
+	defer rows.Close()
+
+	schema := &CustomFieldSchema{ProjectID: projectID}
+	for rows.Next() {
+		var f CustomField
+		if err := rows.Scan(&f.Key, &f.Type, &f.Required, &f.Options); err != nil {
+			return nil, fmt.Errorf("scan custom field: %w", err)
+		}
+		schema.Fields = append(schema.Fields, f)
+	}
+	return schema, rows.Err()
+}
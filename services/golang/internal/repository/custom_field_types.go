@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CustomFieldSchema is the set of typed keys a project's task metadata must
+// follow. An empty schema accepts any metadata.
+type CustomFieldSchema struct {
+	ProjectID uuid.UUID
+	Fields    []CustomField // in display order
+}
+
+type CustomField struct {
+	Key      string
+	Type     string // text, number, date, enum, user
+	Required bool
+	Options  []string // allowed values; enum fields only
+}
+
+type ReplaceCustomFieldsParams struct {
+	ProjectID uuid.UUID
+	Fields    []CustomField
+}
+
+// Validate checks task metadata against the schema and returns one
+// violation per offending key: a missing required field, a value of the
+// wrong type, or a key the schema does not declare. A null value counts as
+// missing. Dates are YYYY-MM-DD strings.
+func (s *CustomFieldSchema) Validate(metadata json.RawMessage) []FieldViolation {
+	if len(s.Fields) == 0 {
+		return nil
+	}
+	var values map[string]any
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &values); err != nil {
+			return []FieldViolation{{Field: "metadata", Description: "must be an object"}}
+		}
+	}
+
+	var violations []FieldViolation
+	for _, f := range s.Fields {
+		v, ok := values[f.Key]
+		if !ok || v == nil {
+			if f.Required {
+				violations = append(violations, FieldViolation{Field: "metadata." + f.Key, Description: "is required"})
+			}
+			continue
+		}
+		if msg := f.check(v); msg != "" {
+			violations = append(violations, FieldViolation{Field: "metadata." + f.Key, Description: msg})
+		}
+	}
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if !slices.ContainsFunc(s.Fields, func(f CustomField) bool { return f.Key == k }) {
+			violations = append(violations, FieldViolation{Field: "metadata." + k, Description: "is not a custom field of this project"})
+		}
+	}
+	return violations
+}
+
+// check returns why v is not a valid value for the field, or "".
+func (f *CustomField) check(v any) string {
+	if f.Type == "number" {
+		if _, ok := v.(float64); !ok {
+			return "must be a number"
+		}
+		return ""
+	}
+	str, ok := v.(string)
+	if !ok {
+		return "must be a string"
+	}
+	switch f.Type {
+	case "date":
+		if _, err := time.Parse(time.DateOnly, str); err != nil {
+			return "must be a date (YYYY-MM-DD)"
+		}
+	case "enum":
+		if !slices.Contains(f.Options, str) {
+			return fmt.Sprintf("must be one of %q", f.Options)
+		}
+	case "user":
+		if str == "" || len(str) > 255 {
+			return "must be a user ID of 1 to 255 characters"
+		}
+	}
+	return ""
+}
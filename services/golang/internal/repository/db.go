@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/identity"
+)
+
+// DBTX is satisfied by both *pgxpool.Pool and pgx.Tx, so helpers can run
+// either standalone or as part of a larger transaction.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// withActor runs fn in a transaction tagged with the calling user from ctx.
+// The task_history trigger reads the tag (app.actor_id) to attribute changes,
+// so every write to tasks should go through here.
+func withActor(ctx context.Context, pool *pgxpool.Pool, fn func(tx pgx.Tx) error) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT set_config('app.actor_id', $1, true)`, identity.UserID(ctx)); err != nil {
+		return fmt.Errorf("set actor: %w", err)
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
+
+// updateBuilder assembles the SET list of an UPDATE that honours an update
+// mask: fields outside the mask are left untouched, and an empty mask
+// updates every field.
+type updateBuilder struct {
+	mask []string
+	sets []string
+	args []any
+}
+
+func newUpdateBuilder(mask []string) *updateBuilder {
+	return &updateBuilder{mask: mask}
+}
+
+// includes reports whether field is covered by the mask.
+func (b *updateBuilder) includes(field string) bool {
+	return len(b.mask) == 0 || slices.Contains(b.mask, field)
+}
+
+// set adds "column = value" if field is covered by the mask.
+func (b *updateBuilder) set(field, column string, value any) {
+	if b.includes(field) {
+		b.sets = append(b.sets, column+" = "+b.arg(value))
+	}
+}
+
+// arg adds a query argument and returns its placeholder.
+func (b *updateBuilder) arg(value any) string {
+	b.args = append(b.args, value)
+	return fmt.Sprintf("$%d", len(b.args))
+}
+
+// clause returns the SET list, always bumping updated_at.
+func (b *updateBuilder) clause() string {
+	return strings.Join(append(b.sets, "updated_at = NOW()"), ", ")
+}
+
+// matchVersion returns a WHERE condition pinning the row version, or "" when
+// version is 0 and the write is unconditional.
+func (b *updateBuilder) matchVersion(version int32) string {
+	if version == 0 {
+		return ""
+	}
+	return " AND version = " + b.arg(version)
+}
+
+// staleOrNotFound explains why a write guarded by a row version matched
+// nothing: ErrStaleVersion if the live row exists, ErrNotFound otherwise.
+// table is always a constant.
+func staleOrNotFound(ctx context.Context, db DBTX, table string, id uuid.UUID) error {
+	var exists bool
+	err := db.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)`, id,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check %s version: %w", table, err)
+	}
+	if exists {
+		return ErrStaleVersion
+	}
+	return ErrNotFound
+}
+
+// lockParent share-locks the row a new row is being created under, so a
+// concurrent delete waits for the create and then cascades to the new row.
+// A missing parent is ErrNotFound and one in the trash ErrFailedPrecondition.
+// table is always a constant.
+func lockParent(ctx context.Context, db DBTX, table string, id uuid.UUID) error {
+	noun := strings.TrimSuffix(table, "s")
+	var deleted bool
+	err := db.QueryRow(ctx,
+		`SELECT deleted_at IS NOT NULL FROM `+table+` WHERE id = $1 FOR SHARE`, id,
+	).Scan(&deleted)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("%s: %w", noun, ErrNotFound)
+		}
+		return fmt.Errorf("lock %s: %w", noun, err)
+	}
+	if deleted {
+		return fmt.Errorf("%w: %s is deleted", ErrFailedPrecondition, noun)
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DependencyRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewDependencyRepo(pool *pgxpool.Pool) *DependencyRepo {
+	return &DependencyRepo{pool: pool}
+}
+
+// Create adds an edge blocker -> blocked. Inserts are serialized per
+// workspace so two concurrent inserts cannot close a cycle together.
+func (r *DependencyRepo) Create(ctx context.Context, blockerID, blockedID uuid.UUID) (*TaskDependency, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var blockerWorkspace, blockedWorkspace uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT workspace_id FROM tasks WHERE id = $1 AND deleted_at IS NULL`, blockerID,
+	).Scan(&blockerWorkspace)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("blocker task: %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("get blocker task: %w", err)
+	}
+	err = tx.QueryRow(ctx,
+		`SELECT workspace_id FROM tasks WHERE id = $1 AND deleted_at IS NULL`, blockedID,
+	).Scan(&blockedWorkspace)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, fmt.Errorf("blocked task: %w", ErrNotFound)
+		}
+		return nil, fmt.Errorf("get blocked task: %w", err)
+	}
+	if blockerWorkspace != blockedWorkspace {
+		return nil, fmt.Errorf("%w: tasks belong to different workspaces", ErrInvalidInput)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`SELECT pg_advisory_xact_lock(hashtext('task_dependencies:' || $1::text))`,
+		blockerWorkspace); err != nil {
+		return nil, fmt.Errorf("lock workspace dependencies: %w", err)
+	}
+
+	// The new edge closes a cycle if the blocker is already reachable
+	// from the blocked task.
+	var cycle bool
+	err = tx.QueryRow(ctx,
+		`WITH RECURSIVE downstream(task_id) AS (
+		     SELECT blocked_task_id FROM task_dependencies WHERE blocker_task_id = $1
+		     UNION
+		     SELECT d.blocked_task_id FROM task_dependencies d
+		     JOIN downstream ds ON d.blocker_task_id = ds.task_id
+		 )
+		 SELECT EXISTS (SELECT 1 FROM downstream WHERE task_id = $2)`,
+		blockedID, blockerID,
+	).Scan(&cycle)
+	if err != nil {
+		return nil, fmt.Errorf("check dependency cycle: %w", err)
+	}
+	if cycle {
+		return nil, fmt.Errorf("%w: dependency would create a cycle", ErrFailedPrecondition)
+	}
+
+	var d TaskDependency
+	err = tx.QueryRow(ctx,
+		`INSERT INTO task_dependencies (blocker_task_id, blocked_task_id, workspace_id, created_at)
+		 VALUES ($1, $2, $3, NOW())
+		 RETURNING blocker_task_id, blocked_task_id, workspace_id, created_at`,
+		blockerID, blockedID, blockerWorkspace,
+	).Scan(&d.BlockerTaskID, &d.BlockedTaskID, &d.WorkspaceID, &d.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("create dependency: %w", ErrConflict)
+		}
+		return nil, fmt.Errorf("create dependency: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &d, nil
+}
+
+func (r *DependencyRepo) Delete(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM task_dependencies WHERE blocker_task_id = $1 AND blocked_task_id = $2`,
+		blockerID, blockedID)
+	if err != nil {
+		return fmt.Errorf("delete dependency: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListByTask returns both directions of a task's dependencies, skipping
+// edges whose other end has been soft-deleted.
+func (r *DependencyRepo) ListByTask(ctx context.Context, taskID uuid.UUID) (*TaskDependencies, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT d.blocker_task_id, d.blocked_task_id, d.workspace_id, d.created_at
+		 FROM task_dependencies d
+		 JOIN tasks b ON b.id = d.blocker_task_id AND b.deleted_at IS NULL
+		 JOIN tasks t ON t.id = d.blocked_task_id AND t.deleted_at IS NULL
+		 WHERE d.blocker_task_id = $1 OR d.blocked_task_id = $1
+		 ORDER BY d.created_at, d.blocker_task_id, d.blocked_task_id`,
+		taskID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list dependencies: %w", err)
+	}
+	defer rows.Close()
+
+	deps := &TaskDependencies{}
+	for rows.Next() {
+		var d TaskDependency
+		if err := rows.Scan(&d.BlockerTaskID, &d.BlockedTaskID, &d.WorkspaceID, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan dependency: %w", err)
+		}
+		if d.BlockedTaskID == taskID {
+			deps.BlockedBy = append(deps.BlockedBy, d)
+		} else {
+			deps.Blocking = append(deps.Blocking, d)
+		}
+	}
+	return deps, rows.Err()
+}
+
+// CountOpenBlockers counts live blockers of a task whose status is not a
+// done status in their own project's workflow.
+func (r *DependencyRepo) CountOpenBlockers(ctx context.Context, taskID uuid.UUID) (int32, error) {
+	var count int32
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*)::int
+		 FROM task_dependencies d
+		 JOIN tasks b ON b.id = d.blocker_task_id AND b.deleted_at IS NULL
+		 JOIN project_statuses ps ON ps.project_id = b.project_id AND ps.name = b.status
+		 WHERE d.blocked_task_id = $1 AND NOT ps.is_done`,
+		taskID,
+	).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("count open blockers: %w", err)
+	}
+	return count, nil
+}
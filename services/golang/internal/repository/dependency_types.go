@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskDependency records that BlockerTaskID must be done before
+// BlockedTaskID can be completed.
+type TaskDependency struct {
+	BlockerTaskID uuid.UUID
+	BlockedTaskID uuid.UUID
+	WorkspaceID   uuid.UUID
+	CreatedAt     time.Time
+}
+
+type TaskDependencies struct {
+	BlockedBy []TaskDependency // tasks blocking this task
+	Blocking  []TaskDependency // tasks this task blocks
+}
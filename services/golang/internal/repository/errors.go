@@ -1,10 +1,39 @@
 package repository
 
-import "errors"
+import (
+	"errors"
+	"strings"
+)
 
 var (
-	ErrNotFound      = errors.New("not found")
-	ErrAlreadyExists = errors.New("already exists")
-	ErrInvalidInput  = errors.New("invalid input")
-	ErrConflict      = errors.New("conflict")
+	ErrNotFound           = errors.New("not found")
+	ErrAlreadyExists      = errors.New("already exists")
+	ErrInvalidInput       = errors.New("invalid input")
+	ErrConflict           = errors.New("conflict")
+	ErrFailedPrecondition = errors.New("failed precondition")
+	ErrStaleVersion       = errors.New("stale version")
 )
+
+// FieldViolation describes one invalid field of a request.
+type FieldViolation struct {
+	Field       string // path of the field, e.g. metadata.customer
+	Description string
+}
+
+// ValidationError reports every invalid field of a request at once. It
+// wraps ErrInvalidInput.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		parts[i] = v.Field + ": " + v.Description
+	}
+	return ErrInvalidInput.Error() + ": " + strings.Join(parts, "; ")
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidInput
+}
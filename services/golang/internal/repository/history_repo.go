@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// HistoryRepo reads the task change log. Entries are written by the
+// task_history trigger, never by application code.
+type HistoryRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewHistoryRepo(pool *pgxpool.Pool) *HistoryRepo {
+	return &HistoryRepo{pool: pool}
+}
+
+// ListByTask returns a task's history, newest first. History stays readable
+// after the task is soft-deleted. The page token is the ID of the last entry
+// on the previous page.
+func (r *HistoryRepo) ListByTask(ctx context.Context, params ListTaskHistoryParams) (*TaskHistoryList, error) {
+	pageSize := params.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var exists bool
+	err := r.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1)`, params.TaskID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("check task: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	var totalCount int32
+	err = r.pool.QueryRow(ctx,
+		`SELECT COUNT(*)::int FROM task_history WHERE task_id = $1`,
+		params.TaskID,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("count task history: %w", err)
+	}
+
+	var rows pgx.Rows
+	if params.PageToken != "" {
+		cursorID, parseErr := uuid.Parse(params.PageToken)
+		if parseErr != nil {
+			return nil, fmt.Errorf("invalid page token: %w", parseErr)
+		}
+		rows, err = r.pool.Query(ctx,
+			`SELECT id, task_id, operation, COALESCE(actor_id, ''), changes, created_at
+			 FROM task_history
+			 WHERE task_id = $1
+			   AND (created_at, id) < (SELECT created_at, id FROM task_history WHERE id = $2)
+			 ORDER BY created_at DESC, id DESC LIMIT $3`,
+			params.TaskID, cursorID, pageSize+1,
+		)
+	} else {
+		rows, err = r.pool.Query(ctx,
+			`SELECT id, task_id, operation, COALESCE(actor_id, ''), changes, created_at
+			 FROM task_history WHERE task_id = $1
+			 ORDER BY created_at DESC, id DESC LIMIT $2`,
+			params.TaskID, pageSize+1,
+		)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list task history: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []TaskHistoryEntry
+	for rows.Next() {
+		var e TaskHistoryEntry
+		if err := rows.Scan(&e.ID, &e.TaskID, &e.Operation, &e.ActorID, &e.Changes, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan task history: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	var nextPageToken string
+	if len(entries) > int(pageSize) {
+		entries = entries[:pageSize]
+		nextPageToken = entries[pageSize-1].ID.String()
+	}
+
+	return &TaskHistoryList{
+		Entries:       entries,
+		NextPageToken: nextPageToken,
+		TotalCount:    totalCount,
+	}, nil
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type TaskHistoryEntry struct {
+	ID        uuid.UUID
+	TaskID    uuid.UUID
+	Operation string          // create, update, delete, restore
+	ActorID   string          // empty when the change was not made on behalf of a user
+	Changes   json.RawMessage // {"field": {"old": ..., "new": ...}}
+	CreatedAt time.Time
+}
+
+type ListTaskHistoryParams struct {
+	TaskID    uuid.UUID
+	PageSize  int32
+	PageToken string
+}
+
+type TaskHistoryList struct {
+	Entries       []TaskHistoryEntry
+	NextPageToken string
+	TotalCount    int32
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type LabelRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewLabelRepo(pool *pgxpool.Pool) *LabelRepo {
+	return &LabelRepo{pool: pool}
+}
+
+// Create adds a label to a workspace, which must not be deleted.
+func (r *LabelRepo) Create(ctx context.Context, params CreateLabelParams) (*Label, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockParent(ctx, tx, "workspaces", params.WorkspaceID); err != nil {
+		return nil, err
+	}
+	var l Label
+	err = tx.QueryRow(ctx,
+		`INSERT INTO labels (id, workspace_id, name, color, created_at, updated_at)
+		 VALUES (gen_random_uuid(), $1, $2, $3, NOW(), NOW())
+		 RETURNING id, workspace_id, name, color, created_at, updated_at`,
+		params.WorkspaceID, params.Name, params.Color,
+	).Scan(&l.ID, &l.WorkspaceID, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("create label: %w", ErrConflict)
+		}
+		return nil, fmt.Errorf("create label: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &l, nil
+}
+
+func (r *LabelRepo) GetByID(ctx context.Context, id uuid.UUID) (*Label, error) {
+	var l Label
+	err := r.pool.QueryRow(ctx,
+		`SELECT id, workspace_id, name, color, created_at, updated_at
+		 FROM labels WHERE id = $1`,
+		id,
+	).Scan(&l.ID, &l.WorkspaceID, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get label: %w", err)
+	}
+	return &l, nil
+}
+
+// labelSortKeys are the orders List supports. Names sort ignoring case, as
+// they are unique.
+var labelSortKeys = map[string]sortKey{
+	"":           {expr: "lower(%[1]s.name)", typ: "text"},
+	"name":       {expr: "lower(%[1]s.name)", typ: "text"},
+	"created_at": {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"updated_at": {expr: "%[1]s.updated_at", typ: "timestamptz", desc: true},
+}
+
+// List returns a workspace's labels, alphabetically unless params.Sort says
+// otherwise.
+func (r *LabelRepo) List(ctx context.Context, params ListLabelsParams) (*LabelList, error) {
+	pageSize := params.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var totalCount int32
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*)::int FROM labels WHERE workspace_id = $1`,
+		params.WorkspaceID,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("count labels: %w", err)
+	}
+
+	orderBy, sortValue, cursorCond, err := keyset(labelSortKeys, params.Sort, "l", 2)
+	if err != nil {
+		return nil, err
+	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.pool.Query(ctx,
+		`SELECT l.id, l.workspace_id, l.name, l.color, l.created_at, l.updated_at, `+sortValue+`
+		 FROM labels l WHERE l.workspace_id = $1
+		   AND ($3::uuid IS NULL OR `+cursorCond+`)
+		 ORDER BY `+orderBy+` LIMIT $4`,
+		params.WorkspaceID, cursorValue, cursorID, pageSize+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list labels: %w", err)
+	}
+	defer rows.Close()
+
+	var labels []Label
+	var value string
+	for rows.Next() {
+		var l Label
+		if err := rows.Scan(&l.ID, &l.WorkspaceID, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt, &value); err != nil {
+			return nil, fmt.Errorf("scan label: %w", err)
+		}
+		labels = append(labels, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list labels: %w", err)
+	}
+
+	var nextPageToken string
+	if len(labels) > int(pageSize) {
+		nextPageToken = encodeCursor(value, labels[pageSize].ID.String())
+		labels = labels[:pageSize]
+	}
+
+	return &LabelList{
+		Labels:        labels,
+		NextPageToken: nextPageToken,
+		TotalCount:    totalCount,
+	}, nil
+}
+
+// Update writes the fields named in params.UpdateMask, or all of them when
+// the mask is empty.
+func (r *LabelRepo) Update(ctx context.Context, params UpdateLabelParams) (*Label, error) {
+	b := newUpdateBuilder(params.UpdateMask)
+	b.set("name", "name", params.Name)
+	b.set("color", "color", params.Color)
+
+	var l Label
+	err := r.pool.QueryRow(ctx,
+		fmt.Sprintf(`UPDATE labels SET %s
+		 WHERE id = %s
+		 RETURNING id, workspace_id, name, color, created_at, updated_at`,
+			b.clause(), b.arg(params.ID)),
+		b.args...,
+	).Scan(&l.ID, &l.WorkspaceID, &l.Name, &l.Color, &l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("update label: %w", ErrConflict)
+		}
+		return nil, fmt.Errorf("update label: %w", err)
+	}
+	return &l, nil
+}
+
+// Delete removes a label; its task assignments are removed by cascade.
+func (r *LabelRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM labels WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("delete label: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// AddToTask assigns a label to a task. Assigning it twice is a no-op.
+func (r *LabelRepo) AddToTask(ctx context.Context, taskID, labelID uuid.UUID) error {
+	_, err := r.pool.Exec(ctx,
+		`INSERT INTO task_labels (task_id, label_id, created_at)
+		 VALUES ($1, $2, NOW())
+		 ON CONFLICT DO NOTHING`,
+		taskID, labelID)
+	if err != nil {
+		return fmt.Errorf("add task label: %w", err)
+	}
+	return nil
+}
+
+func (r *LabelRepo) RemoveFromTask(ctx context.Context, taskID, labelID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM task_labels WHERE task_id = $1 AND label_id = $2`,
+		taskID, labelID)
+	if err != nil {
+		return fmt.Errorf("remove task label: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
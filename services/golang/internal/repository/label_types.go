@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Label struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	Name        string
+	Color       string // #rrggbb
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type CreateLabelParams struct {
+	WorkspaceID uuid.UUID
+	Name        string
+	Color       string
+}
+
+type UpdateLabelParams struct {
+	ID         uuid.UUID
+	Name       string
+	Color      string
+	UpdateMask []string // name, color; empty writes all
+}
+
+type ListLabelsParams struct {
+	WorkspaceID uuid.UUID
+	Sort        Sort // name, created_at or updated_at
+	PageSize    int32
+	PageToken   string
+}
+
+type LabelList struct {
+	Labels        []Label
+	NextPageToken string
+	TotalCount    int32
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -17,102 +18,114 @@ func NewNotificationRepo(pool *pgxpool.Pool) *NotificationRepo {
 	return &NotificationRepo{pool: pool}
 }
 
+const notificationColumns = `id, workspace_id, COALESCE(recipient_id, ''), event_type, payload, status,
+	retry_count, max_retries, next_retry_at, COALESCE(last_error, ''), created_at, processed_at`
+
+func scanNotification(row pgx.Row, n *Notification) error {
+	return row.Scan(&n.ID, &n.WorkspaceID, &n.RecipientID, &n.EventType, &n.Payload, &n.Status,
+		&n.RetryCount, &n.MaxRetries, &n.NextRetryAt, &n.LastError, &n.CreatedAt, &n.ProcessedAt)
+}
+
 func (r *NotificationRepo) Create(ctx context.Context, params CreateNotificationParams) (*Notification, error) {
 	var n Notification
-	err := r.pool.QueryRow(ctx,
+	row := r.pool.QueryRow(ctx,
 		`INSERT INTO notification_queue (workspace_id, event_type, payload, status, created_at)
 		 VALUES ($1, $2, $3, 'pending', NOW())
-		 RETURNING id, workspace_id, event_type, payload, status, retry_count, max_retries,
-		           next_retry_at, COALESCE(last_error, ''), created_at, processed_at`,
+		 RETURNING `+notificationColumns,
 		params.WorkspaceID, params.EventType, params.Payload,
-	).Scan(&n.ID, &n.WorkspaceID, &n.EventType, &n.Payload, &n.Status, &n.RetryCount,
-		&n.MaxRetries, &n.NextRetryAt, &n.LastError, &n.CreatedAt, &n.ProcessedAt)
-	if err != nil {
+	)
+	if err := scanNotification(row, &n); err != nil {
 		return nil, fmt.Errorf("create notification: %w", err)
 	}
 	return &n, nil
 }
 
+// notificationSortKeys are the orders List supports.
+var notificationSortKeys = map[string]sortKey{
+	"":           {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"created_at": {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+}
+
 func (r *NotificationRepo) List(ctx context.Context, params ListNotificationsParams) (*NotificationList, error) {
 	pageSize := params.PageSize
 	if pageSize <= 0 || pageSize > 100 {
 		pageSize = 20
 	}
 
-	// Parse offset from page token (offset-based pagination for notifications)
-	var offset int
+	var cursorValue string
+	var cursorID *int64
 	if params.PageToken != "" {
-		var parseErr error
-		offset, parseErr = strconv.Atoi(params.PageToken)
-		if parseErr != nil {
-			return nil, fmt.Errorf("invalid page token: %w", parseErr)
-		}
-	}
-
-	// Count query
-	var totalCount int32
-	if params.Status != "" {
-		err := r.pool.QueryRow(ctx,
-			`SELECT COUNT(*)::int FROM notification_queue
-			 WHERE workspace_id = $1 AND status = $2`,
-			params.WorkspaceID, params.Status,
-		).Scan(&totalCount)
+		value, idStr, err := splitCursor(params.PageToken)
 		if err != nil {
-			return nil, fmt.Errorf("count notifications: %w", err)
+			return nil, err
 		}
-	} else {
-		err := r.pool.QueryRow(ctx,
-			`SELECT COUNT(*)::int FROM notification_queue WHERE workspace_id = $1`,
-			params.WorkspaceID,
-		).Scan(&totalCount)
+		id, err := strconv.ParseInt(idStr, 10, 64)
 		if err != nil {
-			return nil, fmt.Errorf("count notifications: %w", err)
+			return nil, fmt.Errorf("%w: invalid page token", ErrInvalidInput)
 		}
+		cursorValue, cursorID = value, &id
 	}
 
-	// List query
-	var rows pgx.Rows
-	var err error
+	conditions := []string{"n.workspace_id = $1"}
+	args := []any{params.WorkspaceID}
 	if params.Status != "" {
-		rows, err = r.pool.Query(ctx,
-			`SELECT id, workspace_id, event_type, payload, status, retry_count, max_retries,
-			        next_retry_at, COALESCE(last_error, ''), created_at, processed_at
-			 FROM notification_queue
-			 WHERE workspace_id = $1 AND status = $2
-			 ORDER BY created_at DESC
-			 LIMIT $3 OFFSET $4`,
-			params.WorkspaceID, params.Status, pageSize, offset,
-		)
-	} else {
-		rows, err = r.pool.Query(ctx,
-			`SELECT id, workspace_id, event_type, payload, status, retry_count, max_retries,
-			        next_retry_at, COALESCE(last_error, ''), created_at, processed_at
-			 FROM notification_queue
-			 WHERE workspace_id = $1
-			 ORDER BY created_at DESC
-			 LIMIT $2 OFFSET $3`,
-			params.WorkspaceID, pageSize, offset,
-		)
+		args = append(args, params.Status)
+		conditions = append(conditions, fmt.Sprintf("n.status = $%d", len(args)))
+	}
+	if params.RecipientID != "" {
+		args = append(args, params.RecipientID)
+		conditions = append(conditions, fmt.Sprintf("n.recipient_id = $%d", len(args)))
+	}
+	where := strings.Join(conditions, " AND ")
+
+	var totalCount int32
+	err := r.pool.QueryRow(ctx,
+		`SELECT COUNT(*)::int FROM notification_queue n WHERE `+where, args...,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("count notifications: %w", err)
 	}
+
+	orderBy, sortValue, cursorCond, err := keyset(notificationSortKeys, params.Sort, "n", len(args)+1)
+	if err != nil {
+		return nil, err
+	}
+	if cursorID != nil {
+		args = append(args, cursorValue, *cursorID)
+		conditions = append(conditions, cursorCond)
+		where = strings.Join(conditions, " AND ")
+	}
+	args = append(args, pageSize+1)
+	rows, err := r.pool.Query(ctx,
+		fmt.Sprintf(`SELECT %s, %s
+		 FROM notification_queue n
+		 WHERE %s
+		 ORDER BY %s
+		 LIMIT $%d`, notificationColumns, sortValue, where, orderBy, len(args)),
+		args...,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("list notifications: %w", err)
 	}
 	defer rows.Close()
 
 	var notifications []Notification
+	var value string
 	for rows.Next() {
 		var n Notification
-		if err := rows.Scan(&n.ID, &n.WorkspaceID, &n.EventType, &n.Payload, &n.Status,
-			&n.RetryCount, &n.MaxRetries, &n.NextRetryAt, &n.LastError, &n.CreatedAt, &n.ProcessedAt); err != nil {
+		if err := scanNotification(cursorRow{rows, &value}, &n); err != nil {
 			return nil, fmt.Errorf("scan notification: %w", err)
 		}
 		notifications = append(notifications, n)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list notifications: %w", err)
+	}
 
 	var nextPageToken string
-	nextOffset := offset + int(pageSize)
-	if nextOffset < int(totalCount) {
-		nextPageToken = strconv.Itoa(nextOffset)
+	if len(notifications) > int(pageSize) {
+		nextPageToken = encodeCursor(value, strconv.FormatInt(notifications[pageSize].ID, 10))
+		notifications = notifications[:pageSize]
 	}
 
 	return &NotificationList{
@@ -124,15 +137,13 @@ func (r *NotificationRepo) List(ctx context.Context, params ListNotificationsPar
 
 func (r *NotificationRepo) MarkProcessed(ctx context.Context, id int64) (*Notification, error) {
 	var n Notification
-	err := r.pool.QueryRow(ctx,
+	err := scanNotification(r.pool.QueryRow(ctx,
 		`UPDATE notification_queue
 		 SET status = 'processed', processed_at = NOW()
 		 WHERE id = $1
-		 RETURNING id, workspace_id, event_type, payload, status, retry_count, max_retries,
-		           next_retry_at, COALESCE(last_error, ''), created_at, processed_at`,
+		 RETURNING `+notificationColumns,
 		id,
-	).Scan(&n.ID, &n.WorkspaceID, &n.EventType, &n.Payload, &n.Status, &n.RetryCount,
-		&n.MaxRetries, &n.NextRetryAt, &n.LastError, &n.CreatedAt, &n.ProcessedAt)
+	), &n)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrNotFound
@@ -144,8 +155,7 @@ func (r *NotificationRepo) MarkProcessed(ctx context.Context, id int64) (*Notifi
 
 func (r *NotificationRepo) FetchPending(ctx context.Context, limit int) ([]Notification, error) {
 	rows, err := r.pool.Query(ctx,
-		`SELECT id, workspace_id, event_type, payload, status, retry_count, max_retries,
-		        next_retry_at, COALESCE(last_error, ''), created_at, processed_at
+		`SELECT `+notificationColumns+`
 		 FROM notification_queue
 		 WHERE status IN ('pending', 'failed')
 		   AND (next_retry_at IS NULL OR next_retry_at <= NOW())
@@ -162,12 +172,14 @@ func (r *NotificationRepo) FetchPending(ctx context.Context, limit int) ([]Notif
 	var notifications []Notification
 	for rows.Next() {
 		var n Notification
-		if err := rows.Scan(&n.ID, &n.WorkspaceID, &n.EventType, &n.Payload, &n.Status,
-			&n.RetryCount, &n.MaxRetries, &n.NextRetryAt, &n.LastError, &n.CreatedAt, &n.ProcessedAt); err != nil {
+		if err := scanNotification(rows, &n); err != nil {
 			return nil, fmt.Errorf("scan notification: %w", err)
 		}
 		notifications = append(notifications, n)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("fetch pending notifications: %w", err)
+	}
 
 	return notifications, nil
 }
@@ -10,6 +10,7 @@ import (
 type Notification struct {
 	ID          int64
 	WorkspaceID uuid.UUID
+	RecipientID string // empty for workspace-wide events
 	EventType   string
 	Payload     json.RawMessage
 	Status      string // pending, processing, processed, failed
@@ -30,6 +31,8 @@ type CreateNotificationParams struct {
 type ListNotificationsParams struct {
 	WorkspaceID uuid.UUID
 	Status      string // optional filter
+	RecipientID string // optional filter
+	Sort        Sort   // created_at
 	PageSize    int32
 	PageToken   string
 }
@@ -2,7 +2,9 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -17,27 +19,40 @@ func NewProjectRepo(pool *pgxpool.Pool) *ProjectRepo {
 	return &ProjectRepo{pool: pool}
 }
 
+// Create adds a project to a workspace, which must not be deleted.
 func (r *ProjectRepo) Create(ctx context.Context, params CreateProjectParams) (*Project, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockParent(ctx, tx, "workspaces", params.WorkspaceID); err != nil {
+		return nil, err
+	}
 	var p Project
-	err := r.pool.QueryRow(ctx,
+	err = tx.QueryRow(ctx,
 		`INSERT INTO projects (id, workspace_id, name, description, status, created_at, updated_at)
 		 VALUES (gen_random_uuid(), $1, $2, $3, 'active', NOW(), NOW())
-		 RETURNING id, workspace_id, name, description, status, created_at, updated_at, deleted_at`,
+		 RETURNING id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at`,
 		params.WorkspaceID, params.Name, params.Description,
-	).Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
+	).Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.Version, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
 	if err != nil {
 		return nil, fmt.Errorf("create project: %w", err)
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
 	return &p, nil
 }
 
 func (r *ProjectRepo) GetByID(ctx context.Context, id uuid.UUID) (*Project, error) {
 	var p Project
 	err := r.pool.QueryRow(ctx,
-		`SELECT id, workspace_id, name, description, status, created_at, updated_at, deleted_at
+		`SELECT id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at
 		 FROM projects WHERE id = $1 AND deleted_at IS NULL`,
 		id,
-	).Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
+	).Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.Version, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrNotFound
@@ -47,6 +62,15 @@ func (r *ProjectRepo) GetByID(ctx context.Context, id uuid.UUID) (*Project, erro
 	return &p, nil
 }
 
+// projectSortKeys are the orders List supports.
+var projectSortKeys = map[string]sortKey{
+	"":           {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"created_at": {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"updated_at": {expr: "%[1]s.updated_at", typ: "timestamptz", desc: true},
+	"name":       {expr: "%[1]s.name", typ: "text"},
+	"status":     {expr: "%[1]s.status", typ: "text"},
+}
+
 func (r *ProjectRepo) List(ctx context.Context, params ListProjectsParams) (*ProjectList, error) {
 	pageSize := params.PageSize
 	if pageSize <= 0 || pageSize > 100 {
@@ -62,43 +86,42 @@ func (r *ProjectRepo) List(ctx context.Context, params ListProjectsParams) (*Pro
 		return nil, fmt.Errorf("count projects: %w", err)
 	}
 
-	var rows pgx.Rows
-	if params.PageToken != "" {
-		cursorID, parseErr := uuid.Parse(params.PageToken)
-		if parseErr != nil {
-			return nil, fmt.Errorf("invalid page token: %w", parseErr)
-		}
-		rows, err = r.pool.Query(ctx,
-			`SELECT id, workspace_id, name, description, status, created_at, updated_at, deleted_at
-			 FROM projects WHERE workspace_id = $1 AND deleted_at IS NULL AND id < $2
-			 ORDER BY created_at DESC, id DESC LIMIT $3`,
-			params.WorkspaceID, cursorID, pageSize+1,
-		)
-	} else {
-		rows, err = r.pool.Query(ctx,
-			`SELECT id, workspace_id, name, description, status, created_at, updated_at, deleted_at
-			 FROM projects WHERE workspace_id = $1 AND deleted_at IS NULL
-			 ORDER BY created_at DESC, id DESC LIMIT $2`,
-			params.WorkspaceID, pageSize+1,
-		)
+	orderBy, sortValue, cursorCond, err := keyset(projectSortKeys, params.Sort, "p", 2)
+	if err != nil {
+		return nil, err
+	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
 	}
+	rows, err := r.pool.Query(ctx,
+		`SELECT p.id, p.workspace_id, p.name, p.description, p.status, p.version, p.created_at, p.updated_at, p.deleted_at, `+sortValue+`
+		 FROM projects p WHERE p.workspace_id = $1 AND p.deleted_at IS NULL
+		   AND ($3::uuid IS NULL OR `+cursorCond+`)
+		 ORDER BY `+orderBy+` LIMIT $4`,
+		params.WorkspaceID, cursorValue, cursorID, pageSize+1,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("list projects: %w", err)
 	}
 	defer rows.Close()
 
 	var projects []Project
+	var value string
 	for rows.Next() {
 		var p Project
-		if err := rows.Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt); err != nil {
+		if err := rows.Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.Version, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt, &value); err != nil {
 			return nil, fmt.Errorf("scan project: %w", err)
 		}
 		projects = append(projects, p)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list projects: %w", err)
+	}
 
 	var nextPageToken string
 	if len(projects) > int(pageSize) {
-		nextPageToken = projects[pageSize].ID.String()
+		nextPageToken = encodeCursor(value, projects[pageSize].ID.String())
 		projects = projects[:pageSize]
 	}
 
@@ -109,16 +132,27 @@ func (r *ProjectRepo) List(ctx context.Context, params ListProjectsParams) (*Pro
 	}, nil
 }
 
+// Update writes the fields named in params.UpdateMask, or all of them when
+// the mask is empty.
 func (r *ProjectRepo) Update(ctx context.Context, params UpdateProjectParams) (*Project, error) {
+	b := newUpdateBuilder(params.UpdateMask)
+	b.set("name", "name", params.Name)
+	b.set("description", "description", params.Description)
+	b.set("status", "status", params.Status)
+
 	var p Project
 	err := r.pool.QueryRow(ctx,
-		`UPDATE projects SET name = $1, description = $2, status = $3, updated_at = NOW()
-		 WHERE id = $4 AND deleted_at IS NULL
-		 RETURNING id, workspace_id, name, description, status, created_at, updated_at, deleted_at`,
-		params.Name, params.Description, params.Status, params.ID,
-	).Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
+		fmt.Sprintf(`UPDATE projects SET %s
+		 WHERE id = %s AND deleted_at IS NULL%s
+		 RETURNING id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at`,
+			b.clause(), b.arg(params.ID), b.matchVersion(params.Version)),
+		b.args...,
+	).Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.Version, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
+			if params.Version != 0 {
+				return nil, staleOrNotFound(ctx, r.pool, "projects", params.ID)
+			}
 			return nil, ErrNotFound
 		}
 		return nil, fmt.Errorf("update project: %w", err)
@@ -126,15 +160,136 @@ func (r *ProjectRepo) Update(ctx context.Context, params UpdateProjectParams) (*
 	return &p, nil
 }
 
-func (r *ProjectRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	tag, err := r.pool.Exec(ctx,
-		`UPDATE projects SET deleted_at = NOW(), updated_at = NOW()
-		 WHERE id = $1 AND deleted_at IS NULL`, id)
+// Delete soft-deletes a project and all of its live tasks in one
+// transaction, stamping them with the same deleted_at so Restore can bring
+// them back together. A non-zero version must match the current one.
+func (r *ProjectRepo) Delete(ctx context.Context, id uuid.UUID, version int32) error {
+	var deleted int64
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`UPDATE projects SET deleted_at = NOW(), updated_at = NOW()
+			 WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)`, id, version)
+		if err != nil {
+			return err
+		}
+		if deleted = tag.RowsAffected(); deleted == 0 {
+			return nil
+		}
+		_, err = tx.Exec(ctx,
+			`UPDATE tasks SET deleted_at = NOW(), updated_at = NOW()
+			 WHERE project_id = $1 AND deleted_at IS NULL`, id)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("delete project: %w", err)
 	}
-	if tag.RowsAffected() == 0 {
+	if deleted == 0 {
+		if version != 0 {
+			return staleOrNotFound(ctx, r.pool, "projects", id)
+		}
 		return ErrNotFound
 	}
 	return nil
 }
+
+// ListDeleted lists a workspace's soft-deleted projects, most recently
+// deleted first.
+func (r *ProjectRepo) ListDeleted(ctx context.Context, params ListProjectsParams) (*ProjectList, error) {
+	pageSize := params.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalCount int32
+	err = r.pool.QueryRow(ctx,
+		`SELECT COUNT(*)::int FROM projects WHERE workspace_id = $1 AND deleted_at IS NOT NULL`,
+		params.WorkspaceID,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("count deleted projects: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at
+		 FROM projects WHERE workspace_id = $1 AND deleted_at IS NOT NULL
+		   AND ($3::uuid IS NULL OR (deleted_at, id) <= ($2::text::timestamptz, $3))
+		 ORDER BY deleted_at DESC, id DESC LIMIT $4`,
+		params.WorkspaceID, cursorValue, cursorID, pageSize+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list deleted projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []Project
+	for rows.Next() {
+		var p Project
+		if err := rows.Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.Version, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt); err != nil {
+			return nil, fmt.Errorf("scan project: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list deleted projects: %w", err)
+	}
+
+	var nextPageToken string
+	if len(projects) > int(pageSize) {
+		nextPageToken = trashCursor(projects[pageSize].DeletedAt, projects[pageSize].ID)
+		projects = projects[:pageSize]
+	}
+
+	return &ProjectList{
+		Projects:      projects,
+		NextPageToken: nextPageToken,
+		TotalCount:    totalCount,
+	}, nil
+}
+
+// Restore undeletes a soft-deleted project together with the tasks deleted
+// along with it (those with the same deleted_at). Its workspace must be
+// live; a project that is live or already purged is reported as not found.
+func (r *ProjectRepo) Restore(ctx context.Context, id uuid.UUID) (*Project, error) {
+	var p Project
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		var workspaceDeleted bool
+		var deletedAt time.Time
+		err := tx.QueryRow(ctx,
+			`SELECT w.deleted_at IS NOT NULL, p.deleted_at
+			 FROM projects p JOIN workspaces w ON w.id = p.workspace_id
+			 WHERE p.id = $1 AND p.deleted_at IS NOT NULL
+			 FOR UPDATE OF p FOR SHARE OF w`, id,
+		).Scan(&workspaceDeleted, &deletedAt)
+		if err != nil {
+			return err
+		}
+		if workspaceDeleted {
+			return fmt.Errorf("%w: the project's workspace is deleted; restore it first", ErrFailedPrecondition)
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE tasks SET deleted_at = NULL, updated_at = NOW()
+			 WHERE project_id = $1 AND deleted_at = $2`, id, deletedAt); err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx,
+			`UPDATE projects SET deleted_at = NULL, updated_at = NOW()
+			 WHERE id = $1
+			 RETURNING id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at`,
+			id,
+		).Scan(&p.ID, &p.WorkspaceID, &p.Name, &p.Description, &p.Status, &p.Version, &p.CreatedAt, &p.UpdatedAt, &p.DeletedAt)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		if errors.Is(err, ErrFailedPrecondition) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("restore project: %w", err)
+	}
+	return &p, nil
+}
@@ -12,6 +12,7 @@ type Project struct {
 	Name        string
 	Description string
 	Status      string // active, archived
+	Version     int32  // bumped on every write
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 	DeletedAt   *time.Time
@@ -28,10 +29,13 @@ type UpdateProjectParams struct {
 	Name        string
 	Description string
 	Status      string
+	UpdateMask  []string // fields to write; empty writes all
+	Version     int32    // expected current version; 0 skips the check
 }
 
 type ListProjectsParams struct {
 	WorkspaceID uuid.UUID
+	Sort        Sort // created_at, updated_at, name or status
 	PageSize    int32
 	PageToken   string
 }
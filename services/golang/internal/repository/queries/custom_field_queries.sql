@@ -0,0 +1,12 @@
+-- name: ListProjectCustomFields :many
+SELECT key, type, required, options
+FROM project_custom_fields
+WHERE project_id = @project_id
+ORDER BY position, key;
+
+-- name: DeleteProjectCustomFields :exec
+DELETE FROM project_custom_fields WHERE project_id = @project_id;
+
+-- name: CreateProjectCustomField :exec
+INSERT INTO project_custom_fields (project_id, key, type, required, options, position)
+VALUES (@project_id, @key, @type, @required, @options, @position);
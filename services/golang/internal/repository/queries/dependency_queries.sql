@@ -0,0 +1,22 @@
+-- name: CreateTaskDependency :one
+INSERT INTO task_dependencies (blocker_task_id, blocked_task_id, workspace_id, created_at)
+VALUES (@blocker_task_id, @blocked_task_id, @workspace_id, NOW())
+RETURNING blocker_task_id, blocked_task_id, workspace_id, created_at;
+
+-- name: DeleteTaskDependency :exec
+DELETE FROM task_dependencies WHERE blocker_task_id = @blocker_task_id AND blocked_task_id = @blocked_task_id;
+
+-- name: ListTaskDependencies :many
+SELECT d.blocker_task_id, d.blocked_task_id, d.workspace_id, d.created_at
+FROM task_dependencies d
+JOIN tasks b ON b.id = d.blocker_task_id AND b.deleted_at IS NULL
+JOIN tasks t ON t.id = d.blocked_task_id AND t.deleted_at IS NULL
+WHERE d.blocker_task_id = @task_id OR d.blocked_task_id = @task_id
+ORDER BY d.created_at, d.blocker_task_id, d.blocked_task_id;
+
+-- name: CountOpenBlockers :one
+SELECT COUNT(*)::int
+FROM task_dependencies d
+JOIN tasks b ON b.id = d.blocker_task_id AND b.deleted_at IS NULL
+JOIN project_statuses ps ON ps.project_id = b.project_id AND ps.name = b.status
+WHERE d.blocked_task_id = @task_id AND NOT ps.is_done;
@@ -0,0 +1,9 @@
+-- name: ListTaskHistory :many
+SELECT id, task_id, operation, COALESCE(actor_id, '') AS actor_id, changes, created_at
+FROM task_history
+WHERE task_id = @task_id
+ORDER BY created_at DESC, id DESC
+LIMIT @page_size;
+
+-- name: CountTaskHistory :one
+SELECT COUNT(*)::int FROM task_history WHERE task_id = @task_id;
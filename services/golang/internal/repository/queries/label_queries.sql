@@ -0,0 +1,31 @@
+-- name: CreateLabel :one
+INSERT INTO labels (id, workspace_id, name, color, created_at, updated_at)
+VALUES (gen_random_uuid(), @workspace_id, @name, @color, NOW(), NOW())
+RETURNING id, workspace_id, name, color, created_at, updated_at;
+
+-- name: GetLabel :one
+SELECT id, workspace_id, name, color, created_at, updated_at
+FROM labels WHERE id = @id;
+
+-- name: ListLabels :many
+SELECT id, workspace_id, name, color, created_at, updated_at
+FROM labels
+WHERE workspace_id = @workspace_id
+ORDER BY lower(name), id
+LIMIT @page_size;
+
+-- name: UpdateLabel :one
+UPDATE labels SET name = @name, color = @color, updated_at = NOW()
+WHERE id = @id
+RETURNING id, workspace_id, name, color, created_at, updated_at;
+
+-- name: DeleteLabel :exec
+DELETE FROM labels WHERE id = @id;
+
+-- name: AddTaskLabel :exec
+INSERT INTO task_labels (task_id, label_id, created_at)
+VALUES (@task_id, @label_id, NOW())
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveTaskLabel :exec
+DELETE FROM task_labels WHERE task_id = @task_id AND label_id = @label_id;
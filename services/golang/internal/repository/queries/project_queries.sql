@@ -1,15 +1,15 @@
 -- name: CreateProject :one
 INSERT INTO projects (id, workspace_id, name, description, status, created_at, updated_at)
 VALUES (gen_random_uuid(), @workspace_id, @name, @description, 'active', NOW(), NOW())
-RETURNING id, workspace_id, name, description, status, created_at, updated_at, deleted_at;
+RETURNING id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at;
 
 -- name: GetProjectByID :one
-SELECT id, workspace_id, name, description, status, created_at, updated_at, deleted_at
+SELECT id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at
 FROM projects
 WHERE id = @id AND deleted_at IS NULL;
 
 -- name: ListProjects :many
-SELECT id, workspace_id, name, description, status, created_at, updated_at, deleted_at
+SELECT id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at
 FROM projects
 WHERE workspace_id = @workspace_id AND deleted_at IS NULL
   AND (sqlc.narg('cursor_id')::uuid IS NULL OR id < sqlc.narg('cursor_id')::uuid)
@@ -23,8 +23,32 @@ SELECT COUNT(*)::int FROM projects WHERE workspace_id = @workspace_id AND delete
 UPDATE projects
 SET name = @name, description = @description, status = @status, updated_at = NOW()
 WHERE id = @id AND deleted_at IS NULL
-RETURNING id, workspace_id, name, description, status, created_at, updated_at, deleted_at;
+  AND (@expected_version::int = 0 OR version = @expected_version::int)
+RETURNING id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at;
 
 -- name: SoftDeleteProject :exec
 UPDATE projects SET deleted_at = NOW(), updated_at = NOW()
-WHERE id = @id AND deleted_at IS NULL;
+WHERE id = @id AND deleted_at IS NULL
+  AND (@expected_version::int = 0 OR version = @expected_version::int);
+
+-- name: SoftDeleteProjectTasks :exec
+-- Run in the same transaction as SoftDeleteProject.
+UPDATE tasks SET deleted_at = NOW(), updated_at = NOW()
+WHERE project_id = @project_id AND deleted_at IS NULL;
+
+-- name: ListDeletedProjects :many
+SELECT id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at
+FROM projects
+WHERE workspace_id = @workspace_id AND deleted_at IS NOT NULL
+  AND (sqlc.narg('cursor_id')::uuid IS NULL
+       OR (deleted_at, id) <= (SELECT c.deleted_at, c.id FROM projects c WHERE c.id = sqlc.narg('cursor_id')::uuid))
+ORDER BY deleted_at DESC, id DESC
+LIMIT @page_limit;
+
+-- name: CountDeletedProjects :one
+SELECT COUNT(*)::int FROM projects WHERE workspace_id = @workspace_id AND deleted_at IS NOT NULL;
+
+-- name: RestoreProject :one
+UPDATE projects SET deleted_at = NULL, updated_at = NOW()
+WHERE id = @id AND deleted_at IS NOT NULL
+RETURNING id, workspace_id, name, description, status, version, created_at, updated_at, deleted_at;
@@ -0,0 +1,29 @@
+-- name: SetTaskRecurrence :one
+INSERT INTO task_recurrences (id, task_id, rrule, dtstart, occurrence, created_at, updated_at)
+VALUES (gen_random_uuid(), @task_id, @rrule, @dtstart, 1, NOW(), NOW())
+ON CONFLICT (task_id) DO UPDATE
+SET rrule = EXCLUDED.rrule, dtstart = EXCLUDED.dtstart, occurrence = 1, updated_at = NOW()
+RETURNING id, task_id, rrule, dtstart, occurrence, created_at, updated_at;
+
+-- name: GetTaskRecurrence :one
+SELECT id, task_id, rrule, dtstart, occurrence, created_at, updated_at
+FROM task_recurrences WHERE task_id = @task_id;
+
+-- name: DeleteTaskRecurrence :exec
+DELETE FROM task_recurrences WHERE task_id = @task_id;
+
+-- name: ListDueTaskRecurrences :many
+SELECT r.id, r.task_id, r.rrule, r.dtstart, r.occurrence, r.created_at, r.updated_at, t.due_date
+FROM task_recurrences r
+JOIN tasks t ON t.id = r.task_id AND t.deleted_at IS NULL
+JOIN project_statuses ps ON ps.project_id = t.project_id AND ps.name = t.status
+WHERE ps.is_done OR t.due_date <= @today
+ORDER BY r.updated_at
+LIMIT @batch_size;
+
+-- name: AdvanceTaskRecurrence :exec
+UPDATE task_recurrences SET task_id = @new_task_id, occurrence = @occurrence, updated_at = NOW()
+WHERE id = @id;
+
+-- name: DeferTaskRecurrence :exec
+UPDATE task_recurrences SET updated_at = NOW() WHERE id = @id;
@@ -0,0 +1,15 @@
+-- name: GetReminderSettings :one
+SELECT w.id AS workspace_id,
+       COALESCE(s.due_soon_hours, '{24}')::int[] AS due_soon_hours,
+       COALESCE(s.overdue_hours, '{0}')::int[] AS overdue_hours,
+       s.updated_at
+FROM workspaces w
+LEFT JOIN workspace_reminder_settings s ON s.workspace_id = w.id
+WHERE w.id = @workspace_id AND w.deleted_at IS NULL;
+
+-- name: UpsertReminderSettings :one
+INSERT INTO workspace_reminder_settings (workspace_id, due_soon_hours, overdue_hours, updated_at)
+VALUES (@workspace_id, @due_soon_hours, @overdue_hours, NOW())
+ON CONFLICT (workspace_id) DO UPDATE
+SET due_soon_hours = EXCLUDED.due_soon_hours, overdue_hours = EXCLUDED.overdue_hours, updated_at = NOW()
+RETURNING workspace_id, due_soon_hours, overdue_hours, updated_at;
@@ -0,0 +1,28 @@
+-- name: SearchTasksAndComments :many
+WITH q AS (SELECT websearch_to_tsquery('english', @query) AS query),
+hits AS (
+    SELECT 'task' AS kind, t.id AS result_id, t.id AS task_id, t.title AS task_title,
+           concat_ws(' ', t.title, t.description) AS body,
+           ts_rank(t.search_vector, q.query) AS rank
+    FROM tasks t, q
+    WHERE t.workspace_id = @workspace_id AND t.deleted_at IS NULL
+      AND t.search_vector @@ q.query
+    UNION ALL
+    SELECT 'comment', c.id, t.id, t.title, c.content,
+           ts_rank(c.search_vector, q.query)
+    FROM task_comments c
+    JOIN tasks t ON t.id = c.task_id, q
+    WHERE t.workspace_id = @workspace_id AND t.deleted_at IS NULL
+      AND c.search_vector @@ q.query
+)
+SELECT kind, result_id, task_id, task_title,
+       ts_headline('english', replace(replace(replace(replace(body, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;'),
+                   (SELECT query FROM q),
+                   'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=10, MaxWords=30') AS snippet,
+       rank
+FROM (
+    SELECT * FROM hits
+    ORDER BY rank DESC, result_id DESC
+    LIMIT @page_size
+) page
+ORDER BY rank DESC, result_id DESC;
@@ -1,23 +1,104 @@
 -- name: CreateTask :one
-INSERT INTO tasks (id, workspace_id, project_id, title, description, status, priority, assigned_to, due_date, metadata, created_at, updated_at)
-VALUES (gen_random_uuid(), @workspace_id, @project_id, @title, @description, COALESCE(NULLIF(@status, ''), 'todo'), COALESCE(NULLIF(@priority, ''), 'medium'), NULLIF(@assigned_to, ''), @due_date, COALESCE(@metadata, '{}'::jsonb), NOW(), NOW())
-RETURNING id, workspace_id, project_id, title, description, status, priority, assigned_to, due_date, metadata, created_at, updated_at, deleted_at;
+INSERT INTO tasks (id, workspace_id, project_id, parent_task_id, title, description, status, priority, assigned_to, due_date, metadata, rank, original_estimate_seconds, remaining_estimate_seconds, created_at, updated_at)
+VALUES (gen_random_uuid(), @workspace_id, @project_id, sqlc.narg('parent_task_id'), @title, @description, COALESCE(NULLIF(@status, ''), 'todo'), COALESCE(NULLIF(@priority, ''), 'medium'), NULLIF(@assigned_to, ''), @due_date, COALESCE(@metadata, '{}'::jsonb), @rank, sqlc.narg('original_estimate_seconds'), COALESCE(sqlc.narg('remaining_estimate_seconds'), sqlc.narg('original_estimate_seconds')), NOW(), NOW())
+RETURNING id, workspace_id, project_id, parent_task_id, title, description, status, priority, assigned_to, due_date, metadata, rank, version, original_estimate_seconds, remaining_estimate_seconds, created_at, updated_at, deleted_at;
 
 -- name: GetTaskByID :one
-SELECT id, workspace_id, project_id, title, description, status, priority, assigned_to, due_date, metadata, created_at, updated_at, deleted_at
+SELECT id, workspace_id, project_id, parent_task_id, title, description, status, priority, assigned_to, due_date, metadata, rank, version, original_estimate_seconds, remaining_estimate_seconds, created_at, updated_at, deleted_at
 FROM tasks
 WHERE id = @id AND deleted_at IS NULL;
 
 -- name: UpdateTask :one
 UPDATE tasks
 SET title = @title, description = @description, status = @status, priority = @priority,
-    assigned_to = NULLIF(@assigned_to, ''), due_date = @due_date, metadata = COALESCE(@metadata, '{}'::jsonb), updated_at = NOW()
+    assigned_to = NULLIF(@assigned_to, ''), due_date = @due_date, metadata = COALESCE(@metadata, '{}'::jsonb),
+    original_estimate_seconds = sqlc.narg('original_estimate_seconds'), remaining_estimate_seconds = sqlc.narg('remaining_estimate_seconds'), updated_at = NOW()
 WHERE id = @id AND deleted_at IS NULL
-RETURNING id, workspace_id, project_id, title, description, status, priority, assigned_to, due_date, metadata, created_at, updated_at, deleted_at;
+  AND (@expected_version::int = 0 OR version = @expected_version::int)
+RETURNING id, workspace_id, project_id, parent_task_id, title, description, status, priority, assigned_to, due_date, metadata, rank, version, original_estimate_seconds, remaining_estimate_seconds, created_at, updated_at, deleted_at;
+
+-- name: LockStatusColumn :exec
+SELECT 1 FROM project_statuses WHERE project_id = @project_id AND name = @status FOR UPDATE;
+
+-- name: GetLastTaskRank :one
+SELECT COALESCE(MAX(rank), '')::text FROM tasks WHERE project_id = @project_id AND status = @status;
+
+-- name: MoveTask :one
+UPDATE tasks SET status = @status, rank = @rank, updated_at = NOW()
+WHERE id = @id
+RETURNING id, workspace_id, project_id, parent_task_id, title, description, status, priority, assigned_to, due_date, metadata, rank, version, original_estimate_seconds, remaining_estimate_seconds, created_at, updated_at, deleted_at;
+
+-- name: ListBoardColumns :many
+SELECT ps.name, ps.is_done, COUNT(t.id)::int AS total_count
+FROM project_statuses ps
+JOIN projects p ON p.id = ps.project_id AND p.deleted_at IS NULL
+LEFT JOIN tasks t ON t.project_id = ps.project_id AND t.status = ps.name AND t.deleted_at IS NULL
+WHERE ps.project_id = @project_id
+GROUP BY ps.name, ps.is_done, ps.position
+ORDER BY ps.position;
+
+-- name: ListBoardTasks :many
+SELECT t.id, t.workspace_id, t.project_id, t.parent_task_id, t.title, t.description, t.status, t.priority, t.assigned_to, t.due_date, t.metadata, t.rank, t.version, t.original_estimate_seconds, t.remaining_estimate_seconds, t.created_at, t.updated_at, t.deleted_at
+FROM (
+    SELECT id, row_number() OVER (PARTITION BY status ORDER BY rank, id) AS n
+    FROM tasks WHERE project_id = @project_id AND deleted_at IS NULL
+) page
+JOIN tasks t ON t.id = page.id
+WHERE page.n <= @page_limit
+ORDER BY t.status, t.rank, t.id;
 
 -- name: SoftDeleteTask :exec
+WITH RECURSIVE subtree(id) AS (
+    SELECT t.id FROM tasks t WHERE t.id = @id AND t.deleted_at IS NULL
+      AND (@expected_version::int = 0 OR t.version = @expected_version::int)
+    UNION
+    SELECT c.id FROM tasks c JOIN subtree s ON c.parent_task_id = s.id WHERE c.deleted_at IS NULL
+)
 UPDATE tasks SET deleted_at = NOW(), updated_at = NOW()
-WHERE id = @id AND deleted_at IS NULL;
+WHERE tasks.id IN (SELECT subtree.id FROM subtree);
+
+-- name: CountSubtasks :one
+SELECT COUNT(*)::int FROM tasks WHERE parent_task_id = @parent_task_id AND deleted_at IS NULL;
 
 -- name: CountTasks :one
 SELECT COUNT(*)::int FROM tasks WHERE workspace_id = @workspace_id AND deleted_at IS NULL;
+
+-- name: ListTaskAssignees :many
+SELECT user_id FROM task_assignees WHERE task_id = @task_id ORDER BY position;
+
+-- name: RemoveTaskAssignees :exec
+DELETE FROM task_assignees WHERE task_id = @task_id AND user_id <> ALL(@user_ids::varchar[]);
+
+-- name: SetTaskAssignees :exec
+INSERT INTO task_assignees (task_id, user_id, position, created_at)
+SELECT @task_id, a.user_id, a.n - 1, NOW()
+FROM unnest(@user_ids::varchar[]) WITH ORDINALITY AS a(user_id, n)
+ON CONFLICT (task_id, user_id) DO UPDATE SET position = EXCLUDED.position;
+
+-- name: CopyTaskLabels :exec
+INSERT INTO task_labels (task_id, label_id, created_at)
+SELECT @new_task_id, label_id, created_at FROM task_labels WHERE task_id = @task_id;
+
+-- name: CopyTaskComments :exec
+INSERT INTO task_comments (id, task_id, author_id, content, created_at, updated_at)
+SELECT gen_random_uuid(), @new_task_id, author_id, content, created_at, updated_at
+FROM task_comments WHERE task_id = @task_id;
+
+-- name: ListTaskSubtreeStatuses :many
+WITH RECURSIVE subtree(id, depth) AS (
+    SELECT tasks.id, 1 FROM tasks WHERE tasks.id = @id
+    UNION
+    SELECT c.id, s.depth + 1 FROM tasks c JOIN subtree s ON c.parent_task_id = s.id
+)
+SELECT t.id, COALESCE(ps.name, @initial_status::varchar) AS status
+FROM subtree s
+JOIN tasks t ON t.id = s.id
+LEFT JOIN project_statuses ps ON ps.project_id = @project_id AND ps.name = t.status
+ORDER BY s.depth, t.rank;
+
+-- name: MoveTaskToProject :exec
+UPDATE tasks
+SET project_id = @project_id, status = @status, rank = @rank,
+    parent_task_id = CASE WHEN tasks.id = @root_id THEN NULL ELSE parent_task_id END,
+    updated_at = NOW()
+WHERE tasks.id = @id;
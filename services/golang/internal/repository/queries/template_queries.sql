@@ -0,0 +1,39 @@
+-- name: CreateTemplate :one
+INSERT INTO task_templates (id, workspace_id, project_id, name, title, description, priority, metadata, subtasks, created_at, updated_at)
+SELECT gen_random_uuid(), p.workspace_id, p.id, @name, @title, @description, COALESCE(NULLIF(@priority, ''), 'medium'), @metadata, @subtasks, NOW(), NOW()
+FROM projects p WHERE p.id = @project_id AND p.deleted_at IS NULL
+RETURNING id, workspace_id, project_id, name, title, description, priority, metadata, subtasks, created_at, updated_at;
+
+-- name: GetTemplate :one
+SELECT tt.id, tt.workspace_id, tt.project_id, tt.name, tt.title, tt.description, tt.priority, tt.metadata, tt.subtasks, tt.created_at, tt.updated_at
+FROM task_templates tt
+JOIN projects p ON p.id = tt.project_id AND p.deleted_at IS NULL
+WHERE tt.id = @id;
+
+-- name: ListTemplates :many
+SELECT tt.id, tt.workspace_id, tt.project_id, tt.name, tt.title, tt.description, tt.priority, tt.metadata, tt.subtasks, tt.created_at, tt.updated_at
+FROM task_templates tt
+JOIN projects p ON p.id = tt.project_id AND p.deleted_at IS NULL
+WHERE tt.project_id = @project_id
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (lower(tt.name), tt.id) >= (SELECT lower(c.name), c.id FROM task_templates c WHERE c.id = sqlc.narg('cursor_id')::uuid))
+ORDER BY lower(tt.name), tt.id
+LIMIT @page_limit;
+
+-- name: CountTemplates :one
+SELECT COUNT(*)::int
+FROM task_templates tt
+JOIN projects p ON p.id = tt.project_id AND p.deleted_at IS NULL
+WHERE tt.project_id = @project_id;
+
+-- name: UpdateTemplate :one
+UPDATE task_templates tt
+SET name = @name, title = @title, description = @description, priority = COALESCE(NULLIF(@priority, ''), 'medium'),
+    metadata = @metadata, subtasks = @subtasks, updated_at = NOW()
+FROM projects p
+WHERE tt.id = @id AND p.id = tt.project_id AND p.deleted_at IS NULL
+RETURNING tt.id, tt.workspace_id, tt.project_id, tt.name, tt.title, tt.description, tt.priority, tt.metadata, tt.subtasks, tt.created_at, tt.updated_at;
+
+-- name: DeleteTemplate :exec
+DELETE FROM task_templates tt
+USING projects p
+WHERE tt.id = @id AND p.id = tt.project_id AND p.deleted_at IS NULL;
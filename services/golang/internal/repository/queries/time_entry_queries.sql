@@ -0,0 +1,57 @@
+-- name: CreateTimeEntry :one
+INSERT INTO time_entries (id, task_id, workspace_id, user_id, started_at, duration_seconds, note, created_at, updated_at)
+SELECT gen_random_uuid(), t.id, t.workspace_id, @user_id, @started_at, @duration_seconds, @note, NOW(), NOW()
+FROM tasks t WHERE t.id = @task_id AND t.deleted_at IS NULL
+RETURNING id, task_id, workspace_id, user_id, started_at, duration_seconds, note, created_at, updated_at;
+
+-- name: GetTimeEntry :one
+SELECT e.id, e.task_id, e.workspace_id, e.user_id, e.started_at, e.duration_seconds, e.note, e.created_at, e.updated_at
+FROM time_entries e
+JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+WHERE e.id = @id;
+
+-- name: ListTimeEntries :many
+SELECT e.id, e.task_id, e.workspace_id, e.user_id, e.started_at, e.duration_seconds, e.note, e.created_at, e.updated_at
+FROM time_entries e
+JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+WHERE e.workspace_id = @workspace_id
+  AND (sqlc.narg('task_id')::uuid IS NULL OR e.task_id = sqlc.narg('task_id')::uuid)
+  AND (sqlc.narg('user_id')::varchar IS NULL OR e.user_id = sqlc.narg('user_id')::varchar)
+  AND (sqlc.narg('start_time')::timestamptz IS NULL OR e.started_at >= sqlc.narg('start_time')::timestamptz)
+  AND (sqlc.narg('end_time')::timestamptz IS NULL OR e.started_at < sqlc.narg('end_time')::timestamptz)
+  AND (sqlc.narg('cursor_id')::uuid IS NULL OR (e.started_at, e.id) <= (SELECT c.started_at, c.id FROM time_entries c WHERE c.id = sqlc.narg('cursor_id')::uuid))
+ORDER BY e.started_at DESC, e.id DESC
+LIMIT @page_limit;
+
+-- name: CountTimeEntries :one
+SELECT COUNT(*)::int
+FROM time_entries e
+JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+WHERE e.workspace_id = @workspace_id
+  AND (sqlc.narg('task_id')::uuid IS NULL OR e.task_id = sqlc.narg('task_id')::uuid)
+  AND (sqlc.narg('user_id')::varchar IS NULL OR e.user_id = sqlc.narg('user_id')::varchar)
+  AND (sqlc.narg('start_time')::timestamptz IS NULL OR e.started_at >= sqlc.narg('start_time')::timestamptz)
+  AND (sqlc.narg('end_time')::timestamptz IS NULL OR e.started_at < sqlc.narg('end_time')::timestamptz);
+
+-- name: UpdateTimeEntry :one
+UPDATE time_entries e
+SET started_at = @started_at, duration_seconds = @duration_seconds, note = @note, updated_at = NOW()
+FROM tasks t
+WHERE e.id = @id AND t.id = e.task_id AND t.deleted_at IS NULL
+RETURNING e.id, e.task_id, e.workspace_id, e.user_id, e.started_at, e.duration_seconds, e.note, e.created_at, e.updated_at;
+
+-- name: DeleteTimeEntry :execrows
+DELETE FROM time_entries e
+USING tasks t
+WHERE e.id = @id AND t.id = e.task_id AND t.deleted_at IS NULL;
+
+-- name: SumTimeEntriesByProject :many
+SELECT t.project_id, SUM(e.duration_seconds)::bigint AS duration_seconds, COUNT(*)::int AS entry_count
+FROM time_entries e
+JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+WHERE e.workspace_id = @workspace_id
+  AND (sqlc.narg('project_id')::uuid IS NULL OR t.project_id = sqlc.narg('project_id')::uuid)
+  AND (sqlc.narg('start_time')::timestamptz IS NULL OR e.started_at >= sqlc.narg('start_time')::timestamptz)
+  AND (sqlc.narg('end_time')::timestamptz IS NULL OR e.started_at < sqlc.narg('end_time')::timestamptz)
+GROUP BY t.project_id
+ORDER BY 2 DESC, 1;
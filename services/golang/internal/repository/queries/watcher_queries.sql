@@ -0,0 +1,27 @@
+-- name: WatchTask :one
+INSERT INTO task_watchers (task_id, user_id, reason, created_at)
+VALUES (@task_id, @user_id, 'manual', NOW())
+ON CONFLICT (task_id, user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING user_id, reason, created_at;
+
+-- name: UnwatchTask :execrows
+DELETE FROM task_watchers WHERE task_id = @task_id AND user_id = @user_id;
+
+-- name: ListTaskWatchers :many
+SELECT user_id, reason, created_at FROM task_watchers
+WHERE task_id = @task_id
+ORDER BY created_at, user_id;
+
+-- name: WatchProject :one
+INSERT INTO project_watchers (project_id, user_id, created_at)
+VALUES (@project_id, @user_id, NOW())
+ON CONFLICT (project_id, user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+RETURNING user_id, created_at;
+
+-- name: UnwatchProject :execrows
+DELETE FROM project_watchers WHERE project_id = @project_id AND user_id = @user_id;
+
+-- name: ListProjectWatchers :many
+SELECT user_id, created_at FROM project_watchers
+WHERE project_id = @project_id
+ORDER BY created_at, user_id;
@@ -0,0 +1,23 @@
+-- name: ListProjectStatuses :many
+SELECT project_id, name, position, is_done
+FROM project_statuses
+WHERE project_id = @project_id
+ORDER BY position, name;
+
+-- name: ListProjectStatusTransitions :many
+SELECT project_id, from_status, to_status
+FROM project_status_transitions
+WHERE project_id = @project_id;
+
+-- name: UpsertProjectStatus :exec
+INSERT INTO project_statuses (project_id, name, position, is_done)
+VALUES (@project_id, @name, @position, @is_done)
+ON CONFLICT (project_id, name) DO UPDATE SET position = EXCLUDED.position, is_done = EXCLUDED.is_done;
+
+-- name: DeleteProjectStatusTransitions :exec
+DELETE FROM project_status_transitions WHERE project_id = @project_id;
+
+-- name: CreateProjectStatusTransition :exec
+INSERT INTO project_status_transitions (project_id, from_status, to_status)
+VALUES (@project_id, @from_status, @to_status)
+ON CONFLICT DO NOTHING;
@@ -1,15 +1,15 @@
 -- name: CreateWorkspace :one
 INSERT INTO workspaces (id, name, slug, created_at, updated_at)
 VALUES (gen_random_uuid(), @name, @slug, NOW(), NOW())
-RETURNING id, name, slug, created_at, updated_at, deleted_at;
+RETURNING id, name, slug, version, created_at, updated_at, deleted_at;
 
 -- name: GetWorkspaceByID :one
-SELECT id, name, slug, created_at, updated_at, deleted_at
+SELECT id, name, slug, version, created_at, updated_at, deleted_at
 FROM workspaces
 WHERE id = @id AND deleted_at IS NULL;
 
 -- name: ListWorkspaces :many
-SELECT id, name, slug, created_at, updated_at, deleted_at
+SELECT id, name, slug, version, created_at, updated_at, deleted_at
 FROM workspaces
 WHERE deleted_at IS NULL
   AND (sqlc.narg('cursor_id')::uuid IS NULL OR id < sqlc.narg('cursor_id')::uuid)
@@ -23,8 +23,37 @@ SELECT COUNT(*)::int FROM workspaces WHERE deleted_at IS NULL;
 UPDATE workspaces
 SET name = @name, slug = @slug, updated_at = NOW()
 WHERE id = @id AND deleted_at IS NULL
-RETURNING id, name, slug, created_at, updated_at, deleted_at;
+  AND (@expected_version::int = 0 OR version = @expected_version::int)
+RETURNING id, name, slug, version, created_at, updated_at, deleted_at;
 
 -- name: SoftDeleteWorkspace :exec
 UPDATE workspaces SET deleted_at = NOW(), updated_at = NOW()
-WHERE id = @id AND deleted_at IS NULL;
+WHERE id = @id AND deleted_at IS NULL
+  AND (@expected_version::int = 0 OR version = @expected_version::int);
+
+-- name: SoftDeleteWorkspaceProjects :exec
+-- Run in the same transaction as SoftDeleteWorkspace.
+UPDATE projects SET deleted_at = NOW(), updated_at = NOW()
+WHERE workspace_id = @workspace_id AND deleted_at IS NULL;
+
+-- name: SoftDeleteWorkspaceTasks :exec
+-- Run in the same transaction as SoftDeleteWorkspace.
+UPDATE tasks SET deleted_at = NOW(), updated_at = NOW()
+WHERE workspace_id = @workspace_id AND deleted_at IS NULL;
+
+-- name: ListDeletedWorkspaces :many
+SELECT id, name, slug, version, created_at, updated_at, deleted_at
+FROM workspaces
+WHERE deleted_at IS NOT NULL
+  AND (sqlc.narg('cursor_id')::uuid IS NULL
+       OR (deleted_at, id) <= (SELECT c.deleted_at, c.id FROM workspaces c WHERE c.id = sqlc.narg('cursor_id')::uuid))
+ORDER BY deleted_at DESC, id DESC
+LIMIT @page_limit;
+
+-- name: CountDeletedWorkspaces :one
+SELECT COUNT(*)::int FROM workspaces WHERE deleted_at IS NOT NULL;
+
+-- name: RestoreWorkspace :one
+UPDATE workspaces SET deleted_at = NULL, updated_at = NOW()
+WHERE id = @id AND deleted_at IS NOT NULL
+RETURNING id, name, slug, version, created_at, updated_at, deleted_at;
@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type RecurrenceRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewRecurrenceRepo(pool *pgxpool.Pool) *RecurrenceRepo {
+	return &RecurrenceRepo{pool: pool}
+}
+
+// Set creates or replaces the recurrence on a task. Replacing restarts the
+// series at the task.
+func (r *RecurrenceRepo) Set(ctx context.Context, params SetRecurrenceParams) (*TaskRecurrence, error) {
+	var rec TaskRecurrence
+	err := r.pool.QueryRow(ctx,
+		`INSERT INTO task_recurrences (id, task_id, rrule, dtstart, occurrence, created_at, updated_at)
+		 VALUES (gen_random_uuid(), $1, $2, $3, 1, NOW(), NOW())
+		 ON CONFLICT (task_id) DO UPDATE
+		 SET rrule = EXCLUDED.rrule, dtstart = EXCLUDED.dtstart, occurrence = 1, updated_at = NOW()
+		 RETURNING id, task_id, rrule, dtstart, occurrence, created_at, updated_at`,
+		params.TaskID, params.RRule, params.DTStart,
+	).Scan(&rec.ID, &rec.TaskID, &rec.RRule, &rec.DTStart, &rec.Occurrence, &rec.CreatedAt, &rec.UpdatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("set task recurrence: %w", err)
+	}
+	return &rec, nil
+}
+
+func (r *RecurrenceRepo) GetByTaskID(ctx context.Context, taskID uuid.UUID) (*TaskRecurrence, error) {
+	var rec TaskRecurrence
+	err := r.pool.QueryRow(ctx,
+		`SELECT id, task_id, rrule, dtstart, occurrence, created_at, updated_at
+		 FROM task_recurrences WHERE task_id = $1`,
+		taskID,
+	).Scan(&rec.ID, &rec.TaskID, &rec.RRule, &rec.DTStart, &rec.Occurrence, &rec.CreatedAt, &rec.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get task recurrence: %w", err)
+	}
+	return &rec, nil
+}
+
+func (r *RecurrenceRepo) DeleteByTaskID(ctx context.Context, taskID uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx, `DELETE FROM task_recurrences WHERE task_id = $1`, taskID)
+	if err != nil {
+		return fmt.Errorf("delete task recurrence: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListDue returns series whose latest occurrence is live and either in a
+// done status or due on or before the given date, least recently touched
+// first. Series in archived projects are paused.
+func (r *RecurrenceRepo) ListDue(ctx context.Context, today time.Time, limit int) ([]DueRecurrence, error) {
+	rows, err := r.pool.Query(ctx,
+		`SELECT r.id, r.task_id, r.rrule, r.dtstart, r.occurrence, r.created_at, r.updated_at, t.due_date
+		 FROM task_recurrences r
+		 JOIN tasks t ON t.id = r.task_id AND t.deleted_at IS NULL
+		 JOIN projects p ON p.id = t.project_id AND p.status <> 'archived'
+		 JOIN project_statuses ps ON ps.project_id = t.project_id AND ps.name = t.status
+		 WHERE ps.is_done OR t.due_date <= $1
+		 ORDER BY r.updated_at
+		 LIMIT $2`,
+		today, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list due recurrences: %w", err)
+	}
+	defer rows.Close()
+
+	var due []DueRecurrence
+	for rows.Next() {
+		var d DueRecurrence
+		if err := rows.Scan(&d.ID, &d.TaskID, &d.RRule, &d.DTStart, &d.Occurrence,
+			&d.CreatedAt, &d.UpdatedAt, &d.TaskDueDate); err != nil {
+			return nil, fmt.Errorf("scan recurrence: %w", err)
+		}
+		due = append(due, d)
+	}
+	return due, rows.Err()
+}
+
+// Advance creates the next occurrence of a series by copying the current
+// task (fields and labels, not subtasks, comments or time logged) into the
+// project's initial status with the given due date and the remaining
+// estimate reset to the original, and points the series at it.
+// It returns ErrConflict if the series moved on since it was read.
+func (r *RecurrenceRepo) Advance(ctx context.Context, rec *TaskRecurrence, dueDate time.Time, occurrence int32) (*Task, error) {
+	var t Task
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`SELECT 1 FROM task_recurrences WHERE id = $1 AND task_id = $2 FOR UPDATE`,
+			rec.ID, rec.TaskID)
+		if err != nil {
+			return err
+		}
+		if tag.RowsAffected() == 0 {
+			return ErrConflict
+		}
+
+		var workspaceID, projectID uuid.UUID
+		if err := tx.QueryRow(ctx,
+			`SELECT workspace_id, project_id FROM tasks WHERE id = $1`, rec.TaskID,
+		).Scan(&workspaceID, &projectID); err != nil {
+			return err
+		}
+		if err := lockProject(ctx, tx, workspaceID, projectID); err != nil {
+			return err
+		}
+		status, err := initialStatus(ctx, tx, projectID)
+		if err != nil {
+			return err
+		}
+		rk, err := lastRank(ctx, tx, projectID, status)
+		if err != nil {
+			return err
+		}
+
+		err = scanTask(tx.QueryRow(ctx,
+			`INSERT INTO tasks AS t (id, workspace_id, project_id, parent_task_id, title, description, status, priority, assigned_to, due_date, metadata, rank,
+			                         original_estimate_seconds, remaining_estimate_seconds, created_at, updated_at)
+			 SELECT gen_random_uuid(), src.workspace_id, src.project_id, src.parent_task_id, src.title, src.description,
+			        $3, src.priority, src.assigned_to, $2, src.metadata, $4,
+			        src.original_estimate_seconds, src.original_estimate_seconds, NOW(), NOW()
+			 FROM tasks src WHERE src.id = $1
+			 RETURNING `+taskColumns,
+			rec.TaskID, dueDate, status, rk,
+		), &t)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO task_labels (task_id, label_id, created_at)
+			 SELECT $2, label_id, NOW() FROM task_labels WHERE task_id = $1`,
+			rec.TaskID, t.ID); err != nil {
+			return err
+		}
+		// The insert's sync trigger has already added the first assignee.
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO task_assignees (task_id, user_id, position, created_at)
+			 SELECT $2, user_id, position, NOW() FROM task_assignees WHERE task_id = $1
+			 ON CONFLICT (task_id, user_id) DO UPDATE SET position = EXCLUDED.position`,
+			rec.TaskID, t.ID); err != nil {
+			return err
+		}
+		// Read the copied labels and assignees back; RETURNING ran before
+		// they existed.
+		if err := tx.QueryRow(ctx,
+			`SELECT ARRAY(SELECT label_id FROM task_labels WHERE task_id = $1 ORDER BY created_at, label_id),
+			        ARRAY(SELECT user_id FROM task_assignees WHERE task_id = $1 ORDER BY position)`,
+			t.ID).Scan(&t.LabelIDs, &t.Assignees); err != nil {
+			return err
+		}
+		if err := recordNewAssignees(ctx, tx, t.ID, t.Assignees); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx,
+			`UPDATE task_recurrences SET task_id = $2, occurrence = $3, updated_at = NOW()
+			 WHERE id = $1`,
+			rec.ID, t.ID, occurrence)
+		return err
+	})
+	if err != nil {
+		if err == ErrConflict {
+			return nil, err
+		}
+		return nil, fmt.Errorf("advance task recurrence: %w", err)
+	}
+	return &t, nil
+}
+
+// Defer moves a due series that could not be advanced to the back of the
+// ListDue order, so that it cannot hold up the others batch after batch.
+func (r *RecurrenceRepo) Defer(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `UPDATE task_recurrences SET updated_at = NOW() WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("defer task recurrence: %w", err)
+	}
+	return nil
+}
+
+// End removes a series whose rule has no further occurrences.
+func (r *RecurrenceRepo) End(ctx context.Context, id uuid.UUID) error {
+	if _, err := r.pool.Exec(ctx, `DELETE FROM task_recurrences WHERE id = $1`, id); err != nil {
+		return fmt.Errorf("end task recurrence: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TaskRecurrence is a recurring series. TaskID is the latest occurrence;
+// Occurrence is its 1-based position in the series that starts at DTStart.
+type TaskRecurrence struct {
+	ID          uuid.UUID
+	TaskID      uuid.UUID
+	RRule       string
+	DTStart     time.Time
+	Occurrence  int32
+	NextDueDate *time.Time // computed by the service; nil once the series has ended
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type SetRecurrenceParams struct {
+	TaskID  uuid.UUID
+	RRule   string
+	DTStart time.Time
+}
+
+// DueRecurrence is a series whose latest occurrence is done or due, i.e.
+// ready for the next occurrence to be created.
+type DueRecurrence struct {
+	TaskRecurrence
+	TaskDueDate *time.Time
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type ReminderRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewReminderRepo(pool *pgxpool.Pool) *ReminderRepo {
+	return &ReminderRepo{pool: pool}
+}
+
+// GetSettings returns a workspace's reminder settings, falling back to the
+// defaults when none were saved.
+func (r *ReminderRepo) GetSettings(ctx context.Context, workspaceID uuid.UUID) (*ReminderSettings, error) {
+	var s ReminderSettings
+	err := r.pool.QueryRow(ctx,
+		`SELECT w.id, COALESCE(s.due_soon_hours, '{24}'), COALESCE(s.overdue_hours, '{0}'), s.updated_at
+		 FROM workspaces w
+		 LEFT JOIN workspace_reminder_settings s ON s.workspace_id = w.id
+		 WHERE w.id = $1 AND w.deleted_at IS NULL`,
+		workspaceID,
+	).Scan(&s.WorkspaceID, &s.DueSoonHours, &s.OverdueHours, &s.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get reminder settings: %w", err)
+	}
+	return &s, nil
+}
+
+func (r *ReminderRepo) UpdateSettings(ctx context.Context, settings ReminderSettings) (*ReminderSettings, error) {
+	var s ReminderSettings
+	err := r.pool.QueryRow(ctx,
+		`INSERT INTO workspace_reminder_settings (workspace_id, due_soon_hours, overdue_hours, updated_at)
+		 SELECT id, $2, $3, NOW() FROM workspaces WHERE id = $1 AND deleted_at IS NULL
+		 ON CONFLICT (workspace_id) DO UPDATE
+		 SET due_soon_hours = EXCLUDED.due_soon_hours, overdue_hours = EXCLUDED.overdue_hours, updated_at = NOW()
+		 RETURNING workspace_id, due_soon_hours, overdue_hours, updated_at`,
+		settings.WorkspaceID, settings.DueSoonHours, settings.OverdueHours,
+	).Scan(&s.WorkspaceID, &s.DueSoonHours, &s.OverdueHours, &s.UpdatedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("update reminder settings: %w", err)
+	}
+	return &s, nil
+}
+
+// EnqueueDue queues task.due_soon and task.overdue notifications for every
+// open task that has crossed one of its workspace's thresholds as of now.
+// Recording the reminder and queueing the notification happen in a single
+// statement, so each one is queued exactly once even if runs overlap.
+// Thresholds are capped at 720 hours, which bounds the due dates scanned.
+func (r *ReminderRepo) EnqueueDue(ctx context.Context, now time.Time) (int, error) {
+	tag, err := r.pool.Exec(ctx,
+		`WITH settings AS (
+		     SELECT w.id AS workspace_id,
+		            COALESCE(s.due_soon_hours, '{24}') AS due_soon_hours,
+		            COALESCE(s.overdue_hours, '{0}') AS overdue_hours
+		     FROM workspaces w
+		     LEFT JOIN workspace_reminder_settings s ON s.workspace_id = w.id
+		     WHERE w.deleted_at IS NULL
+		 ),
+		 open_tasks AS (
+		     SELECT t.id, t.workspace_id, t.title, t.due_date,
+		            (t.due_date + 1)::timestamp AT TIME ZONE 'UTC' AS deadline,
+		            s.due_soon_hours, s.overdue_hours
+		     FROM tasks t
+		     JOIN settings s ON s.workspace_id = t.workspace_id
+		     JOIN project_statuses ps ON ps.project_id = t.project_id AND ps.name = t.status
+		     WHERE t.deleted_at IS NULL AND NOT ps.is_done
+		       AND t.due_date BETWEEN ($1::timestamptz AT TIME ZONE 'UTC')::date - 31
+		                          AND ($1::timestamptz AT TIME ZONE 'UTC')::date + 31
+		 ),
+		 candidates AS (
+		     SELECT o.id AS task_id, o.workspace_id, o.title, o.due_date,
+		            'task.due_soon' AS event_type, h AS threshold_hours
+		     FROM open_tasks o, unnest(o.due_soon_hours) AS h
+		     WHERE $1 >= o.deadline - make_interval(hours => h) AND $1 < o.deadline
+		     UNION ALL
+		     SELECT o.id, o.workspace_id, o.title, o.due_date,
+		            'task.overdue', h
+		     FROM open_tasks o, unnest(o.overdue_hours) AS h
+		     WHERE $1 >= o.deadline + make_interval(hours => h)
+		 ),
+		 recorded AS (
+		     INSERT INTO task_due_notifications (task_id, event_type, threshold_hours, due_date, created_at)
+		     SELECT task_id, event_type, threshold_hours, due_date, NOW() FROM candidates
+		     ON CONFLICT DO NOTHING
+		     RETURNING task_id, event_type, threshold_hours, due_date
+		 )
+		 INSERT INTO notification_queue (workspace_id, event_type, payload, status, created_at)
+		 SELECT c.workspace_id, r.event_type,
+		        jsonb_build_object('task_id', r.task_id, 'title', c.title,
+		                           'due_date', r.due_date, 'threshold_hours', r.threshold_hours),
+		        'pending', NOW()
+		 FROM recorded r
+		 JOIN candidates c USING (task_id, event_type, threshold_hours, due_date)`,
+		now)
+	if err != nil {
+		return 0, fmt.Errorf("enqueue due reminders: %w", err)
+	}
+	return int(tag.RowsAffected()), nil
+}
@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReminderSettings holds a workspace's due-date reminder thresholds, in hours
+// relative to the end of a task's due date (midnight UTC after it).
+type ReminderSettings struct {
+	WorkspaceID  uuid.UUID
+	DueSoonHours []int32    // task.due_soon fires this many hours before the deadline
+	OverdueHours []int32    // task.overdue fires this many hours after the deadline
+	UpdatedAt    *time.Time // nil while the workspace uses the defaults
+}
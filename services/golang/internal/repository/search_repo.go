@@ -0,0 +1,156 @@
+package repository
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SearchRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewSearchRepo(pool *pgxpool.Pool) *SearchRepo {
+	return &SearchRepo{pool: pool}
+}
+
+// searchHits matches live tasks and their comments against the query.
+// Parameters: $1 workspace, $2 query text, $3 optional project.
+const searchHits = `WITH q AS (SELECT websearch_to_tsquery('english', $2) AS query),
+hits AS (
+	SELECT 'task' AS kind, t.id AS result_id, t.id AS task_id, t.title AS task_title,
+	       concat_ws(' ', t.title, t.description) AS body,
+	       ts_rank(t.search_vector, q.query) AS rank
+	FROM tasks t, q
+	WHERE t.workspace_id = $1 AND t.deleted_at IS NULL
+	  AND ($3::uuid IS NULL OR t.project_id = $3)
+	  AND t.search_vector @@ q.query
+	UNION ALL
+	SELECT 'comment', c.id, t.id, t.title, c.content,
+	       ts_rank(c.search_vector, q.query)
+	FROM task_comments c
+	JOIN tasks t ON t.id = c.task_id, q
+	WHERE t.workspace_id = $1 AND t.deleted_at IS NULL
+	  AND ($3::uuid IS NULL OR t.project_id = $3)
+	  AND c.search_vector @@ q.query
+)`
+
+// escapedBody is the body of a hit with the HTML special characters
+// escaped, so that the <mark> tags ts_headline adds are the only markup in a
+// snippet. The default text search parser reads entities as non-words, so
+// highlighting is unaffected.
+const escapedBody = `replace(replace(replace(replace(body, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&quot;')`
+
+// Search returns matches ordered by rank. Snippets are only built for the
+// rows on the requested page. The page token encodes the (rank, id) of the
+// last result so pages stay stable while rows are added.
+func (r *SearchRepo) Search(ctx context.Context, params SearchParams) (*SearchResultList, error) {
+	pageSize := params.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	var projectID *uuid.UUID
+	if params.ProjectID != uuid.Nil {
+		projectID = &params.ProjectID
+	}
+
+	var cursorRank *float32
+	var cursorID uuid.UUID
+	if params.PageToken != "" {
+		rank, id, err := decodeSearchToken(params.PageToken)
+		if err != nil {
+			return nil, err
+		}
+		cursorRank, cursorID = &rank, id
+	}
+
+	var totalCount int32
+	err := r.pool.QueryRow(ctx,
+		searchHits+` SELECT COUNT(*)::int FROM hits`,
+		params.WorkspaceID, params.Query, projectID,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("count search results: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx,
+		searchHits+`
+		SELECT kind, result_id, task_id, task_title,
+		       ts_headline('english', `+escapedBody+`, (SELECT query FROM q),
+		                   'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MinWords=10, MaxWords=30'),
+		       rank
+		FROM (
+			SELECT * FROM hits
+			WHERE $4::real IS NULL OR (rank, result_id) < ($4::real, $5::uuid)
+			ORDER BY rank DESC, result_id DESC
+			LIMIT $6
+		) page
+		ORDER BY rank DESC, result_id DESC`,
+		params.WorkspaceID, params.Query, projectID, cursorRank, cursorID, pageSize+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+	defer rows.Close()
+
+	var results []SearchResult
+	var resultIDs []uuid.UUID
+	for rows.Next() {
+		var res SearchResult
+		var resultID uuid.UUID
+		if err := rows.Scan(&res.Type, &resultID, &res.TaskID, &res.TaskTitle, &res.Snippet, &res.Rank); err != nil {
+			return nil, fmt.Errorf("scan search result: %w", err)
+		}
+		if res.Type == SearchResultComment {
+			res.CommentID = &resultID
+		}
+		results = append(results, res)
+		resultIDs = append(resultIDs, resultID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+
+	var nextPageToken string
+	if len(results) > int(pageSize) {
+		results = results[:pageSize]
+		nextPageToken = encodeSearchToken(results[pageSize-1].Rank, resultIDs[pageSize-1])
+	}
+
+	return &SearchResultList{
+		Results:       results,
+		NextPageToken: nextPageToken,
+		TotalCount:    totalCount,
+	}, nil
+}
+
+func encodeSearchToken(rank float32, id uuid.UUID) string {
+	raw := strconv.FormatFloat(float64(rank), 'g', -1, 32) + "|" + id.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSearchToken(token string) (float32, uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, uuid.Nil, fmt.Errorf("%w: invalid page token", ErrInvalidInput)
+	}
+	rankStr, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return 0, uuid.Nil, fmt.Errorf("%w: invalid page token", ErrInvalidInput)
+	}
+	rank, err := strconv.ParseFloat(rankStr, 32)
+	if err != nil {
+		return 0, uuid.Nil, fmt.Errorf("%w: invalid page token", ErrInvalidInput)
+	}
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return 0, uuid.Nil, fmt.Errorf("%w: invalid page token", ErrInvalidInput)
+	}
+	return float32(rank), id, nil
+}
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+)
+
+const (
+	SearchResultTask    = "task"
+	SearchResultComment = "comment"
+)
+
+type SearchResult struct {
+	Type      string // task or comment
+	TaskID    uuid.UUID
+	CommentID *uuid.UUID
+	TaskTitle string
+	Snippet   string
+	Rank      float32
+}
+
+type SearchParams struct {
+	WorkspaceID uuid.UUID
+	ProjectID   uuid.UUID // optional filter
+	Query       string
+	PageSize    int32
+	PageToken   string
+}
+
+type SearchResultList struct {
+	Results       []SearchResult
+	NextPageToken string
+	TotalCount    int32
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Sort orders a listing by one of its sort keys, with the row ID breaking
+// ties. The zero value is the listing's default order.
+type Sort struct {
+	Field string // "" for the listing's default key
+	Desc  *bool  // nil for the key's default direction
+}
+
+// sortKey is an expression a listing can be ordered by. expr refers to the
+// listed table's alias as %[1]s and must never be NULL, so that rows compare
+// as tuples; typ is its SQL type, used to read it back from a page token.
+type sortKey struct {
+	expr string
+	typ  string
+	desc bool // default direction
+}
+
+// keyset resolves s against keys, which must include the default key "",
+// and returns the ORDER BY list for a listing aliased as alias, the sort
+// value (as text) to select as the listing's last column, and the condition
+// that starts a page at a cursor whose sort value and row ID are parameters
+// $cursorArg and $cursorArg+1. Page tokens hold the sort value and ID of
+// the first row of the next page, so a page starts in the right place even
+// if that row has since changed or gone.
+func keyset(keys map[string]sortKey, s Sort, alias string, cursorArg int) (orderBy, value, cursorCond string, err error) {
+	key, ok := keys[s.Field]
+	if !ok {
+		return "", "", "", fmt.Errorf("%w: cannot sort by %s", ErrInvalidInput, s.Field)
+	}
+	desc := key.desc
+	if s.Desc != nil {
+		desc = *s.Desc
+	}
+	dir, cmp := "", ">="
+	if desc {
+		dir, cmp = " DESC", "<="
+	}
+	expr := fmt.Sprintf(key.expr, alias)
+	orderBy = fmt.Sprintf("%s%s, %s.id%s", expr, dir, alias, dir)
+	value = fmt.Sprintf("(%s)::text", expr)
+	cursorCond = fmt.Sprintf("(%s, %s.id) %s ($%d::text::%s, $%d)",
+		expr, alias, cmp, cursorArg, key.typ, cursorArg+1)
+	return orderBy, value, cursorCond, nil
+}
+
+// encodeCursor returns the opaque page token for a row of a keyset
+// listing, from its sort value and ID.
+func encodeCursor(value, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(id + "|" + value))
+}
+
+// decodeCursor returns the sort value and row ID held by a page token from
+// encodeCursor. An empty token has neither.
+func decodeCursor(token string) (value *string, id *uuid.UUID, err error) {
+	if token == "" {
+		return nil, nil, nil
+	}
+	v, idStr, err := splitCursor(token)
+	if err != nil {
+		return nil, nil, err
+	}
+	parsed, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid page token", ErrInvalidInput)
+	}
+	return &v, &parsed, nil
+}
+
+// splitCursor returns the sort value and row ID of a page token as text,
+// for listings whose IDs are not UUIDs.
+func splitCursor(token string) (value, id string, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", "", fmt.Errorf("%w: invalid page token", ErrInvalidInput)
+	}
+	id, value, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return "", "", fmt.Errorf("%w: invalid page token", ErrInvalidInput)
+	}
+	return value, id, nil
+}
+
+// cursorRow scans a row with a scanner for all but its last column, which
+// goes to value: the sort value of a keyset listing, for instance.
+type cursorRow struct {
+	pgx.Row
+	value *string
+}
+
+func (r cursorRow) Scan(dest ...any) error {
+	return r.Row.Scan(append(dest, r.value)...)
+}
@@ -2,12 +2,19 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/rank"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/taskquery"
 )
 
 type TaskRepo struct {
@@ -18,44 +25,280 @@ func NewTaskRepo(pool *pgxpool.Pool) *TaskRepo {
 	return &TaskRepo{pool: pool}
 }
 
+// taskColumns is the select list shared by every query that returns tasks.
+// The tasks table must be aliased as t. Keep in sync with scanTask.
+const taskColumns = `t.id, t.workspace_id, t.project_id, t.parent_task_id, t.title, t.description,
+	t.status, t.priority, COALESCE(t.assigned_to, ''), t.due_date, t.metadata, t.rank, t.version,
+	t.original_estimate_seconds, t.remaining_estimate_seconds,
+	t.created_at, t.updated_at, t.deleted_at,
+	(SELECT COUNT(*)::int FROM tasks c
+	 WHERE c.parent_task_id = t.id AND c.deleted_at IS NULL),
+	(SELECT COUNT(*)::int FROM tasks c
+	 JOIN project_statuses ps ON ps.project_id = c.project_id AND ps.name = c.status
+	 WHERE c.parent_task_id = t.id AND c.deleted_at IS NULL AND ps.is_done),
+	ARRAY(SELECT tl.label_id FROM task_labels tl
+	      WHERE tl.task_id = t.id ORDER BY tl.created_at, tl.label_id),
+	ARRAY(SELECT ta.user_id FROM task_assignees ta
+	      WHERE ta.task_id = t.id ORDER BY ta.position)`
+
+func scanTask(row pgx.Row, t *Task) error {
+	return row.Scan(&t.ID, &t.WorkspaceID, &t.ProjectID, &t.ParentTaskID, &t.Title, &t.Description,
+		&t.Status, &t.Priority, &t.AssignedTo, &t.DueDate, &t.Metadata, &t.Rank, &t.Version,
+		&t.OriginalEstimate, &t.RemainingEstimate,
+		&t.CreatedAt, &t.UpdatedAt, &t.DeletedAt,
+		&t.SubtaskCount, &t.DoneSubtaskCount, &t.LabelIDs, &t.Assignees)
+}
+
 func (r *TaskRepo) Create(ctx context.Context, params CreateTaskParams) (*Task, error) {
 	var t Task
-	var assignedTo *string
-	if params.AssignedTo != "" {
-		assignedTo = &params.AssignedTo
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		return insertTask(ctx, tx, params, &t)
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrFailedPrecondition) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("create task: %w", err)
 	}
+	return &t, nil
+}
+
+// CreateWithSubtasks creates a task and, under it, each of subtasks in
+// order, in a single transaction. The subtasks' workspace, project and
+// parent are taken from the new task.
+func (r *TaskRepo) CreateWithSubtasks(ctx context.Context, params CreateTaskParams, subtasks []CreateTaskParams) (*Task, []Task, error) {
+	var t Task
+	children := make([]Task, len(subtasks))
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		if err := insertTask(ctx, tx, params, &t); err != nil {
+			return err
+		}
+		for i, sub := range subtasks {
+			sub.WorkspaceID, sub.ProjectID, sub.ParentTaskID = t.WorkspaceID, t.ProjectID, &t.ID
+			if err := insertTask(ctx, tx, sub, &children[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrFailedPrecondition) {
+			return nil, nil, err
+		}
+		return nil, nil, fmt.Errorf("create task: %w", err)
+	}
+	t.SubtaskCount = int32(len(children))
+	return &t, children, nil
+}
+
+// insertTask adds a task at the end of its project's first status column
+// and scans it into t.
+func insertTask(ctx context.Context, tx pgx.Tx, params CreateTaskParams, t *Task) error {
+	assignedTo := firstAssignee(params.Assignees)
 	metadata := params.Metadata
 	if metadata == nil {
 		metadata = []byte("{}")
 	}
 
-	err := r.pool.QueryRow(ctx,
-		`INSERT INTO tasks (id, workspace_id, project_id, title, description, status, priority, assigned_to, due_date, metadata, created_at, updated_at)
-		 VALUES (gen_random_uuid(), $1, $2, $3, $4,
-		         COALESCE(NULLIF($5, ''), 'todo'),
+	if err := lockProject(ctx, tx, params.WorkspaceID, params.ProjectID); err != nil {
+		return err
+	}
+	if params.ParentTaskID != nil {
+		if err := lockParent(ctx, tx, "tasks", *params.ParentTaskID); err != nil {
+			return err
+		}
+	}
+	status, err := initialStatus(ctx, tx, params.ProjectID)
+	if err != nil {
+		return err
+	}
+	rk, err := lastRank(ctx, tx, params.ProjectID, status)
+	if err != nil {
+		return err
+	}
+	err = scanTask(tx.QueryRow(ctx,
+		`INSERT INTO tasks AS t (id, workspace_id, project_id, parent_task_id, title, description, status, priority, assigned_to, due_date, metadata, rank,
+		                         original_estimate_seconds, remaining_estimate_seconds, created_at, updated_at)
+		 VALUES (gen_random_uuid(), $1, $2, $10, $3, $4, $5,
 		         COALESCE(NULLIF($6, ''), 'medium'),
-		         $7, $8, $9, NOW(), NOW())
-		 RETURNING id, workspace_id, project_id, title, description, status, priority,
-		           COALESCE(assigned_to, ''), due_date, metadata, created_at, updated_at, deleted_at`,
+		         $7, $8, $9, $11, $12, COALESCE($13, $12), NOW(), NOW())
+		 RETURNING `+taskColumns,
 		params.WorkspaceID, params.ProjectID, params.Title, params.Description,
-		"", params.Priority, assignedTo, params.DueDate, metadata,
-	).Scan(&t.ID, &t.WorkspaceID, &t.ProjectID, &t.Title, &t.Description, &t.Status, &t.Priority,
-		&t.AssignedTo, &t.DueDate, &t.Metadata, &t.CreatedAt, &t.UpdatedAt, &t.DeletedAt)
+		status, params.Priority, assignedTo, params.DueDate, metadata, params.ParentTaskID, rk,
+		params.OriginalEstimate, params.RemainingEstimate,
+	), t)
 	if err != nil {
-		return nil, fmt.Errorf("create task: %w", err)
+		return err
 	}
-	return &t, nil
+	// RETURNING ran before the assignees existed.
+	t.Assignees = params.Assignees
+	if err := setAssignees(ctx, tx, t.ID, params.Assignees); err != nil {
+		return err
+	}
+	return recordNewAssignees(ctx, tx, t.ID, params.Assignees)
+}
+
+// firstAssignee returns the value of tasks.assigned_to for a list of
+// assignees: the first one, or NULL.
+func firstAssignee(assignees []string) *string {
+	if len(assignees) == 0 {
+		return nil
+	}
+	return &assignees[0]
+}
+
+// setAssignees replaces the assignees of a live task, keeping their order.
+// Assignees already on the task keep their created_at.
+func setAssignees(ctx context.Context, db DBTX, taskID uuid.UUID, assignees []string) error {
+	if assignees == nil {
+		assignees = []string{}
+	}
+	if _, err := db.Exec(ctx,
+		`DELETE FROM task_assignees WHERE task_id = $1 AND user_id <> ALL($2)`,
+		taskID, assignees); err != nil {
+		return fmt.Errorf("remove task assignees: %w", err)
+	}
+	if _, err := db.Exec(ctx,
+		`INSERT INTO task_assignees (task_id, user_id, position, created_at)
+		 SELECT $1, a.user_id, a.n - 1, NOW()
+		 FROM unnest($2::varchar[]) WITH ORDINALITY AS a(user_id, n)
+		 WHERE EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)
+		 ON CONFLICT (task_id, user_id) DO UPDATE SET position = EXCLUDED.position`,
+		taskID, assignees); err != nil {
+		return fmt.Errorf("add task assignees: %w", err)
+	}
+	return nil
+}
+
+// recordNewAssignees adds the assignee list of a task created in this
+// transaction to its create history entry; the history trigger on tasks only
+// sees assigned_to.
+func recordNewAssignees(ctx context.Context, db DBTX, taskID uuid.UUID, assignees []string) error {
+	if len(assignees) == 0 {
+		return nil
+	}
+	_, err := db.Exec(ctx,
+		`UPDATE task_history
+		 SET changes = changes || jsonb_build_object('assignees',
+		         jsonb_build_object('old', NULL::jsonb, 'new', to_jsonb($2::varchar[])))
+		 WHERE task_id = $1 AND operation = 'create'`,
+		taskID, assignees)
+	if err != nil {
+		return fmt.Errorf("record task assignees: %w", err)
+	}
+	return nil
+}
+
+// recordAssignees adds an update entry to a live task's history when its
+// assignee list changes. The list lives in task_assignees, out of sight of
+// the history trigger on tasks.
+func recordAssignees(ctx context.Context, db DBTX, taskID uuid.UUID, before, after []string) error {
+	if slices.Equal(before, after) {
+		return nil
+	}
+	if before == nil {
+		before = []string{}
+	}
+	if after == nil {
+		after = []string{}
+	}
+	_, err := db.Exec(ctx,
+		`INSERT INTO task_history (task_id, operation, actor_id, changes)
+		 SELECT $1, 'update', NULLIF(current_setting('app.actor_id', true), ''),
+		        jsonb_build_object('assignees',
+		            jsonb_build_object('old', to_jsonb($2::varchar[]), 'new', to_jsonb($3::varchar[])))
+		 WHERE EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)`,
+		taskID, before, after)
+	if err != nil {
+		return fmt.Errorf("record task assignees: %w", err)
+	}
+	return nil
+}
+
+// initialStatus returns the first status of a project's workflow, which new
+// tasks start in, and locks that column (see lockColumn).
+func initialStatus(ctx context.Context, db DBTX, projectID uuid.UUID) (string, error) {
+	var status string
+	err := db.QueryRow(ctx,
+		`SELECT name FROM project_statuses WHERE project_id = $1
+		 ORDER BY position LIMIT 1 FOR UPDATE`,
+		projectID,
+	).Scan(&status)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("get initial status: %w", err)
+	}
+	return status, nil
+}
+
+// lockProject checks that tasks may be created in a project: it must belong
+// to the workspace, and neither it nor the workspace may be deleted, nor the
+// project archived. Both rows stay share-locked, workspace first, which is
+// the order deletes cascade in.
+func lockProject(ctx context.Context, db DBTX, workspaceID, projectID uuid.UUID) error {
+	if err := lockParent(ctx, db, "workspaces", workspaceID); err != nil {
+		return err
+	}
+	var status string
+	var deleted bool
+	err := db.QueryRow(ctx,
+		`SELECT status, deleted_at IS NOT NULL FROM projects
+		 WHERE id = $1 AND workspace_id = $2 FOR SHARE`,
+		projectID, workspaceID,
+	).Scan(&status, &deleted)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("project: %w", ErrNotFound)
+		}
+		return fmt.Errorf("lock project: %w", err)
+	}
+	if deleted {
+		return fmt.Errorf("%w: project is deleted", ErrFailedPrecondition)
+	}
+	if status == "archived" {
+		return fmt.Errorf("%w: project is archived", ErrFailedPrecondition)
+	}
+	return nil
+}
+
+// lockColumn locks a project's status column until the transaction ends, so
+// tasks entering it are ranked one at a time and never share a rank.
+func lockColumn(ctx context.Context, db DBTX, projectID uuid.UUID, status string) error {
+	tag, err := db.Exec(ctx,
+		`SELECT 1 FROM project_statuses WHERE project_id = $1 AND name = $2 FOR UPDATE`,
+		projectID, status)
+	if err != nil {
+		return fmt.Errorf("lock status column: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("%w: invalid status: %s", ErrInvalidInput, status)
+	}
+	return nil
+}
+
+// lastRank returns a rank after every task in a status column. Deleted tasks
+// count too, so restoring one never collides with a newer rank.
+func lastRank(ctx context.Context, db DBTX, projectID uuid.UUID, status string) (string, error) {
+	var last string
+	err := db.QueryRow(ctx,
+		`SELECT COALESCE(MAX(rank), '') FROM tasks WHERE project_id = $1 AND status = $2`,
+		projectID, status,
+	).Scan(&last)
+	if err != nil {
+		return "", fmt.Errorf("get last rank: %w", err)
+	}
+	return rank.After(last)
 }
 
 func (r *TaskRepo) GetByID(ctx context.Context, id uuid.UUID) (*Task, error) {
 	var t Task
-	err := r.pool.QueryRow(ctx,
-		`SELECT id, workspace_id, project_id, title, description, status, priority,
-		        COALESCE(assigned_to, ''), due_date, metadata, created_at, updated_at, deleted_at
-		 FROM tasks WHERE id = $1 AND deleted_at IS NULL`,
+	err := scanTask(r.pool.QueryRow(ctx,
+		`SELECT `+taskColumns+`
+		 FROM tasks t WHERE t.id = $1 AND t.deleted_at IS NULL`,
 		id,
-	).Scan(&t.ID, &t.WorkspaceID, &t.ProjectID, &t.Title, &t.Description, &t.Status, &t.Priority,
-		&t.AssignedTo, &t.DueDate, &t.Metadata, &t.CreatedAt, &t.UpdatedAt, &t.DeletedAt)
+	), &t)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrNotFound
@@ -71,38 +314,15 @@ func (r *TaskRepo) List(ctx context.Context, params ListTasksParams) (*TaskList,
 		pageSize = 20
 	}
 
-	// Build dynamic WHERE clause
-	conditions := []string{"workspace_id = $1", "deleted_at IS NULL"}
-	args := []any{params.WorkspaceID}
-	argIdx := 2
-
-	if params.ProjectID != uuid.Nil {
-		conditions = append(conditions, fmt.Sprintf("project_id = $%d", argIdx))
-		args = append(args, params.ProjectID)
-		argIdx++
-	}
-	if params.Status != "" {
-		conditions = append(conditions, fmt.Sprintf("status = $%d", argIdx))
-		args = append(args, params.Status)
-		argIdx++
-	}
-	if params.Priority != "" {
-		conditions = append(conditions, fmt.Sprintf("priority = $%d", argIdx))
-		args = append(args, params.Priority)
-		argIdx++
-	}
-	if params.AssignedTo != "" {
-		conditions = append(conditions, fmt.Sprintf("assigned_to = $%d", argIdx))
-		args = append(args, params.AssignedTo)
-		argIdx++
-	}
+	conditions, args := taskFilter(params)
+	argIdx := len(args) + 1
 
 	whereClause := strings.Join(conditions, " AND ")
 
 	// Count query
 	var totalCount int32
 	err := r.pool.QueryRow(ctx,
-		fmt.Sprintf("SELECT COUNT(*)::int FROM tasks WHERE %s", whereClause),
+		fmt.Sprintf("SELECT COUNT(*)::int FROM tasks t WHERE %s", whereClause),
 		args...,
 	).Scan(&totalCount)
 	if err != nil {
@@ -110,14 +330,22 @@ func (r *TaskRepo) List(ctx context.Context, params ListTasksParams) (*TaskList,
 	}
 
 	// Add cursor pagination
-	if params.PageToken != "" {
-		cursorID, parseErr := uuid.Parse(params.PageToken)
-		if parseErr != nil {
-			return nil, fmt.Errorf("invalid page token: %w", parseErr)
-		}
-		conditions = append(conditions, fmt.Sprintf("id < $%d", argIdx))
-		args = append(args, cursorID)
-		argIdx++
+	sort := params.Sort
+	if params.OrderByRank {
+		sort = Sort{Field: "rank"}
+	}
+	orderBy, sortValue, cursorCond, err := keyset(taskSortKeys, sort, "t", argIdx)
+	if err != nil {
+		return nil, err
+	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	if cursorID != nil {
+		conditions = append(conditions, cursorCond)
+		args = append(args, *cursorValue, *cursorID)
+		argIdx += 2
 		whereClause = strings.Join(conditions, " AND ")
 	}
 
@@ -125,11 +353,10 @@ func (r *TaskRepo) List(ctx context.Context, params ListTasksParams) (*TaskList,
 	args = append(args, pageSize+1)
 
 	query := fmt.Sprintf(
-		`SELECT id, workspace_id, project_id, title, description, status, priority,
-		        COALESCE(assigned_to, ''), due_date, metadata, created_at, updated_at, deleted_at
-		 FROM tasks WHERE %s
-		 ORDER BY created_at DESC, id DESC LIMIT $%d`,
-		whereClause, argIdx,
+		`SELECT %s, %s
+		 FROM tasks t WHERE %s
+		 ORDER BY %s LIMIT $%d`,
+		taskColumns, sortValue, whereClause, orderBy, argIdx,
 	)
 
 	rows, err := r.pool.Query(ctx, query, args...)
@@ -139,19 +366,21 @@ func (r *TaskRepo) List(ctx context.Context, params ListTasksParams) (*TaskList,
 	defer rows.Close()
 
 	var tasks []Task
+	var value string
 	for rows.Next() {
 		var t Task
-		if err := rows.Scan(&t.ID, &t.WorkspaceID, &t.ProjectID, &t.Title, &t.Description,
-			&t.Status, &t.Priority, &t.AssignedTo, &t.DueDate, &t.Metadata,
-			&t.CreatedAt, &t.UpdatedAt, &t.DeletedAt); err != nil {
+		if err := scanTask(cursorRow{rows, &value}, &t); err != nil {
 			return nil, fmt.Errorf("scan task: %w", err)
 		}
 		tasks = append(tasks, t)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list tasks: %w", err)
+	}
 
 	var nextPageToken string
 	if len(tasks) > int(pageSize) {
-		nextPageToken = tasks[pageSize].ID.String()
+		nextPageToken = encodeCursor(value, tasks[pageSize].ID.String())
 		tasks = tasks[:pageSize]
 	}
 
@@ -162,45 +391,1074 @@ func (r *TaskRepo) List(ctx context.Context, params ListTasksParams) (*TaskList,
 	}, nil
 }
 
+// taskSortKeys are the orders List supports. Tasks without a due date sort
+// as due last; statuses sort in their project's workflow order.
+var taskSortKeys = map[string]sortKey{
+	"":           {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"created_at": {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"updated_at": {expr: "%[1]s.updated_at", typ: "timestamptz", desc: true},
+	"due_date":   {expr: "COALESCE(%[1]s.due_date, 'infinity'::date)", typ: "date"},
+	"priority":   {expr: "array_position(ARRAY['low', 'medium', 'high', 'critical']::varchar[], %[1]s.priority)", typ: "int", desc: true},
+	"title":      {expr: "%[1]s.title", typ: "text"},
+	"status":     {expr: "(SELECT ps.position FROM project_statuses ps WHERE ps.project_id = %[1]s.project_id AND ps.name = %[1]s.status)", typ: "int"},
+	"rank":       {expr: "%[1]s.rank", typ: "text"},
+}
+
+// taskFilter builds the WHERE conditions (over tasks t) and arguments for
+// the filters of params, ignoring pagination and order.
+func taskFilter(params ListTasksParams) ([]string, []any) {
+	conditions := []string{"t.workspace_id = $1", "t.deleted_at IS NULL"}
+	args := []any{params.WorkspaceID}
+	argIdx := 2
+
+	if params.ProjectID != uuid.Nil {
+		conditions = append(conditions, fmt.Sprintf("t.project_id = $%d", argIdx))
+		args = append(args, params.ProjectID)
+		argIdx++
+	}
+	if params.ParentTaskID != uuid.Nil {
+		conditions = append(conditions, fmt.Sprintf("t.parent_task_id = $%d", argIdx))
+		args = append(args, params.ParentTaskID)
+		argIdx++
+	}
+	if len(params.LabelIDsAny) > 0 {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM task_labels tl WHERE tl.task_id = t.id AND tl.label_id = ANY($%d))", argIdx))
+		args = append(args, params.LabelIDsAny)
+		argIdx++
+	}
+	if len(params.LabelIDsAll) > 0 {
+		// Callers pass distinct IDs, so matching every one means the number of
+		// matching assignments equals the number of requested labels.
+		conditions = append(conditions, fmt.Sprintf(
+			"(SELECT COUNT(*) FROM task_labels tl WHERE tl.task_id = t.id AND tl.label_id = ANY($%d)) = cardinality($%d::uuid[])",
+			argIdx, argIdx))
+		args = append(args, params.LabelIDsAll)
+		argIdx++
+	}
+	if params.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("t.status = $%d", argIdx))
+		args = append(args, params.Status)
+		argIdx++
+	}
+	if params.Priority != "" {
+		conditions = append(conditions, fmt.Sprintf("t.priority = $%d", argIdx))
+		args = append(args, params.Priority)
+		argIdx++
+	}
+	if params.AssignedTo != "" {
+		conditions = append(conditions, fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = t.id AND ta.user_id = $%d)", argIdx))
+		args = append(args, params.AssignedTo)
+		argIdx++
+	}
+	if params.Due.From != nil {
+		conditions = append(conditions, fmt.Sprintf("t.due_date >= ($%d::timestamptz AT TIME ZONE 'UTC')::date", argIdx))
+		args = append(args, *params.Due.From)
+		argIdx++
+	}
+	if params.Due.To != nil {
+		conditions = append(conditions, fmt.Sprintf("t.due_date < ($%d::timestamptz AT TIME ZONE 'UTC')::date", argIdx))
+		args = append(args, *params.Due.To)
+		argIdx++
+	}
+	if params.Created.From != nil {
+		conditions = append(conditions, fmt.Sprintf("t.created_at >= $%d", argIdx))
+		args = append(args, *params.Created.From)
+		argIdx++
+	}
+	if params.Created.To != nil {
+		conditions = append(conditions, fmt.Sprintf("t.created_at < $%d", argIdx))
+		args = append(args, *params.Created.To)
+		argIdx++
+	}
+	if params.Updated.From != nil {
+		conditions = append(conditions, fmt.Sprintf("t.updated_at >= $%d", argIdx))
+		args = append(args, *params.Updated.From)
+		argIdx++
+	}
+	if params.Updated.To != nil {
+		conditions = append(conditions, fmt.Sprintf("t.updated_at < $%d", argIdx))
+		args = append(args, *params.Updated.To)
+		argIdx++
+	}
+	if params.Overdue {
+		// Matches the reminder job: a task is overdue once its due day has
+		// ended in UTC.
+		conditions = append(conditions,
+			`t.due_date < (NOW() AT TIME ZONE 'UTC')::date`,
+			`EXISTS (SELECT 1 FROM project_statuses ps WHERE ps.project_id = t.project_id AND ps.name = t.status AND NOT ps.is_done)`)
+	}
+	if params.NoDueDate {
+		conditions = append(conditions, "t.due_date IS NULL")
+	}
+	for _, f := range params.Metadata {
+		var cond string
+		cond, args = metadataCondition(f, args)
+		conditions = append(conditions, cond)
+	}
+	if params.Query != nil {
+		for _, term := range params.Query.Terms {
+			var cond string
+			cond, args = queryCondition(term, args)
+			conditions = append(conditions, cond)
+		}
+	}
+	return conditions, args
+}
+
+// queryColumns maps the fields of the task query language to columns.
+// Labels and assignees live in their own tables.
+var queryColumns = map[string]string{
+	"status":   "t.status",
+	"priority": "t.priority",
+	"due":      "t.due_date",
+	"created":  "t.created_at",
+	"updated":  "t.updated_at",
+	"title":    "t.title",
+}
+
+// queryCondition compiles one term of a parsed task query to a condition
+// (over tasks t), appending its arguments to args. A negated term also
+// matches tasks where the field is unset.
+func queryCondition(term taskquery.Term, args []any) (string, []any) {
+	col := queryColumns[term.Field]
+	var cond string
+	switch {
+	case term.Values[0].None:
+		switch term.Field {
+		case "label":
+			cond = "NOT EXISTS (SELECT 1 FROM task_labels tl WHERE tl.task_id = t.id)"
+		case "assignee":
+			cond = "NOT EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = t.id)"
+		default:
+			cond = col + " IS NULL"
+		}
+	case term.Kind == taskquery.KindContains:
+		args = append(args, term.Values[0].Text)
+		cond = fmt.Sprintf("strpos(lower(%s), $%d) > 0", col, len(args))
+	case term.Kind == taskquery.KindPriority:
+		// Priorities are few, so comparisons become a set of allowed values.
+		var allowed []string
+		for _, v := range term.Values {
+			i := slices.Index(taskquery.Priorities, v.Text)
+			switch term.Op {
+			case taskquery.OpEq:
+				allowed = append(allowed, v.Text)
+			case taskquery.OpLt:
+				allowed = taskquery.Priorities[:i]
+			case taskquery.OpLe:
+				allowed = taskquery.Priorities[:i+1]
+			case taskquery.OpGt:
+				allowed = taskquery.Priorities[i+1:]
+			case taskquery.OpGe:
+				allowed = taskquery.Priorities[i:]
+			}
+		}
+		args = append(args, allowed)
+		cond = fmt.Sprintf("%s = ANY($%d)", col, len(args))
+	case term.Kind == taskquery.KindDate && term.Field == "due":
+		dates := make([]time.Time, len(term.Values))
+		for i, v := range term.Values {
+			dates[i] = v.Date
+		}
+		if term.Op == taskquery.OpEq {
+			args = append(args, dates)
+			cond = fmt.Sprintf("%s = ANY($%d::date[])", col, len(args))
+		} else {
+			args = append(args, dates[0])
+			cond = fmt.Sprintf("%s %s $%d::date", col, term.Op, len(args))
+		}
+	case term.Kind == taskquery.KindDate:
+		// Timestamps compare against whole UTC days.
+		var conds []string
+		for _, v := range term.Values {
+			day, next := v.Date, v.Date.AddDate(0, 0, 1)
+			switch term.Op {
+			case taskquery.OpEq:
+				args = append(args, day, next)
+				conds = append(conds, fmt.Sprintf("(%s >= $%d AND %s < $%d)", col, len(args)-1, col, len(args)))
+			case taskquery.OpLt:
+				args = append(args, day)
+				conds = append(conds, fmt.Sprintf("%s < $%d", col, len(args)))
+			case taskquery.OpLe:
+				args = append(args, next)
+				conds = append(conds, fmt.Sprintf("%s < $%d", col, len(args)))
+			case taskquery.OpGt:
+				args = append(args, next)
+				conds = append(conds, fmt.Sprintf("%s >= $%d", col, len(args)))
+			case taskquery.OpGe:
+				args = append(args, day)
+				conds = append(conds, fmt.Sprintf("%s >= $%d", col, len(args)))
+			}
+		}
+		cond = "(" + strings.Join(conds, " OR ") + ")"
+	case term.Field == "label":
+		names := make([]string, len(term.Values))
+		for i, v := range term.Values {
+			names[i] = strings.ToLower(v.Text)
+		}
+		args = append(args, names)
+		cond = fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM task_labels tl JOIN labels l ON l.id = tl.label_id WHERE tl.task_id = t.id AND lower(l.name) = ANY($%d))",
+			len(args))
+	case term.Field == "assignee":
+		users := make([]string, len(term.Values))
+		for i, v := range term.Values {
+			users[i] = v.Text
+		}
+		args = append(args, users)
+		cond = fmt.Sprintf(
+			"EXISTS (SELECT 1 FROM task_assignees ta WHERE ta.task_id = t.id AND ta.user_id = ANY($%d))",
+			len(args))
+	default:
+		values := make([]string, len(term.Values))
+		for i, v := range term.Values {
+			values[i] = v.Text
+		}
+		args = append(args, values)
+		cond = fmt.Sprintf("%s = ANY($%d)", col, len(args))
+	}
+	if term.Negate {
+		cond = "NOT COALESCE(" + cond + ", false)"
+	}
+	return cond, args
+}
+
+// metadataCondition returns the condition (over tasks t) for one metadata
+// filter, appending its arguments to args. Each condition, and each arm of
+// an OR, includes a key or containment test, so it can use
+// idx_tasks_metadata. The index cannot match substrings, though: a string
+// "contains" rechecks every task that has the key.
+func metadataCondition(f MetadataFilter, args []any) (string, []any) {
+	args = append(args, f.Key)
+	k := len(args)
+	switch f.Op {
+	case "equals":
+		// Containment alone would also match arrays and objects that merely
+		// include the value.
+		args = append(args, f.Value)
+		v := len(args)
+		return fmt.Sprintf("(t.metadata @> jsonb_build_object($%[1]d::text, $%[2]d::jsonb) AND t.metadata -> $%[1]d::text = $%[2]d::jsonb)", k, v), args
+	case "contains":
+		args = append(args, f.Value)
+		cond := fmt.Sprintf("t.metadata @> jsonb_build_object($%d::text, jsonb_build_array($%d::jsonb))", k, len(args))
+		var substr string
+		if json.Unmarshal(f.Value, &substr) == nil {
+			// The key test lets the substring arm use the index too;
+			// without it the OR would scan every task.
+			args = append(args, substr)
+			cond = fmt.Sprintf("(%s OR (t.metadata ? $%[2]d::text AND jsonb_typeof(t.metadata -> $%[2]d::text) = 'string' AND strpos(t.metadata ->> $%[2]d::text, $%[3]d) > 0))", cond, k, len(args))
+		}
+		return cond, args
+	case "exists":
+		if f.Exists {
+			return fmt.Sprintf("t.metadata ? $%d::text", k), args
+		}
+		return fmt.Sprintf("NOT COALESCE(t.metadata ? $%d::text, false)", k), args
+	default: // range
+		number := fmt.Sprintf("CASE WHEN jsonb_typeof(t.metadata -> $%[1]d::text) = 'number' THEN (t.metadata ->> $%[1]d::text)::numeric END", k)
+		conds := []string{fmt.Sprintf("t.metadata ? $%d::text", k)}
+		if f.Min != nil {
+			args = append(args, *f.Min)
+			conds = append(conds, fmt.Sprintf("%s >= $%d", number, len(args)))
+		}
+		if f.Max != nil {
+			args = append(args, *f.Max)
+			conds = append(conds, fmt.Sprintf("%s <= $%d", number, len(args)))
+		}
+		return "(" + strings.Join(conds, " AND ") + ")", args
+	}
+}
+
+// Board returns every status column of a project with its live task count
+// and the first pageSize tasks in rank order. Each column's NextPageToken
+// is a valid page token for List with OrderByRank on that column.
+func (r *TaskRepo) Board(ctx context.Context, projectID uuid.UUID, pageSize int32) (*Board, error) {
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT ps.name, ps.is_done, COUNT(t.id)::int
+		 FROM project_statuses ps
+		 JOIN projects p ON p.id = ps.project_id AND p.deleted_at IS NULL
+		 LEFT JOIN tasks t ON t.project_id = ps.project_id AND t.status = ps.name AND t.deleted_at IS NULL
+		 WHERE ps.project_id = $1
+		 GROUP BY ps.name, ps.is_done, ps.position
+		 ORDER BY ps.position`,
+		projectID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list board columns: %w", err)
+	}
+	defer rows.Close()
+
+	board := &Board{ProjectID: projectID}
+	index := make(map[string]int)
+	for rows.Next() {
+		var c BoardColumn
+		if err := rows.Scan(&c.Status, &c.IsDone, &c.TotalCount); err != nil {
+			return nil, fmt.Errorf("scan board column: %w", err)
+		}
+		index[c.Status] = len(board.Columns)
+		board.Columns = append(board.Columns, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list board columns: %w", err)
+	}
+	if len(board.Columns) == 0 {
+		return nil, ErrNotFound
+	}
+
+	// One extra task per column tells whether the column continues.
+	rows, err = r.pool.Query(ctx,
+		`SELECT `+taskColumns+`
+		 FROM (
+		     SELECT id, row_number() OVER (PARTITION BY status ORDER BY rank, id) AS n
+		     FROM tasks WHERE project_id = $1 AND deleted_at IS NULL
+		 ) page
+		 JOIN tasks t ON t.id = page.id
+		 WHERE page.n <= $2
+		 ORDER BY t.status, t.rank, t.id`,
+		projectID, pageSize+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list board tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		i, ok := index[t.Status]
+		if !ok {
+			continue
+		}
+		c := &board.Columns[i]
+		if len(c.Tasks) == int(pageSize) {
+			c.NextPageToken = encodeCursor(t.Rank, t.ID.String())
+			continue
+		}
+		c.Tasks = append(c.Tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list board tasks: %w", err)
+	}
+	return board, nil
+}
+
+// Update writes the fields named in params.UpdateMask, or all of them when
+// the mask is empty.
 func (r *TaskRepo) Update(ctx context.Context, params UpdateTaskParams) (*Task, error) {
 	var t Task
-	var assignedTo *string
-	if params.AssignedTo != "" {
-		assignedTo = &params.AssignedTo
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		return updateTask(ctx, tx, params, &t)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			if params.Version != 0 {
+				return nil, staleOrNotFound(ctx, r.pool, "tasks", params.ID)
+			}
+			return nil, ErrNotFound
+		}
+		if errors.Is(err, ErrInvalidInput) || errors.Is(err, ErrStaleVersion) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("update task: %w", err)
 	}
+	return &t, nil
+}
+
+// BulkUpdate applies the same update to every task picked by sel, up to
+// limit, in a single transaction. The tasks are locked before check runs on
+// each of them, so what check sees is what gets updated. Tasks check rejects
+// with ErrInvalidInput or ErrFailedPrecondition are reported in the result
+// and left unchanged; any other error rolls everything back. params.ID is
+// ignored.
+func (r *TaskRepo) BulkUpdate(ctx context.Context, sel TaskSelection, limit int, params UpdateTaskParams, check func(*Task) error) (*BulkResult, error) {
+	var result *BulkResult
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		tasks, err := selectTasks(ctx, tx, sel, limit)
+		if err != nil {
+			return err
+		}
+		result = newBulkResult(sel, tasks)
+		for i := range tasks {
+			t := &tasks[i]
+			if err := check(t); err != nil {
+				if !errors.Is(err, ErrInvalidInput) && !errors.Is(err, ErrFailedPrecondition) {
+					return fmt.Errorf("task %s: %w", t.ID, err)
+				}
+				result.fail(t.ID, err)
+				continue
+			}
+			params.ID = t.ID
+			params.FromStatus = t.Status
+			if err := updateTask(ctx, tx, params, t); err != nil {
+				return fmt.Errorf("task %s: %w", t.ID, err)
+			}
+			result.Succeeded++
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("bulk update tasks: %w", err)
+	}
+	return result, nil
+}
+
+func updateTask(ctx context.Context, tx pgx.Tx, params UpdateTaskParams, t *Task) error {
 	metadata := params.Metadata
 	if metadata == nil {
 		metadata = []byte("{}")
 	}
 
-	err := r.pool.QueryRow(ctx,
-		`UPDATE tasks SET title = $1, description = $2, status = $3, priority = $4,
-		        assigned_to = $5, due_date = $6, metadata = $7, updated_at = NOW()
-		 WHERE id = $8 AND deleted_at IS NULL
-		 RETURNING id, workspace_id, project_id, title, description, status, priority,
-		           COALESCE(assigned_to, ''), due_date, metadata, created_at, updated_at, deleted_at`,
-		params.Title, params.Description, params.Status, params.Priority,
-		assignedTo, params.DueDate, metadata, params.ID,
-	).Scan(&t.ID, &t.WorkspaceID, &t.ProjectID, &t.Title, &t.Description, &t.Status, &t.Priority,
-		&t.AssignedTo, &t.DueDate, &t.Metadata, &t.CreatedAt, &t.UpdatedAt, &t.DeletedAt)
+	b := newUpdateBuilder(params.UpdateMask)
+	b.set("title", "title", params.Title)
+	b.set("description", "description", params.Description)
+	b.set("status", "status", params.Status)
+	b.set("priority", "priority", params.Priority)
+	b.set("assignees", "assigned_to", firstAssignee(params.Assignees))
+	b.set("due_date", "due_date", params.DueDate)
+	b.set("metadata", "metadata", metadata)
+	b.set("original_estimate", "original_estimate_seconds", params.OriginalEstimate)
+	b.set("remaining_estimate", "remaining_estimate_seconds", params.RemainingEstimate)
+	if b.includes("assignees") {
+		// Written first so that RETURNING sees them.
+		var before []string
+		if err := tx.QueryRow(ctx,
+			`SELECT ARRAY(SELECT user_id FROM task_assignees WHERE task_id = $1 ORDER BY position)`,
+			params.ID).Scan(&before); err != nil {
+			return fmt.Errorf("get task assignees: %w", err)
+		}
+		if err := setAssignees(ctx, tx, params.ID, params.Assignees); err != nil {
+			return err
+		}
+		if err := recordAssignees(ctx, tx, params.ID, before, params.Assignees); err != nil {
+			return err
+		}
+	}
+	if b.includes("status") {
+		// A task moving to another column goes to the end of it.
+		var projectID uuid.UUID
+		var status string
+		if err := tx.QueryRow(ctx,
+			`SELECT project_id, status FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+			params.ID).Scan(&projectID, &status); err != nil {
+			return err
+		}
+		if err := matchStatus(params.FromStatus, status); err != nil {
+			return err
+		}
+		if err := lockColumn(ctx, tx, projectID, params.Status); err != nil {
+			return err
+		}
+		rk, err := lastRank(ctx, tx, projectID, params.Status)
+		if err != nil {
+			return err
+		}
+		b.sets = append(b.sets, fmt.Sprintf(
+			"rank = CASE WHEN t.status = %s THEN t.rank ELSE %s END", b.arg(params.Status), b.arg(rk)))
+	}
+	query := fmt.Sprintf(
+		`UPDATE tasks t SET %s
+		 WHERE t.id = %s AND t.deleted_at IS NULL%s
+		 RETURNING %s`,
+		b.clause(), b.arg(params.ID), b.matchVersion(params.Version), taskColumns,
+	)
+	return scanTask(tx.QueryRow(ctx, query, b.args...), t)
+}
+
+// matchStatus checks that a task locked for a status change is still in
+// the status its workflow transition was checked from; from is empty when
+// there was no check.
+func matchStatus(from, current string) error {
+	if from != "" && from != current {
+		return fmt.Errorf("%w: task status changed from %s to %s", ErrStaleVersion, from, current)
+	}
+	return nil
+}
+
+// Move places a task in a status column, before or after another task of
+// that column or at its end, changing its status if needed.
+func (r *TaskRepo) Move(ctx context.Context, params MoveTaskParams) (*Task, error) {
+	var t Task
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		var projectID uuid.UUID
+		var status string
+		var version int32
+		if err := tx.QueryRow(ctx,
+			`SELECT project_id, status, version FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+			params.ID).Scan(&projectID, &status, &version); err != nil {
+			return err
+		}
+		if params.Version != 0 && version != params.Version {
+			return ErrStaleVersion
+		}
+		if err := matchStatus(params.FromStatus, status); err != nil {
+			return err
+		}
+		if err := lockColumn(ctx, tx, projectID, params.Status); err != nil {
+			return err
+		}
+
+		var rk string
+		var err error
+		switch {
+		case params.BeforeID != nil, params.AfterID != nil:
+			var lo, hi string
+			query := `SELECT a.rank, COALESCE((SELECT MAX(n.rank) FROM tasks n
+			                  WHERE n.project_id = a.project_id AND n.status = a.status AND n.deleted_at IS NULL
+			                    AND n.id <> $4 AND n.rank < a.rank), '')
+			          FROM tasks a
+			          WHERE a.id = $1 AND a.project_id = $2 AND a.status = $3 AND a.deleted_at IS NULL`
+			anchor, dest := params.BeforeID, []any{&hi, &lo}
+			if params.AfterID != nil {
+				query = `SELECT a.rank, COALESCE((SELECT MIN(n.rank) FROM tasks n
+				                  WHERE n.project_id = a.project_id AND n.status = a.status AND n.deleted_at IS NULL
+				                    AND n.id <> $4 AND n.rank > a.rank), '')
+				          FROM tasks a
+				          WHERE a.id = $1 AND a.project_id = $2 AND a.status = $3 AND a.deleted_at IS NULL`
+				anchor, dest = params.AfterID, []any{&lo, &hi}
+			}
+			err = tx.QueryRow(ctx, query, *anchor, projectID, params.Status, params.ID).Scan(dest...)
+			if err == pgx.ErrNoRows {
+				return fmt.Errorf("%w: task %s is not in column %s", ErrInvalidInput, *anchor, params.Status)
+			}
+			if err != nil {
+				return err
+			}
+			rk, err = rank.Between(lo, hi)
+			if err != nil {
+				return fmt.Errorf("%w: %v", ErrConflict, err)
+			}
+		default:
+			rk, err = lastRank(ctx, tx, projectID, params.Status)
+			if err != nil {
+				return err
+			}
+		}
+
+		return scanTask(tx.QueryRow(ctx,
+			`UPDATE tasks t SET status = $2, rank = $3, updated_at = NOW()
+			 WHERE t.id = $1
+			 RETURNING `+taskColumns,
+			params.ID, params.Status, rk,
+		), &t)
+	})
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrNotFound
 		}
-		return nil, fmt.Errorf("update task: %w", err)
+		return nil, fmt.Errorf("move task: %w", err)
 	}
 	return &t, nil
 }
 
-func (r *TaskRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	tag, err := r.pool.Exec(ctx,
-		`UPDATE tasks SET deleted_at = NOW(), updated_at = NOW()
-		 WHERE id = $1 AND deleted_at IS NULL`, id)
+// Clone copies a live task into a new one at the end of the first status
+// column of params.ProjectID, or of the task's own project, with the same
+// labels and assignees and, if asked, its comments. The copy keeps the
+// parent task only when it stays in the same project. Watchers of either
+// task or project get a task.cloned event.
+func (r *TaskRepo) Clone(ctx context.Context, params CloneTaskParams) (*Task, error) {
+	var t Task
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		var src Task
+		if err := scanTask(tx.QueryRow(ctx,
+			`SELECT `+taskColumns+`
+			 FROM tasks t WHERE t.id = $1 AND t.deleted_at IS NULL FOR SHARE OF t`,
+			params.ID), &src); err != nil {
+			return err
+		}
+		projectID := params.ProjectID
+		if projectID == uuid.Nil {
+			projectID = src.ProjectID
+		}
+		create := CreateTaskParams{
+			WorkspaceID:       src.WorkspaceID,
+			ProjectID:         projectID,
+			Title:             src.Title,
+			Description:       src.Description,
+			Priority:          src.Priority,
+			Assignees:         src.Assignees,
+			DueDate:           src.DueDate,
+			Metadata:          src.Metadata,
+			OriginalEstimate:  src.OriginalEstimate,
+			RemainingEstimate: src.RemainingEstimate,
+		}
+		if projectID == src.ProjectID {
+			create.ParentTaskID = src.ParentTaskID
+		}
+		if err := insertTask(ctx, tx, create, &t); err != nil {
+			return err
+		}
+
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO task_labels (task_id, label_id, created_at)
+			 SELECT $2, label_id, created_at FROM task_labels WHERE task_id = $1`,
+			src.ID, t.ID); err != nil {
+			return fmt.Errorf("copy task labels: %w", err)
+		}
+		t.LabelIDs = src.LabelIDs
+		if params.IncludeComments {
+			// Copied comments are not news: the comment trigger neither
+			// subscribes their authors nor notifies while this is on.
+			if _, err := tx.Exec(ctx, `SELECT set_config('app.copying_comments', 'on', true)`); err != nil {
+				return fmt.Errorf("copy task comments: %w", err)
+			}
+			if _, err := tx.Exec(ctx,
+				`INSERT INTO task_comments (id, task_id, author_id, content, created_at, updated_at)
+				 SELECT gen_random_uuid(), $2, author_id, content, created_at, updated_at
+				 FROM task_comments WHERE task_id = $1
+				 ORDER BY created_at, id`,
+				src.ID, t.ID); err != nil {
+				return fmt.Errorf("copy task comments: %w", err)
+			}
+			if _, err := tx.Exec(ctx, `SELECT set_config('app.copying_comments', 'off', true)`); err != nil {
+				return fmt.Errorf("copy task comments: %w", err)
+			}
+		}
+		return queueTaskEvent(ctx, tx, t.WorkspaceID, "task.cloned", map[string]any{
+			"task_id":         t.ID,
+			"title":           t.Title,
+			"source_task_id":  src.ID,
+			"from_project_id": src.ProjectID,
+			"to_project_id":   t.ProjectID,
+		}, []uuid.UUID{src.ID, t.ID}, []uuid.UUID{src.ProjectID, t.ProjectID})
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrFailedPrecondition) || errors.Is(err, ErrInvalidInput) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("clone task: %w", err)
+	}
+	return &t, nil
+}
+
+// MoveToProject moves a live task to the end of a column of another project
+// in its workspace, detaching it from its parent task. Its subtasks move
+// along, deleted ones included, so that a restore never finds its parent in
+// another project. Each task keeps its status when the new project's
+// workflow has it, and otherwise starts over in the first status. check runs
+// on the locked subtree, root first, before anything moves; its error aborts
+// the move. Watchers of the task and of both projects get a task.moved
+// event.
+func (r *TaskRepo) MoveToProject(ctx context.Context, params MoveTaskToProjectParams, check func(subtree []Task) error) (*Task, error) {
+	var t Task
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		var workspaceID, projectID uuid.UUID
+		var version int32
+		if err := tx.QueryRow(ctx,
+			`SELECT workspace_id, project_id, version FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+			params.ID).Scan(&workspaceID, &projectID, &version); err != nil {
+			return err
+		}
+		if params.Version != 0 && version != params.Version {
+			return ErrStaleVersion
+		}
+		if projectID == params.ProjectID {
+			return fmt.Errorf("%w: task is already in project %s", ErrInvalidInput, projectID)
+		}
+		if err := lockProject(ctx, tx, workspaceID, params.ProjectID); err != nil {
+			return err
+		}
+		initial, err := initialStatus(ctx, tx, params.ProjectID)
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx,
+			`WITH RECURSIVE subtree(id, depth) AS (
+			     SELECT id, 1 FROM tasks WHERE id = $1
+			     UNION
+			     SELECT c.id, s.depth + 1 FROM tasks c
+			     JOIN subtree s ON c.parent_task_id = s.id
+			 )
+			 SELECT `+taskColumns+`, COALESCE(ps.name, $3)
+			 FROM subtree s
+			 JOIN tasks t ON t.id = s.id
+			 LEFT JOIN project_statuses ps ON ps.project_id = $2 AND ps.name = t.status
+			 ORDER BY s.depth, t.rank
+			 FOR UPDATE OF t`,
+			params.ID, params.ProjectID, initial)
+		if err != nil {
+			return fmt.Errorf("list subtasks: %w", err)
+		}
+		type placement struct {
+			id     uuid.UUID
+			status string
+		}
+		var subtree []Task
+		var moves []placement
+		for rows.Next() {
+			var st Task
+			var m placement
+			if err := scanTask(cursorRow{rows, &m.status}, &st); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan subtask: %w", err)
+			}
+			m.id = st.ID
+			subtree = append(subtree, st)
+			moves = append(moves, m)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("list subtasks: %w", err)
+		}
+		if err := check(subtree); err != nil {
+			return err
+		}
+
+		for _, m := range moves {
+			if err := lockColumn(ctx, tx, params.ProjectID, m.status); err != nil {
+				return err
+			}
+			rk, err := lastRank(ctx, tx, params.ProjectID, m.status)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.Exec(ctx,
+				`UPDATE tasks SET project_id = $2, status = $3, rank = $4,
+				     parent_task_id = CASE WHEN id = $5 THEN NULL ELSE parent_task_id END,
+				     updated_at = NOW()
+				 WHERE id = $1`,
+				m.id, params.ProjectID, m.status, rk, params.ID); err != nil {
+				return err
+			}
+		}
+		if err := scanTask(tx.QueryRow(ctx,
+			`SELECT `+taskColumns+` FROM tasks t WHERE t.id = $1`, params.ID), &t); err != nil {
+			return err
+		}
+		return queueTaskEvent(ctx, tx, workspaceID, "task.moved", map[string]any{
+			"task_id":         t.ID,
+			"title":           t.Title,
+			"from_project_id": projectID,
+			"to_project_id":   t.ProjectID,
+		}, []uuid.UUID{t.ID}, []uuid.UUID{projectID, t.ProjectID})
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		if errors.Is(err, ErrNotFound) || errors.Is(err, ErrFailedPrecondition) || errors.Is(err, ErrInvalidInput) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("move task to project: %w", err)
+	}
+	return &t, nil
+}
+
+// queueTaskEvent queues an event for each watcher of the given tasks and
+// projects, once per user and skipping the actor, like the history trigger
+// does for the changes it records.
+func queueTaskEvent(ctx context.Context, tx pgx.Tx, workspaceID uuid.UUID, eventType string, payload map[string]any, taskIDs, projectIDs []uuid.UUID) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal %s payload: %w", eventType, err)
+	}
+	if _, err := tx.Exec(ctx,
+		`INSERT INTO notification_queue (workspace_id, recipient_id, event_type, payload, status, created_at)
+		 SELECT $1, w.user_id, $2, $3, 'pending', NOW()
+		 FROM (
+		     SELECT user_id FROM task_watchers WHERE task_id = ANY($4)
+		     UNION
+		     SELECT user_id FROM project_watchers WHERE project_id = ANY($5)
+		 ) w
+		 WHERE w.user_id IS DISTINCT FROM NULLIF(current_setting('app.actor_id', true), '')`,
+		workspaceID, eventType, body, taskIDs, projectIDs); err != nil {
+		return fmt.Errorf("queue %s: %w", eventType, err)
+	}
+	return nil
+}
+
+// Delete soft-deletes a task together with all of its live subtasks. A
+// non-zero version must match the task's current one.
+func (r *TaskRepo) Delete(ctx context.Context, id uuid.UUID, version int32) error {
+	var deleted int64
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		var err error
+		deleted, err = deleteTask(ctx, tx, id, version)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("delete task: %w", err)
 	}
-	if tag.RowsAffected() == 0 {
+	if deleted == 0 {
+		if version != 0 {
+			return staleOrNotFound(ctx, r.pool, "tasks", id)
+		}
 		return ErrNotFound
 	}
 	return nil
 }
+
+// BulkDelete soft-deletes every task picked by sel, up to limit, with their
+// subtasks, in a single transaction. Tasks already deleted, e.g. as the
+// subtask of an earlier one, are skipped.
+func (r *TaskRepo) BulkDelete(ctx context.Context, sel TaskSelection, limit int) (*BulkResult, error) {
+	var result *BulkResult
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		tasks, err := selectTasks(ctx, tx, sel, limit)
+		if err != nil {
+			return err
+		}
+		result = newBulkResult(sel, tasks)
+		for _, t := range tasks {
+			if _, err := deleteTask(ctx, tx, t.ID, 0); err != nil {
+				return fmt.Errorf("task %s: %w", t.ID, err)
+			}
+			result.Succeeded++
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrInvalidInput) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("bulk delete tasks: %w", err)
+	}
+	return result, nil
+}
+
+// deleteTask soft-deletes a task and its live subtree, returning how many
+// rows it marked.
+func deleteTask(ctx context.Context, tx pgx.Tx, id uuid.UUID, version int32) (int64, error) {
+	tag, err := tx.Exec(ctx,
+		`WITH RECURSIVE subtree(id) AS (
+		     SELECT id FROM tasks
+		     WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)
+		     UNION
+		     SELECT c.id FROM tasks c
+		     JOIN subtree s ON c.parent_task_id = s.id
+		     WHERE c.deleted_at IS NULL
+		 )
+		 UPDATE tasks SET deleted_at = NOW(), updated_at = NOW()
+		 WHERE id IN (SELECT id FROM subtree)`, id, version)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// ListDeleted lists a workspace's soft-deleted tasks, most recently deleted
+// first.
+func (r *TaskRepo) ListDeleted(ctx context.Context, params ListDeletedTasksParams) (*TaskList, error) {
+	pageSize := params.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	var projectID *uuid.UUID
+	if params.ProjectID != uuid.Nil {
+		projectID = &params.ProjectID
+	}
+
+	var totalCount int32
+	err = r.pool.QueryRow(ctx,
+		`SELECT COUNT(*)::int FROM tasks t
+		 WHERE t.workspace_id = $1 AND t.deleted_at IS NOT NULL
+		   AND ($2::uuid IS NULL OR t.project_id = $2)`,
+		params.WorkspaceID, projectID,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("count deleted tasks: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx,
+		fmt.Sprintf(`SELECT %s FROM tasks t
+		 WHERE t.workspace_id = $1 AND t.deleted_at IS NOT NULL
+		   AND ($2::uuid IS NULL OR t.project_id = $2)
+		   AND ($4::uuid IS NULL OR (t.deleted_at, t.id) <= ($3::text::timestamptz, $4))
+		 ORDER BY t.deleted_at DESC, t.id DESC LIMIT $5`, taskColumns),
+		params.WorkspaceID, projectID, cursorValue, cursorID, pageSize+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list deleted tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		var t Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list deleted tasks: %w", err)
+	}
+
+	var nextPageToken string
+	if len(tasks) > int(pageSize) {
+		nextPageToken = trashCursor(tasks[pageSize].DeletedAt, tasks[pageSize].ID)
+		tasks = tasks[:pageSize]
+	}
+
+	return &TaskList{
+		Tasks:         tasks,
+		NextPageToken: nextPageToken,
+		TotalCount:    totalCount,
+	}, nil
+}
+
+// Restore undeletes a soft-deleted task together with the subtasks that
+// were deleted along with it (those with the same deleted_at). Its parent
+// task, project and workspace must be live; a task that is live or already
+// purged is reported as not found.
+func (r *TaskRepo) Restore(ctx context.Context, id uuid.UUID) (*Task, error) {
+	var t Task
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		var parentDeleted, projectDeleted, workspaceDeleted bool
+		err := tx.QueryRow(ctx,
+			`SELECT COALESCE(pt.deleted_at IS NOT NULL, false), p.deleted_at IS NOT NULL, w.deleted_at IS NOT NULL
+			 FROM tasks t
+			 JOIN projects p ON p.id = t.project_id
+			 JOIN workspaces w ON w.id = t.workspace_id
+			 LEFT JOIN tasks pt ON pt.id = t.parent_task_id
+			 WHERE t.id = $1 AND t.deleted_at IS NOT NULL
+			 FOR UPDATE OF t FOR SHARE OF p, w`, id,
+		).Scan(&parentDeleted, &projectDeleted, &workspaceDeleted)
+		if err != nil {
+			return err
+		}
+		switch {
+		case workspaceDeleted:
+			return fmt.Errorf("%w: the task's workspace is deleted; restore it first", ErrFailedPrecondition)
+		case projectDeleted:
+			return fmt.Errorf("%w: the task's project is deleted; restore it first", ErrFailedPrecondition)
+		case parentDeleted:
+			return fmt.Errorf("%w: the parent task is deleted; restore it first", ErrFailedPrecondition)
+		}
+
+		_, err = tx.Exec(ctx,
+			`WITH RECURSIVE subtree(id, deleted_at) AS (
+			     SELECT id, deleted_at FROM tasks WHERE id = $1
+			     UNION
+			     SELECT c.id, c.deleted_at FROM tasks c
+			     JOIN subtree s ON c.parent_task_id = s.id AND c.deleted_at = s.deleted_at
+			 )
+			 UPDATE tasks SET deleted_at = NULL, updated_at = NOW()
+			 WHERE id IN (SELECT id FROM subtree)`, id)
+		if err != nil {
+			return err
+		}
+		return scanTask(tx.QueryRow(ctx,
+			fmt.Sprintf(`SELECT %s FROM tasks t WHERE t.id = $1`, taskColumns), id), &t)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		if errors.Is(err, ErrFailedPrecondition) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("restore task: %w", err)
+	}
+	return &t, nil
+}
+
+// selectTasks locks the live tasks of a workspace picked by sel until the
+// transaction ends. It fails if more than limit tasks match.
+func selectTasks(ctx context.Context, tx pgx.Tx, sel TaskSelection, limit int) ([]Task, error) {
+	conditions := []string{"t.workspace_id = $1", "t.deleted_at IS NULL", "t.id = ANY($2)"}
+	args := []any{sel.WorkspaceID, sel.IDs}
+	if sel.Filter != nil {
+		filter := *sel.Filter
+		filter.WorkspaceID = sel.WorkspaceID
+		conditions, args = taskFilter(filter)
+	}
+	args = append(args, limit+1)
+
+	rows, err := tx.Query(ctx,
+		fmt.Sprintf(`SELECT %s FROM tasks t WHERE %s ORDER BY t.created_at DESC, t.id DESC LIMIT $%d FOR UPDATE OF t`,
+			taskColumns, strings.Join(conditions, " AND "), len(args)),
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("select tasks: %w", err)
+	}
+	defer rows.Close()
+
+	var tasks []Task
+	for rows.Next() {
+		var t Task
+		if err := scanTask(rows, &t); err != nil {
+			return nil, fmt.Errorf("scan task: %w", err)
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(tasks) > limit {
+		return nil, fmt.Errorf("%w: filter matches more than %d tasks", ErrInvalidInput, limit)
+	}
+	return tasks, nil
+}
+
+// newBulkResult starts the result of a bulk operation on tasks. Requested
+// IDs that match no live task of the workspace are recorded as failures.
+func newBulkResult(sel TaskSelection, tasks []Task) *BulkResult {
+	result := &BulkResult{Total: int32(len(tasks))}
+	if sel.Filter == nil {
+		result.Total = int32(len(sel.IDs))
+		found := make(map[uuid.UUID]bool, len(tasks))
+		for _, t := range tasks {
+			found[t.ID] = true
+		}
+		for _, id := range sel.IDs {
+			if !found[id] {
+				result.fail(id, ErrNotFound)
+			}
+		}
+	}
+	return result
+}
+
+// fail records that a task of a bulk operation was left unchanged.
+func (r *BulkResult) fail(id uuid.UUID, err error) {
+	e := BulkError{TaskID: id, Error: err.Error()}
+	var verr *ValidationError
+	if errors.As(err, &verr) {
+		e.Violations = verr.Violations
+	}
+	r.Failed++
+	r.Errors = append(r.Errors, e)
+}
+
+// Depth returns how many levels deep a task is nested; top-level tasks
+// have depth 1.
+func (r *TaskRepo) Depth(ctx context.Context, id uuid.UUID) (int, error) {
+	var depth int
+	err := r.pool.QueryRow(ctx,
+		`WITH RECURSIVE ancestors(id, parent_task_id, depth) AS (
+		     SELECT id, parent_task_id, 1 FROM tasks WHERE id = $1
+		     UNION ALL
+		     SELECT p.id, p.parent_task_id, a.depth + 1 FROM tasks p
+		     JOIN ancestors a ON p.id = a.parent_task_id
+		 )
+		 SELECT COALESCE(MAX(depth), 0) FROM ancestors`,
+		id,
+	).Scan(&depth)
+	if err != nil {
+		return 0, fmt.Errorf("get task depth: %w", err)
+	}
+	if depth == 0 {
+		return 0, ErrNotFound
+	}
+	return depth, nil
+}
@@ -5,33 +5,50 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/taskquery"
 )
 
 type Task struct {
-	ID          uuid.UUID
-	WorkspaceID uuid.UUID
-	ProjectID   uuid.UUID
-	Title       string
-	Description string
-	Status      string // todo, in_progress, review, done
-	Priority    string // low, medium, high, critical
-	AssignedTo  string
-	DueDate     *time.Time
-	Metadata    json.RawMessage
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   *time.Time
+	ID                uuid.UUID
+	WorkspaceID       uuid.UUID
+	ProjectID         uuid.UUID
+	ParentTaskID      *uuid.UUID
+	Title             string
+	Description       string
+	Status            string   // one of the project's workflow statuses
+	Priority          string   // low, medium, high, critical
+	AssignedTo        string   // first of Assignees, for older clients
+	Assignees         []string // user IDs, in the order they were given
+	DueDate           *time.Time
+	Metadata          json.RawMessage
+	Rank              string // position within the status column; sorts byte-wise
+	Version           int32  // bumped on every write
+	OriginalEstimate  *int64 // seconds; nil when not estimated
+	RemainingEstimate *int64 // seconds
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	DeletedAt         *time.Time
+	SubtaskCount      int32       // live direct subtasks
+	DoneSubtaskCount  int32       // live direct subtasks in a done status
+	BlockedBy         []uuid.UUID // only populated when fetching a single task
+	Blocking          []uuid.UUID // only populated when fetching a single task
+	LabelIDs          []uuid.UUID
 }
 
 type CreateTaskParams struct {
-	WorkspaceID uuid.UUID
-	ProjectID   uuid.UUID
-	Title       string
-	Description string
-	Priority    string
-	AssignedTo  string
-	DueDate     *time.Time
-	Metadata    json.RawMessage
+	WorkspaceID  uuid.UUID
+	ProjectID    uuid.UUID
+	ParentTaskID *uuid.UUID
+	Title        string
+	Description  string
+	Priority     string
+	Assignees    []string
+	DueDate      *time.Time
+	Metadata     json.RawMessage
+
+	OriginalEstimate  *int64 // seconds
+	RemainingEstimate *int64 // seconds; defaults to OriginalEstimate
 }
 
 type UpdateTaskParams struct {
@@ -40,19 +57,81 @@ type UpdateTaskParams struct {
 	Description string
 	Status      string
 	Priority    string
-	AssignedTo  string
+	Assignees   []string // replaces the whole list
 	DueDate     *time.Time
 	Metadata    json.RawMessage
+	UpdateMask  []string // fields to write; empty writes all
+
+	OriginalEstimate  *int64 // seconds; nil clears the estimate
+	RemainingEstimate *int64 // seconds
+	Version           int32  // expected current version; 0 skips the check
+	FromStatus        string // status the transition was checked from; empty skips the check
+}
+
+// MoveTaskParams places a task in a status column. At most one of BeforeID
+// and AfterID is set; with neither the task goes to the end of the column.
+type MoveTaskParams struct {
+	ID       uuid.UUID
+	Status   string
+	BeforeID *uuid.UUID
+	AfterID  *uuid.UUID
+	Version  int32 // expected current version; 0 skips the check
+
+	FromStatus string // status the transition was checked from; empty skips the check
+}
+
+// CloneTaskParams copies a task into a new one. A nil ProjectID keeps the
+// task's own project.
+type CloneTaskParams struct {
+	ID              uuid.UUID
+	ProjectID       uuid.UUID
+	IncludeComments bool
+}
+
+// MoveTaskToProjectParams moves a task and its subtasks to another project
+// of the same workspace.
+type MoveTaskToProjectParams struct {
+	ID        uuid.UUID
+	ProjectID uuid.UUID
+	Version   int32 // expected current version; 0 skips the check
 }
 
 type ListTasksParams struct {
+	WorkspaceID  uuid.UUID
+	ProjectID    uuid.UUID        // optional filter
+	ParentTaskID uuid.UUID        // optional filter
+	Status       string           // optional filter
+	Priority     string           // optional filter
+	AssignedTo   string           // optional: one of the task's assignees
+	LabelIDsAny  []uuid.UUID      // optional: task has at least one of these labels
+	LabelIDsAll  []uuid.UUID      // optional: task has all of these labels (distinct)
+	Metadata     []MetadataFilter // optional: task matches every filter
+	Query        *taskquery.Query // optional: task matches the parsed query
+	Due          TimeRange        // optional: due date range, as UTC days
+	Created      TimeRange        // optional
+	Updated      TimeRange        // optional
+	Overdue      bool             // optional: due before today (UTC) and not done
+	NoDueDate    bool             // optional: task has no due date
+	Sort         Sort             // created_at, updated_at, due_date, priority, title or status
+	OrderByRank  bool             // board order; overrides Sort
+	PageSize     int32
+	PageToken    string
+}
+
+// MetadataFilter is a predicate on one top-level key of task metadata.
+type MetadataFilter struct {
+	Key      string
+	Op       string          // equals, contains, exists, range
+	Value    json.RawMessage // equals and contains
+	Exists   bool            // exists: whether the key must be present
+	Min, Max *float64        // range; inclusive, either may be nil
+}
+
+type ListDeletedTasksParams struct {
 	WorkspaceID uuid.UUID
 	ProjectID   uuid.UUID // optional filter
-	Status      string    // optional filter
-	Priority    string    // optional filter
-	AssignedTo  string    // optional filter
 	PageSize    int32
-	PageToken   string
+	PageToken   string // ID of the first task of the page
 }
 
 type TaskList struct {
@@ -61,11 +140,51 @@ type TaskList struct {
 	TotalCount    int32
 }
 
+// Board is a project's tasks grouped by status column, in workflow order.
+type Board struct {
+	ProjectID uuid.UUID
+	Columns   []BoardColumn
+}
+
+type BoardColumn struct {
+	Status        string
+	IsDone        bool
+	TotalCount    int32
+	Tasks         []Task // first page in rank order
+	NextPageToken string // continues a rank-ordered List of the column
+}
+
+// TaskSelection picks tasks of a workspace for a bulk operation, either by
+// ID or, when Filter is set, by the ListTasks filters (paging is ignored).
+type TaskSelection struct {
+	WorkspaceID uuid.UUID
+	IDs         []uuid.UUID
+	Filter      *ListTasksParams
+}
+
+type BulkUpdateTasksParams struct {
+	Selection TaskSelection
+	Update    UpdateTaskParams // ID and Version are ignored
+}
+
+type BulkResult struct {
+	Total     int32
+	Succeeded int32
+	Failed    int32
+	Errors    []BulkError
+}
+
+type BulkError struct {
+	TaskID     uuid.UUID
+	Error      string
+	Violations []FieldViolation // set when the task's metadata was invalid
+}
+
 type TaskInput struct {
 	Title       string
 	Description string
 	Priority    string
-	AssignedTo  string
+	Assignees   []string
 	DueDate     *time.Time
 	Metadata    json.RawMessage
 }
@@ -78,6 +197,7 @@ type ImportResult struct {
 }
 
 type ImportError struct {
-	Index int32
-	Error string
+	Index      int32
+	Error      string
+	Violations []FieldViolation // set when the input's metadata was invalid
 }
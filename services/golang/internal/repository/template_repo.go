@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TemplateRepo stores task templates. Templates follow their project into
+// the trash: those of a deleted project are hidden until it is restored.
+type TemplateRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTemplateRepo(pool *pgxpool.Pool) *TemplateRepo {
+	return &TemplateRepo{pool: pool}
+}
+
+// templateColumns is the select list shared by every query that returns
+// templates. The task_templates table must be aliased as tt. Keep in sync
+// with scanTemplate.
+const templateColumns = `tt.id, tt.workspace_id, tt.project_id, tt.name, tt.title, tt.description,
+	tt.priority, tt.metadata, tt.subtasks, tt.created_at, tt.updated_at`
+
+func scanTemplate(row pgx.Row, tt *TaskTemplate) error {
+	var subtasks []byte
+	if err := row.Scan(&tt.ID, &tt.WorkspaceID, &tt.ProjectID, &tt.Name, &tt.Title, &tt.Description,
+		&tt.Priority, &tt.Metadata, &subtasks, &tt.CreatedAt, &tt.UpdatedAt); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(subtasks, &tt.Subtasks); err != nil {
+		return fmt.Errorf("decode template subtasks: %w", err)
+	}
+	return nil
+}
+
+// encodeSubtasks returns the task_templates.subtasks value for subtasks.
+func encodeSubtasks(subtasks []TemplateSubtask) ([]byte, error) {
+	if subtasks == nil {
+		subtasks = []TemplateSubtask{}
+	}
+	b, err := json.Marshal(subtasks)
+	if err != nil {
+		return nil, fmt.Errorf("encode template subtasks: %w", err)
+	}
+	return b, nil
+}
+
+// Create adds a template to a project, which must not be deleted, in the
+// project's workspace.
+func (r *TemplateRepo) Create(ctx context.Context, params CreateTemplateParams) (*TaskTemplate, error) {
+	metadata := params.Metadata
+	if metadata == nil {
+		metadata = []byte("{}")
+	}
+	subtasks, err := encodeSubtasks(params.Subtasks)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockParent(ctx, tx, "projects", params.ProjectID); err != nil {
+		return nil, err
+	}
+	var tt TaskTemplate
+	err = scanTemplate(tx.QueryRow(ctx,
+		`INSERT INTO task_templates AS tt (id, workspace_id, project_id, name, title, description, priority, metadata, subtasks, created_at, updated_at)
+		 SELECT gen_random_uuid(), p.workspace_id, p.id, $2, $3, $4, COALESCE(NULLIF($5, ''), 'medium'), $6, $7, NOW(), NOW()
+		 FROM projects p WHERE p.id = $1
+		 RETURNING `+templateColumns,
+		params.ProjectID, params.Name, params.Title, params.Description, params.Priority, metadata, subtasks,
+	), &tt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("create template: %w", ErrConflict)
+		}
+		return nil, fmt.Errorf("create template: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &tt, nil
+}
+
+func (r *TemplateRepo) GetByID(ctx context.Context, id uuid.UUID) (*TaskTemplate, error) {
+	var tt TaskTemplate
+	err := scanTemplate(r.pool.QueryRow(ctx,
+		`SELECT `+templateColumns+`
+		 FROM task_templates tt
+		 JOIN projects p ON p.id = tt.project_id AND p.deleted_at IS NULL
+		 WHERE tt.id = $1`,
+		id,
+	), &tt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get template: %w", err)
+	}
+	return &tt, nil
+}
+
+// templateSortKeys are the orders List supports. Names sort ignoring case,
+// as they are unique.
+var templateSortKeys = map[string]sortKey{
+	"":           {expr: "lower(%[1]s.name)", typ: "text"},
+	"name":       {expr: "lower(%[1]s.name)", typ: "text"},
+	"created_at": {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"updated_at": {expr: "%[1]s.updated_at", typ: "timestamptz", desc: true},
+}
+
+// List returns a project's templates, alphabetically by default. A deleted
+// project has none.
+func (r *TemplateRepo) List(ctx context.Context, params ListTemplatesParams) (*TemplateList, error) {
+	pageSize := params.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalCount int32
+	err = r.pool.QueryRow(ctx,
+		`SELECT COUNT(*)::int FROM task_templates tt
+		 JOIN projects p ON p.id = tt.project_id AND p.deleted_at IS NULL
+		 WHERE tt.project_id = $1`,
+		params.ProjectID,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("count templates: %w", err)
+	}
+
+	orderBy, sortValue, cursorCond, err := keyset(templateSortKeys, params.Sort, "tt", 2)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+templateColumns+`, `+sortValue+`
+		 FROM task_templates tt
+		 JOIN projects p ON p.id = tt.project_id AND p.deleted_at IS NULL
+		 WHERE tt.project_id = $1
+		   AND ($3::uuid IS NULL OR `+cursorCond+`)
+		 ORDER BY `+orderBy+` LIMIT $4`,
+		params.ProjectID, cursorValue, cursorID, pageSize+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+	defer rows.Close()
+
+	var templates []TaskTemplate
+	var value string
+	for rows.Next() {
+		var tt TaskTemplate
+		if err := scanTemplate(cursorRow{rows, &value}, &tt); err != nil {
+			return nil, fmt.Errorf("scan template: %w", err)
+		}
+		templates = append(templates, tt)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list templates: %w", err)
+	}
+
+	var nextPageToken string
+	if len(templates) > int(pageSize) {
+		nextPageToken = encodeCursor(value, templates[pageSize].ID.String())
+		templates = templates[:pageSize]
+	}
+
+	return &TemplateList{
+		Templates:     templates,
+		NextPageToken: nextPageToken,
+		TotalCount:    totalCount,
+	}, nil
+}
+
+// Update writes the fields named in params.UpdateMask, or all of them when
+// the mask is empty.
+func (r *TemplateRepo) Update(ctx context.Context, params UpdateTemplateParams) (*TaskTemplate, error) {
+	metadata := params.Metadata
+	if metadata == nil {
+		metadata = []byte("{}")
+	}
+	subtasks, err := encodeSubtasks(params.Subtasks)
+	if err != nil {
+		return nil, err
+	}
+
+	b := newUpdateBuilder(params.UpdateMask)
+	b.set("name", "name", params.Name)
+	b.set("title", "title", params.Title)
+	b.set("description", "description", params.Description)
+	if b.includes("priority") {
+		b.sets = append(b.sets, "priority = COALESCE(NULLIF("+b.arg(params.Priority)+", ''), 'medium')")
+	}
+	b.set("metadata", "metadata", metadata)
+	b.set("subtasks", "subtasks", subtasks)
+	query := fmt.Sprintf(
+		`UPDATE task_templates tt SET %s
+		 FROM projects p
+		 WHERE tt.id = %s AND p.id = tt.project_id AND p.deleted_at IS NULL
+		 RETURNING %s`,
+		b.clause(), b.arg(params.ID), templateColumns,
+	)
+	var tt TaskTemplate
+	if err := scanTemplate(r.pool.QueryRow(ctx, query, b.args...), &tt); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, fmt.Errorf("update template: %w", ErrConflict)
+		}
+		return nil, fmt.Errorf("update template: %w", err)
+	}
+	return &tt, nil
+}
+
+// Delete removes a template. Tasks created from it are not affected.
+func (r *TemplateRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM task_templates tt
+		 USING projects p
+		 WHERE tt.id = $1 AND p.id = tt.project_id AND p.deleted_at IS NULL`,
+		id)
+	if err != nil {
+		return fmt.Errorf("delete template: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/placeholder"
+)
+
+// TaskTemplate is a reusable blueprint for a task and its subtasks. Title
+// and descriptions may contain {{name}} placeholders.
+type TaskTemplate struct {
+	ID          uuid.UUID
+	WorkspaceID uuid.UUID
+	ProjectID   uuid.UUID
+	Name        string
+	Title       string
+	Description string
+	Priority    string
+	Metadata    json.RawMessage
+	Subtasks    []TemplateSubtask // in creation order
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Variables returns the names of the placeholders the template uses, in
+// its task or any subtask, sorted.
+func (tt *TaskTemplate) Variables() []string {
+	texts := []string{tt.Title, tt.Description}
+	for _, s := range tt.Subtasks {
+		texts = append(texts, s.Title, s.Description)
+	}
+	return placeholder.Names(texts...)
+}
+
+// TemplateSubtask is stored as an element of task_templates.subtasks.
+type TemplateSubtask struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	Priority    string          `json:"priority,omitempty"`
+	Metadata    json.RawMessage `json:"metadata,omitempty"`
+}
+
+type CreateTemplateParams struct {
+	ProjectID   uuid.UUID
+	Name        string
+	Title       string
+	Description string
+	Priority    string
+	Metadata    json.RawMessage
+	Subtasks    []TemplateSubtask
+}
+
+type UpdateTemplateParams struct {
+	ID          uuid.UUID
+	Name        string
+	Title       string
+	Description string
+	Priority    string
+	Metadata    json.RawMessage
+	Subtasks    []TemplateSubtask
+	UpdateMask  []string // name, title, description, priority, metadata, subtasks; empty writes all
+}
+
+type ListTemplatesParams struct {
+	ProjectID uuid.UUID
+	Sort      Sort // name, created_at or updated_at
+	PageSize  int32
+	PageToken string
+}
+
+type TemplateList struct {
+	Templates     []TaskTemplate
+	NextPageToken string
+	TotalCount    int32
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TimeEntryRepo stores time logged against tasks. Entries follow their task
+// into the trash: those of a deleted task are hidden from every query until
+// it is restored.
+type TimeEntryRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTimeEntryRepo(pool *pgxpool.Pool) *TimeEntryRepo {
+	return &TimeEntryRepo{pool: pool}
+}
+
+// timeEntryColumns is the select list shared by every query that returns
+// time entries. The time_entries table must be aliased as e. Keep in sync
+// with scanTimeEntry.
+const timeEntryColumns = `e.id, e.task_id, e.workspace_id, e.user_id, e.started_at, e.duration_seconds,
+	e.note, e.created_at, e.updated_at`
+
+func scanTimeEntry(row pgx.Row, e *TimeEntry) error {
+	return row.Scan(&e.ID, &e.TaskID, &e.WorkspaceID, &e.UserID, &e.StartedAt, &e.DurationSeconds,
+		&e.Note, &e.CreatedAt, &e.UpdatedAt)
+}
+
+// Create logs time against a live task, in the task's workspace.
+func (r *TimeEntryRepo) Create(ctx context.Context, params CreateTimeEntryParams) (*TimeEntry, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockParent(ctx, tx, "tasks", params.TaskID); err != nil {
+		return nil, err
+	}
+	var e TimeEntry
+	err = scanTimeEntry(tx.QueryRow(ctx,
+		`INSERT INTO time_entries AS e (id, task_id, workspace_id, user_id, started_at, duration_seconds, note, created_at, updated_at)
+		 SELECT gen_random_uuid(), t.id, t.workspace_id, $2, $3, $4, $5, NOW(), NOW()
+		 FROM tasks t WHERE t.id = $1
+		 RETURNING `+timeEntryColumns,
+		params.TaskID, params.UserID, params.StartedAt, params.DurationSeconds, params.Note,
+	), &e)
+	if err != nil {
+		return nil, fmt.Errorf("create time entry: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *TimeEntryRepo) GetByID(ctx context.Context, id uuid.UUID) (*TimeEntry, error) {
+	var e TimeEntry
+	err := scanTimeEntry(r.pool.QueryRow(ctx,
+		`SELECT `+timeEntryColumns+`
+		 FROM time_entries e
+		 JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+		 WHERE e.id = $1`,
+		id,
+	), &e)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("get time entry: %w", err)
+	}
+	return &e, nil
+}
+
+// timeEntrySortKeys are the orders List supports.
+var timeEntrySortKeys = map[string]sortKey{
+	"":           {expr: "%[1]s.started_at", typ: "timestamptz", desc: true},
+	"started_at": {expr: "%[1]s.started_at", typ: "timestamptz", desc: true},
+	"duration":   {expr: "%[1]s.duration_seconds", typ: "int", desc: true},
+	"updated_at": {expr: "%[1]s.updated_at", typ: "timestamptz", desc: true},
+}
+
+// List returns a workspace's time entries, latest started first unless
+// params.Sort says otherwise.
+func (r *TimeEntryRepo) List(ctx context.Context, params ListTimeEntriesParams) (*TimeEntryList, error) {
+	pageSize := params.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	var taskID *uuid.UUID
+	if params.TaskID != uuid.Nil {
+		taskID = &params.TaskID
+	}
+	var userID *string
+	if params.UserID != "" {
+		userID = &params.UserID
+	}
+
+	const filter = `e.workspace_id = $1
+		   AND ($2::uuid IS NULL OR e.task_id = $2)
+		   AND ($3::varchar IS NULL OR e.user_id = $3)
+		   AND ($4::timestamptz IS NULL OR e.started_at >= $4)
+		   AND ($5::timestamptz IS NULL OR e.started_at < $5)`
+	args := []any{params.WorkspaceID, taskID, userID, params.Range.From, params.Range.To}
+
+	var totalCount int32
+	err = r.pool.QueryRow(ctx,
+		`SELECT COUNT(*)::int FROM time_entries e
+		 JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+		 WHERE `+filter,
+		args...,
+	).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("count time entries: %w", err)
+	}
+
+	orderBy, sortValue, cursorCond, err := keyset(timeEntrySortKeys, params.Sort, "e", 6)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+timeEntryColumns+`, `+sortValue+`
+		 FROM time_entries e
+		 JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+		 WHERE `+filter+`
+		   AND ($7::uuid IS NULL OR `+cursorCond+`)
+		 ORDER BY `+orderBy+` LIMIT $8`,
+		append(args, cursorValue, cursorID, pageSize+1)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []TimeEntry
+	var value string
+	for rows.Next() {
+		var e TimeEntry
+		if err := scanTimeEntry(cursorRow{rows, &value}, &e); err != nil {
+			return nil, fmt.Errorf("scan time entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list time entries: %w", err)
+	}
+
+	var nextPageToken string
+	if len(entries) > int(pageSize) {
+		nextPageToken = encodeCursor(value, entries[pageSize].ID.String())
+		entries = entries[:pageSize]
+	}
+
+	return &TimeEntryList{
+		TimeEntries:   entries,
+		NextPageToken: nextPageToken,
+		TotalCount:    totalCount,
+	}, nil
+}
+
+func (r *TimeEntryRepo) Update(ctx context.Context, params UpdateTimeEntryParams) (*TimeEntry, error) {
+	b := newUpdateBuilder(params.UpdateMask)
+	b.set("started_at", "started_at", params.StartedAt)
+	b.set("duration", "duration_seconds", params.DurationSeconds)
+	b.set("note", "note", params.Note)
+	query := fmt.Sprintf(
+		`UPDATE time_entries e SET %s
+		 FROM tasks t
+		 WHERE e.id = %s AND t.id = e.task_id AND t.deleted_at IS NULL
+		 RETURNING %s`,
+		b.clause(), b.arg(params.ID), timeEntryColumns,
+	)
+	var e TimeEntry
+	if err := scanTimeEntry(r.pool.QueryRow(ctx, query, b.args...), &e); err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("update time entry: %w", err)
+	}
+	return &e, nil
+}
+
+func (r *TimeEntryRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM time_entries e
+		 USING tasks t
+		 WHERE e.id = $1 AND t.id = e.task_id AND t.deleted_at IS NULL`,
+		id)
+	if err != nil {
+		return fmt.Errorf("delete time entry: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// timeTotalKeys maps a grouping to the expression it groups by. "user" is
+// the user who logged the time; "assignee" is the task's first assignee, so
+// each entry still counts once.
+var timeTotalKeys = map[string]string{
+	"":         "''",
+	"task":     "e.task_id::text",
+	"project":  "t.project_id::text",
+	"user":     "e.user_id",
+	"assignee": "COALESCE(t.assigned_to, '')",
+}
+
+// Totals sums the time logged in a workspace, optionally for one project,
+// in groups ordered by the time logged, largest first.
+func (r *TimeEntryRepo) Totals(ctx context.Context, params TimeTotalsParams) (*TimeTotals, error) {
+	key, ok := timeTotalKeys[params.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("%w: invalid group_by: %s", ErrInvalidInput, params.GroupBy)
+	}
+	var projectID *uuid.UUID
+	if params.ProjectID != uuid.Nil {
+		projectID = &params.ProjectID
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT `+key+`, SUM(e.duration_seconds)::bigint, COUNT(*)::int
+		 FROM time_entries e
+		 JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+		 WHERE e.workspace_id = $1
+		   AND ($2::uuid IS NULL OR t.project_id = $2)
+		   AND ($3::timestamptz IS NULL OR e.started_at >= $3)
+		   AND ($4::timestamptz IS NULL OR e.started_at < $4)
+		 GROUP BY 1
+		 ORDER BY 2 DESC, 1`,
+		params.WorkspaceID, projectID, params.Range.From, params.Range.To,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("sum time entries: %w", err)
+	}
+	defer rows.Close()
+
+	totals := &TimeTotals{}
+	for rows.Next() {
+		var t TimeTotal
+		if err := rows.Scan(&t.Key, &t.DurationSeconds, &t.EntryCount); err != nil {
+			return nil, fmt.Errorf("scan time total: %w", err)
+		}
+		totals.DurationSeconds += t.DurationSeconds
+		totals.EntryCount += t.EntryCount
+		if params.GroupBy != "" {
+			totals.Totals = append(totals.Totals, t)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("sum time entries: %w", err)
+	}
+	return totals, nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeEntry is time a user logged against a task.
+type TimeEntry struct {
+	ID              uuid.UUID
+	TaskID          uuid.UUID
+	WorkspaceID     uuid.UUID
+	UserID          string
+	StartedAt       time.Time
+	DurationSeconds int64
+	Note            string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type CreateTimeEntryParams struct {
+	TaskID          uuid.UUID
+	UserID          string
+	StartedAt       time.Time
+	DurationSeconds int64
+	Note            string
+}
+
+type UpdateTimeEntryParams struct {
+	ID              uuid.UUID
+	StartedAt       time.Time
+	DurationSeconds int64
+	Note            string
+	UpdateMask      []string // fields to write; empty writes all
+}
+
+// TimeRange bounds started_at: From is inclusive, To exclusive, and a nil
+// end is open.
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+type ListTimeEntriesParams struct {
+	WorkspaceID uuid.UUID
+	TaskID      uuid.UUID // optional filter
+	UserID      string    // optional filter
+	Range       TimeRange
+	Sort        Sort // started_at, duration or updated_at
+	PageSize    int32
+	PageToken   string // ID of the first entry of the page
+}
+
+type TimeEntryList struct {
+	TimeEntries   []TimeEntry
+	NextPageToken string
+	TotalCount    int32
+}
+
+type TimeTotalsParams struct {
+	WorkspaceID uuid.UUID
+	ProjectID   uuid.UUID // optional filter
+	Range       TimeRange
+	GroupBy     string // task, project, user (who logged), assignee; empty for the grand total only
+}
+
+type TimeTotal struct {
+	Key             string // task ID, project ID or user ID, per GroupBy
+	DurationSeconds int64
+	EntryCount      int32
+}
+
+type TimeTotals struct {
+	Totals          []TimeTotal // largest first
+	DurationSeconds int64
+	EntryCount      int32
+}
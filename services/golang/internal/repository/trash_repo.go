@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type TrashRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewTrashRepo(pool *pgxpool.Pool) *TrashRepo {
+	return &TrashRepo{pool: pool}
+}
+
+// Purge hard-deletes workspaces, projects and tasks soft-deleted before
+// cutoff, together with everything that belongs to them: the projects and
+// tasks of a purged workspace, the tasks of a purged project, and the
+// comments, attachments and queued notifications of every purged task.
+// Rows are locked while they are selected, so a concurrent restore either
+// wins or finds nothing left to restore.
+func (r *TrashRepo) Purge(ctx context.Context, cutoff time.Time) (*PurgeResult, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var workspaceIDs, projectIDs, taskIDs []uuid.UUID
+	err = tx.QueryRow(ctx,
+		`WITH w AS (
+		     SELECT id FROM workspaces WHERE deleted_at < $1 FOR UPDATE
+		 ),
+		 p AS (
+		     SELECT id FROM projects
+		     WHERE deleted_at < $1 OR workspace_id IN (SELECT id FROM w)
+		     FOR UPDATE
+		 ),
+		 t AS (
+		     SELECT id FROM tasks
+		     WHERE deleted_at < $1 OR project_id IN (SELECT id FROM p)
+		     FOR UPDATE
+		 )
+		 SELECT ARRAY(SELECT id FROM w), ARRAY(SELECT id FROM p), ARRAY(SELECT id FROM t)`,
+		cutoff,
+	).Scan(&workspaceIDs, &projectIDs, &taskIDs)
+	if err != nil {
+		return nil, fmt.Errorf("select expired rows: %w", err)
+	}
+
+	// Children first. Dependencies, label assignments, history, recurrences
+	// and reminder records go with their task via ON DELETE CASCADE.
+	var res PurgeResult
+	steps := []struct {
+		count *int64
+		query string
+		ids   []uuid.UUID
+	}{
+		{&res.Comments, `DELETE FROM task_comments WHERE task_id = ANY($1)`, taskIDs},
+		{&res.Attachments, `DELETE FROM attachments WHERE task_id = ANY($1)`, taskIDs},
+		{&res.Notifications, `DELETE FROM notification_queue WHERE payload->>'task_id' = ANY($1::uuid[]::text[])`, taskIDs},
+		{&res.Tasks, `DELETE FROM tasks WHERE id = ANY($1)`, taskIDs},
+		{&res.Projects, `DELETE FROM projects WHERE id = ANY($1)`, projectIDs},
+		{nil, `DELETE FROM labels WHERE workspace_id = ANY($1)`, workspaceIDs},
+		{nil, `DELETE FROM task_dependencies WHERE workspace_id = ANY($1)`, workspaceIDs},
+		{&res.Notifications, `DELETE FROM notification_queue WHERE workspace_id = ANY($1)`, workspaceIDs},
+		{&res.Workspaces, `DELETE FROM workspaces WHERE id = ANY($1)`, workspaceIDs},
+	}
+	for _, step := range steps {
+		if len(step.ids) == 0 {
+			continue
+		}
+		tag, err := tx.Exec(ctx, step.query, step.ids)
+		if err != nil {
+			return nil, fmt.Errorf("purge trash: %w", err)
+		}
+		if step.count != nil {
+			*step.count += tag.RowsAffected()
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &res, nil
+}
+
+// trashCursor returns the page token of a trash listing, ordered by
+// deleted_at, for a row; decodeCursor reads it back. Holding the row's
+// deleted_at, it starts the next page in the right place even if the row
+// is restored or purged in the meantime.
+func trashCursor(deletedAt *time.Time, id uuid.UUID) string {
+	return encodeCursor(deletedAt.Format(time.RFC3339Nano), id.String())
+}
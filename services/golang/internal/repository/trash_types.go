@@ -0,0 +1,11 @@
+package repository
+
+// PurgeResult counts the rows a purge removed.
+type PurgeResult struct {
+	Workspaces    int64
+	Projects      int64
+	Tasks         int64
+	Comments      int64
+	Attachments   int64
+	Notifications int64
+}
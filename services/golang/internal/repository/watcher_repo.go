@@ -0,0 +1,163 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// WatcherRepo manages task and project subscriptions. Assignees and
+// commenters are subscribed by triggers, which also fan notifications out
+// to watchers; this repo only handles explicit subscriptions.
+type WatcherRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewWatcherRepo(pool *pgxpool.Pool) *WatcherRepo {
+	return &WatcherRepo{pool: pool}
+}
+
+// WatchTask subscribes a user to a live task. Watching is idempotent: an
+// existing subscription is returned unchanged.
+func (r *WatcherRepo) WatchTask(ctx context.Context, taskID uuid.UUID, userID string) (*Watcher, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockParent(ctx, tx, "tasks", taskID); err != nil {
+		return nil, err
+	}
+	var w Watcher
+	err = tx.QueryRow(ctx,
+		`INSERT INTO task_watchers (task_id, user_id, reason, created_at)
+		 VALUES ($1, $2, 'manual', NOW())
+		 ON CONFLICT (task_id, user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		 RETURNING user_id, reason, created_at`,
+		taskID, userID,
+	).Scan(&w.UserID, &w.Reason, &w.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("watch task: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &w, nil
+}
+
+func (r *WatcherRepo) UnwatchTask(ctx context.Context, taskID uuid.UUID, userID string) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM task_watchers WHERE task_id = $1 AND user_id = $2`,
+		taskID, userID)
+	if err != nil {
+		return fmt.Errorf("unwatch task: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListTaskWatchers returns a live task's watchers, oldest first. Watchers of
+// the task's project are notified too but are not listed here.
+func (r *WatcherRepo) ListTaskWatchers(ctx context.Context, taskID uuid.UUID) ([]Watcher, error) {
+	if err := r.checkLive(ctx, "tasks", taskID); err != nil {
+		return nil, err
+	}
+	return r.list(ctx,
+		`SELECT user_id, reason, created_at FROM task_watchers
+		 WHERE task_id = $1
+		 ORDER BY created_at, user_id`,
+		taskID)
+}
+
+// WatchProject subscribes a user to every task in a live project.
+// Watching is idempotent: an existing subscription is returned unchanged.
+func (r *WatcherRepo) WatchProject(ctx context.Context, projectID uuid.UUID, userID string) (*Watcher, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockParent(ctx, tx, "projects", projectID); err != nil {
+		return nil, err
+	}
+	var w Watcher
+	err = tx.QueryRow(ctx,
+		`INSERT INTO project_watchers (project_id, user_id, created_at)
+		 VALUES ($1, $2, NOW())
+		 ON CONFLICT (project_id, user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		 RETURNING user_id, 'manual', created_at`,
+		projectID, userID,
+	).Scan(&w.UserID, &w.Reason, &w.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("watch project: %w", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return &w, nil
+}
+
+func (r *WatcherRepo) UnwatchProject(ctx context.Context, projectID uuid.UUID, userID string) error {
+	tag, err := r.pool.Exec(ctx,
+		`DELETE FROM project_watchers WHERE project_id = $1 AND user_id = $2`,
+		projectID, userID)
+	if err != nil {
+		return fmt.Errorf("unwatch project: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ListProjectWatchers returns a live project's watchers, oldest first.
+func (r *WatcherRepo) ListProjectWatchers(ctx context.Context, projectID uuid.UUID) ([]Watcher, error) {
+	if err := r.checkLive(ctx, "projects", projectID); err != nil {
+		return nil, err
+	}
+	return r.list(ctx,
+		`SELECT user_id, 'manual', created_at FROM project_watchers
+		 WHERE project_id = $1
+		 ORDER BY created_at, user_id`,
+		projectID)
+}
+
+// checkLive returns ErrNotFound unless the row exists and is not deleted.
+// table is always a constant.
+func (r *WatcherRepo) checkLive(ctx context.Context, table string, id uuid.UUID) error {
+	var exists bool
+	err := r.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM `+table+` WHERE id = $1 AND deleted_at IS NULL)`, id,
+	).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("check %s: %w", table, err)
+	}
+	if !exists {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *WatcherRepo) list(ctx context.Context, query string, id uuid.UUID) ([]Watcher, error) {
+	rows, err := r.pool.Query(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("list watchers: %w", err)
+	}
+	defer rows.Close()
+
+	var watchers []Watcher
+	for rows.Next() {
+		var w Watcher
+		if err := rows.Scan(&w.UserID, &w.Reason, &w.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan watcher: %w", err)
+		}
+		watchers = append(watchers, w)
+	}
+	return watchers, rows.Err()
+}
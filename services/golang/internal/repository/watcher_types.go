@@ -0,0 +1,10 @@
+package repository
+
+import "time"
+
+// Watcher is a user subscribed to a task's or project's notifications.
+type Watcher struct {
+	UserID    string
+	Reason    string // manual, assignee, commenter; always manual for projects
+	CreatedAt time.Time
+}
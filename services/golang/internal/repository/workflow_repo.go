@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type WorkflowRepo struct {
+	pool *pgxpool.Pool
+}
+
+func NewWorkflowRepo(pool *pgxpool.Pool) *WorkflowRepo {
+	return &WorkflowRepo{pool: pool}
+}
+
+func (r *WorkflowRepo) GetByProjectID(ctx context.Context, projectID uuid.UUID) (*Workflow, error) {
+	var exists bool
+	err := r.pool.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM projects WHERE id = $1 AND deleted_at IS NULL)`,
+		projectID,
+	).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("get workflow: %w", err)
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+	return loadWorkflow(ctx, r.pool, projectID)
+}
+
+// Replace swaps the project's statuses and transitions for the given ones.
+// Statuses that are still referenced by tasks (including soft-deleted ones)
+// cannot be removed.
+func (r *WorkflowRepo) Replace(ctx context.Context, params ReplaceWorkflowParams) (*Workflow, error) {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var projectID uuid.UUID
+	err = tx.QueryRow(ctx,
+		`SELECT id FROM projects WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`,
+		params.ProjectID,
+	).Scan(&projectID)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("lock project: %w", err)
+	}
+
+	names := make([]string, len(params.Statuses))
+	for i, s := range params.Statuses {
+		names[i] = s.Name
+	}
+
+	var inUse string
+	var taskCount int32
+	err = tx.QueryRow(ctx,
+		`SELECT status, COUNT(*)::int FROM tasks
+		 WHERE project_id = $1 AND NOT (status = ANY($2))
+		 GROUP BY status ORDER BY status LIMIT 1`,
+		params.ProjectID, names,
+	).Scan(&inUse, &taskCount)
+	if err == nil {
+		return nil, fmt.Errorf("%w: status %q is still used by %d tasks", ErrFailedPrecondition, inUse, taskCount)
+	}
+	if err != pgx.ErrNoRows {
+		return nil, fmt.Errorf("check status usage: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM project_status_transitions WHERE project_id = $1`, params.ProjectID); err != nil {
+		return nil, fmt.Errorf("delete transitions: %w", err)
+	}
+	if _, err := tx.Exec(ctx,
+		`DELETE FROM project_statuses WHERE project_id = $1 AND NOT (name = ANY($2))`,
+		params.ProjectID, names); err != nil {
+		return nil, fmt.Errorf("delete statuses: %w", err)
+	}
+	for i, s := range params.Statuses {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO project_statuses (project_id, name, position, is_done)
+			 VALUES ($1, $2, $3, $4)
+			 ON CONFLICT (project_id, name) DO UPDATE SET position = EXCLUDED.position, is_done = EXCLUDED.is_done`,
+			params.ProjectID, s.Name, i, s.IsDone); err != nil {
+			return nil, fmt.Errorf("upsert status: %w", err)
+		}
+	}
+	for _, t := range params.Transitions {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO project_status_transitions (project_id, from_status, to_status)
+			 VALUES ($1, $2, $3) ON CONFLICT DO NOTHING`,
+			params.ProjectID, t.From, t.To); err != nil {
+			return nil, fmt.Errorf("insert transition: %w", err)
+		}
+	}
+
+	w, err := loadWorkflow(ctx, tx, params.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit tx: %w", err)
+	}
+	return w, nil
+}
+
+func loadWorkflow(ctx context.Context, q DBTX, projectID uuid.UUID) (*Workflow, error) {
+	w := &Workflow{ProjectID: projectID}
+
+	rows, err := q.Query(ctx,
+		`SELECT name, is_done FROM project_statuses
+		 WHERE project_id = $1 ORDER BY position, name`,
+		projectID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list statuses: %w", err)
+	}
+	for rows.Next() {
+		var s WorkflowStatus
+		if err := rows.Scan(&s.Name, &s.IsDone); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan status: %w", err)
+		}
+		w.Statuses = append(w.Statuses, s)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list statuses: %w", err)
+	}
+
+	rows, err = q.Query(ctx,
+		`SELECT t.from_status, t.to_status
+		 FROM project_status_transitions t
+		 JOIN project_statuses f ON f.project_id = t.project_id AND f.name = t.from_status
+		 JOIN project_statuses s ON s.project_id = t.project_id AND s.name = t.to_status
+		 WHERE t.project_id = $1
+		 ORDER BY f.position, s.position`,
+		projectID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list transitions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var t WorkflowTransition
+		if err := rows.Scan(&t.From, &t.To); err != nil {
+			return nil, fmt.Errorf("scan transition: %w", err)
+		}
+		w.Transitions = append(w.Transitions, t)
+	}
+	return w, rows.Err()
+}
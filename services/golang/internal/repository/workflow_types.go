@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"github.com/google/uuid"
+)
+
+type Workflow struct {
+	ProjectID   uuid.UUID
+	Statuses    []WorkflowStatus // ordered; the first status is assigned to new tasks
+	Transitions []WorkflowTransition
+}
+
+type WorkflowStatus struct {
+	Name   string
+	IsDone bool
+}
+
+type WorkflowTransition struct {
+	From string
+	To   string
+}
+
+type ReplaceWorkflowParams struct {
+	ProjectID   uuid.UUID
+	Statuses    []WorkflowStatus
+	Transitions []WorkflowTransition
+}
+
+// InitialStatus returns the status assigned to newly created tasks.
+func (w *Workflow) InitialStatus() string {
+	if len(w.Statuses) == 0 {
+		return ""
+	}
+	return w.Statuses[0].Name
+}
+
+func (w *Workflow) HasStatus(name string) bool {
+	for _, s := range w.Statuses {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Workflow) IsDone(name string) bool {
+	for _, s := range w.Statuses {
+		if s.Name == name {
+			return s.IsDone
+		}
+	}
+	return false
+}
+
+// CanTransition reports whether a task may move from one status to another.
+// Staying in the same status is always allowed.
+func (w *Workflow) CanTransition(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, t := range w.Transitions {
+		if t.From == from && t.To == to {
+			return true
+		}
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
@@ -24,9 +25,9 @@ func (r *WorkspaceRepo) Create(ctx context.Context, params CreateWorkspaceParams
 	err := r.pool.QueryRow(ctx,
 		`INSERT INTO workspaces (id, name, slug, created_at, updated_at)
 		 VALUES (gen_random_uuid(), $1, $2, NOW(), NOW())
-		 RETURNING id, name, slug, created_at, updated_at, deleted_at`,
+		 RETURNING id, name, slug, version, created_at, updated_at, deleted_at`,
 		params.Name, params.Slug,
-	).Scan(&w.ID, &w.Name, &w.Slug, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt)
+	).Scan(&w.ID, &w.Name, &w.Slug, &w.Version, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
@@ -40,10 +41,10 @@ func (r *WorkspaceRepo) Create(ctx context.Context, params CreateWorkspaceParams
 func (r *WorkspaceRepo) GetByID(ctx context.Context, id uuid.UUID) (*Workspace, error) {
 	var w Workspace
 	err := r.pool.QueryRow(ctx,
-		`SELECT id, name, slug, created_at, updated_at, deleted_at
+		`SELECT id, name, slug, version, created_at, updated_at, deleted_at
 		 FROM workspaces WHERE id = $1 AND deleted_at IS NULL`,
 		id,
-	).Scan(&w.ID, &w.Name, &w.Slug, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt)
+	).Scan(&w.ID, &w.Name, &w.Slug, &w.Version, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			return nil, ErrNotFound
@@ -53,6 +54,14 @@ func (r *WorkspaceRepo) GetByID(ctx context.Context, id uuid.UUID) (*Workspace,
 	return &w, nil
 }
 
+// workspaceSortKeys are the orders List supports.
+var workspaceSortKeys = map[string]sortKey{
+	"":           {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"created_at": {expr: "%[1]s.created_at", typ: "timestamptz", desc: true},
+	"updated_at": {expr: "%[1]s.updated_at", typ: "timestamptz", desc: true},
+	"name":       {expr: "%[1]s.name", typ: "text"},
+}
+
 func (r *WorkspaceRepo) List(ctx context.Context, params ListWorkspacesParams) (*WorkspaceList, error) {
 	pageSize := params.PageSize
 	if pageSize <= 0 || pageSize > 100 {
@@ -65,43 +74,42 @@ func (r *WorkspaceRepo) List(ctx context.Context, params ListWorkspacesParams) (
 		return nil, fmt.Errorf("count workspaces: %w", err)
 	}
 
-	var rows pgx.Rows
-	if params.PageToken != "" {
-		cursorID, parseErr := uuid.Parse(params.PageToken)
-		if parseErr != nil {
-			return nil, fmt.Errorf("invalid page token: %w", parseErr)
-		}
-		rows, err = r.pool.Query(ctx,
-			`SELECT id, name, slug, created_at, updated_at, deleted_at
-			 FROM workspaces WHERE deleted_at IS NULL AND id < $1
-			 ORDER BY created_at DESC, id DESC LIMIT $2`,
-			cursorID, pageSize+1,
-		)
-	} else {
-		rows, err = r.pool.Query(ctx,
-			`SELECT id, name, slug, created_at, updated_at, deleted_at
-			 FROM workspaces WHERE deleted_at IS NULL
-			 ORDER BY created_at DESC, id DESC LIMIT $1`,
-			pageSize+1,
-		)
+	orderBy, sortValue, cursorCond, err := keyset(workspaceSortKeys, params.Sort, "w", 1)
+	if err != nil {
+		return nil, err
 	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := r.pool.Query(ctx,
+		`SELECT w.id, w.name, w.slug, w.version, w.created_at, w.updated_at, w.deleted_at, `+sortValue+`
+		 FROM workspaces w WHERE w.deleted_at IS NULL
+		   AND ($2::uuid IS NULL OR `+cursorCond+`)
+		 ORDER BY `+orderBy+` LIMIT $3`,
+		cursorValue, cursorID, pageSize+1,
+	)
 	if err != nil {
 		return nil, fmt.Errorf("list workspaces: %w", err)
 	}
 	defer rows.Close()
 
 	var workspaces []Workspace
+	var value string
 	for rows.Next() {
 		var w Workspace
-		if err := rows.Scan(&w.ID, &w.Name, &w.Slug, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt); err != nil {
+		if err := rows.Scan(&w.ID, &w.Name, &w.Slug, &w.Version, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt, &value); err != nil {
 			return nil, fmt.Errorf("scan workspace: %w", err)
 		}
 		workspaces = append(workspaces, w)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list workspaces: %w", err)
+	}
 
 	var nextPageToken string
 	if len(workspaces) > int(pageSize) {
-		nextPageToken = workspaces[pageSize].ID.String()
+		nextPageToken = encodeCursor(value, workspaces[pageSize].ID.String())
 		workspaces = workspaces[:pageSize]
 	}
 
@@ -112,16 +120,26 @@ func (r *WorkspaceRepo) List(ctx context.Context, params ListWorkspacesParams) (
 	}, nil
 }
 
+// Update writes the fields named in params.UpdateMask, or all of them when
+// the mask is empty.
 func (r *WorkspaceRepo) Update(ctx context.Context, params UpdateWorkspaceParams) (*Workspace, error) {
+	b := newUpdateBuilder(params.UpdateMask)
+	b.set("name", "name", params.Name)
+	b.set("slug", "slug", params.Slug)
+
 	var w Workspace
 	err := r.pool.QueryRow(ctx,
-		`UPDATE workspaces SET name = $1, slug = $2, updated_at = NOW()
-		 WHERE id = $3 AND deleted_at IS NULL
-		 RETURNING id, name, slug, created_at, updated_at, deleted_at`,
-		params.Name, params.Slug, params.ID,
-	).Scan(&w.ID, &w.Name, &w.Slug, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt)
+		fmt.Sprintf(`UPDATE workspaces SET %s
+		 WHERE id = %s AND deleted_at IS NULL%s
+		 RETURNING id, name, slug, version, created_at, updated_at, deleted_at`,
+			b.clause(), b.arg(params.ID), b.matchVersion(params.Version)),
+		b.args...,
+	).Scan(&w.ID, &w.Name, &w.Slug, &w.Version, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt)
 	if err != nil {
 		if err == pgx.ErrNoRows {
+			if params.Version != 0 {
+				return nil, staleOrNotFound(ctx, r.pool, "workspaces", params.ID)
+			}
 			return nil, ErrNotFound
 		}
 		var pgErr *pgconn.PgError
@@ -133,15 +151,132 @@ func (r *WorkspaceRepo) Update(ctx context.Context, params UpdateWorkspaceParams
 	return &w, nil
 }
 
-func (r *WorkspaceRepo) Delete(ctx context.Context, id uuid.UUID) error {
-	tag, err := r.pool.Exec(ctx,
-		`UPDATE workspaces SET deleted_at = NOW(), updated_at = NOW()
-		 WHERE id = $1 AND deleted_at IS NULL`, id)
+// Delete soft-deletes a workspace with all of its live projects and tasks in
+// one transaction, stamping them with the same deleted_at so Restore can
+// bring them back together. A non-zero version must match the current one.
+func (r *WorkspaceRepo) Delete(ctx context.Context, id uuid.UUID, version int32) error {
+	var deleted int64
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx,
+			`UPDATE workspaces SET deleted_at = NOW(), updated_at = NOW()
+			 WHERE id = $1 AND deleted_at IS NULL AND ($2 = 0 OR version = $2)`, id, version)
+		if err != nil {
+			return err
+		}
+		if deleted = tag.RowsAffected(); deleted == 0 {
+			return nil
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE projects SET deleted_at = NOW(), updated_at = NOW()
+			 WHERE workspace_id = $1 AND deleted_at IS NULL`, id); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx,
+			`UPDATE tasks SET deleted_at = NOW(), updated_at = NOW()
+			 WHERE workspace_id = $1 AND deleted_at IS NULL`, id)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("delete workspace: %w", err)
 	}
-	if tag.RowsAffected() == 0 {
+	if deleted == 0 {
+		if version != 0 {
+			return staleOrNotFound(ctx, r.pool, "workspaces", id)
+		}
 		return ErrNotFound
 	}
 	return nil
 }
+
+// ListDeleted lists soft-deleted workspaces, most recently deleted first.
+func (r *WorkspaceRepo) ListDeleted(ctx context.Context, params ListWorkspacesParams) (*WorkspaceList, error) {
+	pageSize := params.PageSize
+	if pageSize <= 0 || pageSize > 100 {
+		pageSize = 20
+	}
+	cursorValue, cursorID, err := decodeCursor(params.PageToken)
+	if err != nil {
+		return nil, err
+	}
+
+	var totalCount int32
+	err = r.pool.QueryRow(ctx, `SELECT COUNT(*)::int FROM workspaces WHERE deleted_at IS NOT NULL`).Scan(&totalCount)
+	if err != nil {
+		return nil, fmt.Errorf("count deleted workspaces: %w", err)
+	}
+
+	rows, err := r.pool.Query(ctx,
+		`SELECT id, name, slug, version, created_at, updated_at, deleted_at
+		 FROM workspaces WHERE deleted_at IS NOT NULL
+		   AND ($2::uuid IS NULL OR (deleted_at, id) <= ($1::text::timestamptz, $2))
+		 ORDER BY deleted_at DESC, id DESC LIMIT $3`,
+		cursorValue, cursorID, pageSize+1,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list deleted workspaces: %w", err)
+	}
+	defer rows.Close()
+
+	var workspaces []Workspace
+	for rows.Next() {
+		var w Workspace
+		if err := rows.Scan(&w.ID, &w.Name, &w.Slug, &w.Version, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt); err != nil {
+			return nil, fmt.Errorf("scan workspace: %w", err)
+		}
+		workspaces = append(workspaces, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list deleted workspaces: %w", err)
+	}
+
+	var nextPageToken string
+	if len(workspaces) > int(pageSize) {
+		nextPageToken = trashCursor(workspaces[pageSize].DeletedAt, workspaces[pageSize].ID)
+		workspaces = workspaces[:pageSize]
+	}
+
+	return &WorkspaceList{
+		Workspaces:    workspaces,
+		NextPageToken: nextPageToken,
+		TotalCount:    totalCount,
+	}, nil
+}
+
+// Restore undeletes a soft-deleted workspace together with the projects and
+// tasks deleted along with it (those with the same deleted_at). A workspace
+// that is live or already purged is reported as not found.
+func (r *WorkspaceRepo) Restore(ctx context.Context, id uuid.UUID) (*Workspace, error) {
+	var w Workspace
+	err := withActor(ctx, r.pool, func(tx pgx.Tx) error {
+		var deletedAt time.Time
+		err := tx.QueryRow(ctx,
+			`SELECT deleted_at FROM workspaces WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`, id,
+		).Scan(&deletedAt)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE projects SET deleted_at = NULL, updated_at = NOW()
+			 WHERE workspace_id = $1 AND deleted_at = $2`, id, deletedAt); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx,
+			`UPDATE tasks SET deleted_at = NULL, updated_at = NOW()
+			 WHERE workspace_id = $1 AND deleted_at = $2`, id, deletedAt); err != nil {
+			return err
+		}
+		return tx.QueryRow(ctx,
+			`UPDATE workspaces SET deleted_at = NULL, updated_at = NOW()
+			 WHERE id = $1
+			 RETURNING id, name, slug, version, created_at, updated_at, deleted_at`,
+			id,
+		).Scan(&w.ID, &w.Name, &w.Slug, &w.Version, &w.CreatedAt, &w.UpdatedAt, &w.DeletedAt)
+	})
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("restore workspace: %w", err)
+	}
+	return &w, nil
+}
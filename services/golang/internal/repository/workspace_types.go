@@ -10,6 +10,7 @@ type Workspace struct {
 	ID        uuid.UUID
 	Name      string
 	Slug      string
+	Version   int32 // bumped on every write
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	DeletedAt *time.Time
@@ -21,14 +22,17 @@ type CreateWorkspaceParams struct {
 }
 
 type UpdateWorkspaceParams struct {
-	ID   uuid.UUID
-	Name string
-	Slug string
+	ID         uuid.UUID
+	Name       string
+	Slug       string
+	UpdateMask []string // fields to write; empty writes all
+	Version    int32    // expected current version; 0 skips the check
 }
 
 type ListWorkspacesParams struct {
+	Sort      Sort // created_at, updated_at or name
 	PageSize  int32
-	PageToken string // cursor: UUID of last item
+	PageToken string // cursor: UUID of the first item of the page
 }
 
 type WorkspaceList struct {
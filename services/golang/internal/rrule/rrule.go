@@ -0,0 +1,388 @@
+// Package rrule parses and expands the subset of RFC 5545 recurrence rules
+// supported for recurring tasks.
+//
+// Supported parts: FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, BYDAY
+// (plain weekdays, no ordinals), BYMONTHDAY (1..31 or -31..-1), COUNT and
+// UNTIL. Occurrences are calendar dates; times of day are ignored.
+package rrule
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalid is wrapped by every parse error.
+var ErrInvalid = errors.New("invalid recurrence rule")
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxEmptyPeriods bounds how many periods in a row Next will search without
+// finding a date before giving up, so rules that can never match (e.g.
+// FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30 starting in February) end.
+const maxEmptyPeriods = 1000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+var weekdayNames = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int        // 0 means unbounded
+	Until      *time.Time // inclusive, date only
+}
+
+// Parse parses a rule such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10".
+// A leading "RRULE:" is accepted.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(s)), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalid)
+	}
+
+	r := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || value == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalid, part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%w: %s given more than once", ErrInvalid, key)
+		}
+		seen[key] = true
+
+		switch key {
+		case "FREQ":
+			switch f := Frequency(value); f {
+			case Daily, Weekly, Monthly, Yearly:
+				r.Freq = f
+			default:
+				return nil, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalid, value)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive integer", ErrInvalid)
+			}
+			r.Interval = n
+		case "COUNT":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be a positive integer", ErrInvalid)
+			}
+			r.Count = n
+		case "UNTIL":
+			until, err := parseUntil(value)
+			if err != nil {
+				return nil, err
+			}
+			r.Until = &until
+		case "BYDAY":
+			for _, name := range strings.Split(value, ",") {
+				day, ok := weekdays[name]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY value %s", ErrInvalid, name)
+				}
+				r.ByDay = append(r.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, v := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(v)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("%w: BYMONTHDAY values must be 1..31 or -31..-1", ErrInvalid)
+				}
+				r.ByMonthDay = append(r.ByMonthDay, n)
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalid, key)
+		}
+	}
+
+	if r.Freq == "" {
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalid)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, fmt.Errorf("%w: COUNT and UNTIL cannot both be set", ErrInvalid)
+	}
+	if r.Freq == Yearly && (len(r.ByDay) > 0 || len(r.ByMonthDay) > 0) {
+		return nil, fmt.Errorf("%w: BYDAY and BYMONTHDAY are not supported with FREQ=YEARLY", ErrInvalid)
+	}
+	if r.Freq == Weekly && len(r.ByMonthDay) > 0 {
+		return nil, fmt.Errorf("%w: BYMONTHDAY is not supported with FREQ=WEEKLY", ErrInvalid)
+	}
+	return r, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102", "20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return dateOf(t), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ", ErrInvalid)
+}
+
+// String returns the rule in canonical form.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		names := make([]string, len(r.ByDay))
+		for i, d := range r.ByDay {
+			names[i] = weekdayNames[d]
+		}
+		parts = append(parts, "BYDAY="+strings.Join(names, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, d := range r.ByMonthDay {
+			days[i] = strconv.Itoa(d)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.Format("20060102"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence strictly after `after` in the series
+// that starts at dtstart, together with its 1-based position in the series
+// (dtstart itself is occurrence 1). ok is false once the series has ended.
+//
+// The search starts at the period containing `after`; the occurrences of
+// the periods before it are only counted.
+func (r *Rule) Next(dtstart, after time.Time) (next time.Time, n int, ok bool) {
+	dtstart, after = dateOf(dtstart), dateOf(after)
+	if after.Before(dtstart) {
+		return dtstart, 1, true
+	}
+	if r.Until != nil && !r.Until.After(after) {
+		return time.Time{}, 0, false
+	}
+
+	start := r.periodOf(dtstart, after)
+	n = 1 + r.countBefore(dtstart, start)
+	for period, empty := start, 0; empty < maxEmptyPeriods; period++ {
+		empty++
+		for _, d := range r.expand(dtstart, period) {
+			if !d.After(dtstart) {
+				continue
+			}
+			empty = 0
+			if r.Until != nil && d.After(*r.Until) {
+				return time.Time{}, 0, false
+			}
+			n++
+			if r.Count > 0 && n > r.Count {
+				return time.Time{}, 0, false
+			}
+			if d.After(after) {
+				return d, n, true
+			}
+		}
+	}
+	return time.Time{}, 0, false
+}
+
+// periodOf returns the period containing date d, which is not before
+// dtstart.
+func (r *Rule) periodOf(dtstart, d time.Time) int {
+	switch r.Freq {
+	case Daily:
+		return daysBetween(dtstart, d) / r.Interval
+	case Weekly:
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		return (daysBetween(dtstart, d) + offset) / 7 / r.Interval
+	case Monthly:
+		months := (d.Year()-dtstart.Year())*12 + int(d.Month()) - int(dtstart.Month())
+		return months / r.Interval
+	default:
+		return (d.Year() - dtstart.Year()) / r.Interval
+	}
+}
+
+// countBefore returns how many dates after dtstart the periods before the
+// given one hold. Daily rules without BYMONTHDAY repeat their weekdays every
+// seven periods and weekly rules have the same dates in every period after
+// the first, so those are counted without expanding every period.
+func (r *Rule) countBefore(dtstart time.Time, period int) int {
+	if period == 0 {
+		return 0
+	}
+	switch {
+	case r.Freq == Daily && len(r.ByMonthDay) == 0:
+		matches := func(p int) int {
+			if r.matchesDay(dtstart.AddDate(0, 0, p*r.Interval)) {
+				return 1
+			}
+			return 0
+		}
+		week := 0
+		for p := 0; p < 7; p++ {
+			week += matches(p)
+		}
+		// Period 0 is dtstart itself, which is not counted.
+		c := period/7*week - matches(0)
+		for p := 0; p < period%7; p++ {
+			c += matches(p)
+		}
+		return c
+	case r.Freq == Weekly:
+		return r.countAfter(dtstart, 0) + (period-1)*len(r.expand(dtstart, 1))
+	default:
+		c := 0
+		for p := 0; p < period; p++ {
+			c += r.countAfter(dtstart, p)
+		}
+		return c
+	}
+}
+
+// countAfter returns how many dates of a period fall after dtstart.
+func (r *Rule) countAfter(dtstart time.Time, period int) int {
+	c := 0
+	for _, d := range r.expand(dtstart, period) {
+		if d.After(dtstart) {
+			c++
+		}
+	}
+	return c
+}
+
+// expand returns the sorted candidate dates in the given period (0 is the
+// period containing dtstart).
+func (r *Rule) expand(dtstart time.Time, period int) []time.Time {
+	step := period * r.Interval
+	var dates []time.Time
+
+	switch r.Freq {
+	case Daily:
+		d := dtstart.AddDate(0, 0, step)
+		if r.matchesDay(d) && r.matchesMonthDay(d) {
+			dates = append(dates, d)
+		}
+
+	case Weekly:
+		// Weeks start on Monday (RFC 5545 default WKST=MO).
+		offset := (int(dtstart.Weekday()) + 6) % 7
+		monday := dtstart.AddDate(0, 0, step*7-offset)
+		days := r.ByDay
+		if len(days) == 0 {
+			days = []time.Weekday{dtstart.Weekday()}
+		}
+		for _, wd := range days {
+			dates = append(dates, monday.AddDate(0, 0, (int(wd)+6)%7))
+		}
+
+	case Monthly:
+		first := time.Date(dtstart.Year(), dtstart.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		last := first.AddDate(0, 1, -1).Day()
+		switch {
+		case len(r.ByMonthDay) > 0:
+			for _, md := range r.ByMonthDay {
+				day := md
+				if md < 0 {
+					day = last + md + 1
+				}
+				if day < 1 || day > last {
+					continue
+				}
+				d := first.AddDate(0, 0, day-1)
+				if r.matchesDay(d) {
+					dates = append(dates, d)
+				}
+			}
+		case len(r.ByDay) > 0:
+			for day := 1; day <= last; day++ {
+				d := first.AddDate(0, 0, day-1)
+				if r.matchesDay(d) {
+					dates = append(dates, d)
+				}
+			}
+		default:
+			if dtstart.Day() <= last {
+				dates = append(dates, first.AddDate(0, 0, dtstart.Day()-1))
+			}
+		}
+
+	case Yearly:
+		year := dtstart.Year() + step
+		d := time.Date(year, dtstart.Month(), dtstart.Day(), 0, 0, 0, 0, time.UTC)
+		// Skip years where the date doesn't exist (Feb 29).
+		if d.Month() == dtstart.Month() {
+			dates = append(dates, d)
+		}
+	}
+
+	sort.Slice(dates, func(i, j int) bool { return dates[i].Before(dates[j]) })
+	return dedupe(dates)
+}
+
+func (r *Rule) matchesDay(d time.Time) bool {
+	if len(r.ByDay) == 0 {
+		return true
+	}
+	for _, wd := range r.ByDay {
+		if d.Weekday() == wd {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Rule) matchesMonthDay(d time.Time) bool {
+	if len(r.ByMonthDay) == 0 {
+		return true
+	}
+	last := time.Date(d.Year(), d.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	for _, md := range r.ByMonthDay {
+		if md == d.Day() || (md < 0 && last+md+1 == d.Day()) {
+			return true
+		}
+	}
+	return false
+}
+
+func dedupe(dates []time.Time) []time.Time {
+	out := dates[:0]
+	for i, d := range dates {
+		if i == 0 || !d.Equal(dates[i-1]) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// daysBetween returns the number of days from date a to date b.
+func daysBetween(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
+
+// dateOf truncates t to midnight UTC of its calendar date.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
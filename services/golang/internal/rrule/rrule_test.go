@@ -0,0 +1,163 @@
+package rrule
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string // canonical form
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"rrule:freq=weekly;interval=2;byday=mo,th;count=10", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,TH;COUNT=10"},
+		{" FREQ=MONTHLY;BYMONTHDAY=1,-1 ", "FREQ=MONTHLY;BYMONTHDAY=1,-1"},
+		{"FREQ=MONTHLY;BYDAY=FR;UNTIL=20261231T235959Z", "FREQ=MONTHLY;BYDAY=FR;UNTIL=20261231"},
+		{"FREQ=YEARLY;INTERVAL=1", "FREQ=YEARLY"},
+	}
+	for _, tt := range tests {
+		r, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := r.String(); got != tt.want {
+			t.Errorf("Parse(%q).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=-1",
+		"FREQ=DAILY;COUNT=3;UNTIL=20270101",
+		"FREQ=DAILY;UNTIL=2027-01-01",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=YEARLY;BYDAY=MO",
+		"FREQ=DAILY;WKST=MO",
+		"FREQ=DAILY;COUNT",
+	} {
+		if _, err := Parse(in); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error = %v, want ErrInvalid", in, err)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		dtstart time.Time
+		after   time.Time
+		want    time.Time
+		wantN   int
+		ended   bool
+	}{
+		{"before start", "FREQ=DAILY", date(2026, 3, 10), date(2026, 3, 1), date(2026, 3, 10), 1, false},
+		{"daily", "FREQ=DAILY", date(2026, 3, 10), date(2026, 3, 10), date(2026, 3, 11), 2, false},
+		{"daily interval", "FREQ=DAILY;INTERVAL=3", date(2026, 3, 10), date(2026, 3, 14), date(2026, 3, 16), 3, false},
+		{"weekdays", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", date(2026, 10, 16), date(2026, 10, 16), date(2026, 10, 19), 2, false},
+		{"weekly byday", "FREQ=WEEKLY;BYDAY=MO,TH", date(2026, 10, 15), date(2026, 10, 15), date(2026, 10, 19), 2, false},
+		{"biweekly", "FREQ=WEEKLY;INTERVAL=2", date(2026, 10, 14), date(2026, 10, 20), date(2026, 10, 28), 2, false},
+		{"last day of month", "FREQ=MONTHLY;BYMONTHDAY=-1", date(2026, 1, 31), date(2026, 1, 31), date(2026, 2, 28), 2, false},
+		{"monthly skips short months", "FREQ=MONTHLY", date(2026, 1, 31), date(2026, 1, 31), date(2026, 3, 31), 2, false},
+		{"leap day", "FREQ=YEARLY", date(2024, 2, 29), date(2024, 2, 29), date(2028, 2, 29), 2, false},
+		{"count", "FREQ=DAILY;COUNT=3", date(2026, 3, 10), date(2026, 3, 11), date(2026, 3, 12), 3, false},
+		{"count exhausted", "FREQ=DAILY;COUNT=3", date(2026, 3, 10), date(2026, 3, 12), time.Time{}, 0, true},
+		{"until inclusive", "FREQ=DAILY;UNTIL=20260312", date(2026, 3, 10), date(2026, 3, 11), date(2026, 3, 12), 3, false},
+		{"until passed", "FREQ=DAILY;UNTIL=20260312", date(2026, 3, 10), date(2026, 3, 12), time.Time{}, 0, true},
+		{"never matches", "FREQ=MONTHLY;INTERVAL=12;BYMONTHDAY=30", date(2026, 2, 1), date(2026, 2, 1), time.Time{}, 0, true},
+		{"old daily series", "FREQ=DAILY", date(1990, 1, 1), date(2026, 10, 17), date(2026, 10, 18), 13440, false},
+		{"old weekday series", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR", date(1990, 1, 1), date(2026, 10, 16), date(2026, 10, 19), 9601, false},
+		{"old weekly series", "FREQ=WEEKLY;BYDAY=MO,FR", date(1900, 1, 1), date(2026, 10, 16), date(2026, 10, 19), 13233, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, n, ok := r.Next(tt.dtstart, tt.after)
+			if ok == tt.ended {
+				t.Fatalf("Next ok = %v, want %v", ok, !tt.ended)
+			}
+			if !got.Equal(tt.want) || n != tt.wantN {
+				t.Errorf("Next = %s #%d, want %s #%d", got.Format(time.DateOnly), n, tt.want.Format(time.DateOnly), tt.wantN)
+			}
+		})
+	}
+}
+
+// TestNextMatchesExpansion checks Next, which seeks to the period of
+// `after`, against walking every period from dtstart.
+func TestNextMatchesExpansion(t *testing.T) {
+	rules := []string{
+		"FREQ=DAILY",
+		"FREQ=DAILY;INTERVAL=3",
+		"FREQ=DAILY;INTERVAL=2;BYDAY=MO,WE,SA",
+		"FREQ=DAILY;BYMONTHDAY=1,15,-1",
+		"FREQ=WEEKLY",
+		"FREQ=WEEKLY;INTERVAL=3;BYDAY=SU,TU,FR",
+		"FREQ=MONTHLY",
+		"FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=31,-3",
+		"FREQ=MONTHLY;BYDAY=TH",
+		"FREQ=MONTHLY;BYMONTHDAY=13;BYDAY=FR",
+		"FREQ=YEARLY;INTERVAL=3",
+		"FREQ=DAILY;COUNT=40",
+		"FREQ=WEEKLY;BYDAY=MO,TU;UNTIL=20270315",
+	}
+	starts := []time.Time{date(2024, 2, 29), date(2026, 1, 31), date(2026, 10, 18)}
+	for _, rule := range rules {
+		r, err := Parse(rule)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, dtstart := range starts {
+			// Occurrences 2.. in order, up to 2031.
+			var want []time.Time
+			for period := 0; period < 2500; period++ {
+				for _, d := range r.expand(dtstart, period) {
+					if d.After(dtstart) && d.Year() < 2032 {
+						want = append(want, d)
+					}
+				}
+			}
+			for after := dtstart; after.Year() < 2031; after = after.AddDate(0, 0, 1) {
+				i := 0
+				for i < len(want) && !want[i].After(after) {
+					i++
+				}
+				if i == len(want) {
+					break // beyond the expanded horizon
+				}
+				got, n, ok := r.Next(dtstart, after)
+				if (r.Count > 0 && i+2 > r.Count) || (r.Until != nil && want[i].After(*r.Until)) {
+					if ok {
+						t.Fatalf("%s from %s: Next(%s) = %s #%d, want end",
+							rule, dtstart.Format(time.DateOnly), after.Format(time.DateOnly), got.Format(time.DateOnly), n)
+					}
+					continue
+				}
+				if !ok || !got.Equal(want[i]) || n != i+2 {
+					t.Fatalf("%s from %s: Next(%s) = %s #%d (ok %v), want %s #%d",
+						rule, dtstart.Format(time.DateOnly), after.Format(time.DateOnly),
+						got.Format(time.DateOnly), n, ok, want[i].Format(time.DateOnly), i+2)
+				}
+			}
+		}
+	}
+}
@@ -14,13 +14,13 @@ import (
 )
 
 type ImportService struct {
-	taskRepo    *repository.TaskRepo
-	notifRepo   *repository.NotificationRepo
-	concurrency int
-	rateLimit   rate.Limit
+	taskRepo        *repository.TaskRepo
+	customFieldRepo *repository.CustomFieldRepo
+	concurrency     int
+	rateLimit       rate.Limit
 }
 
-func NewImportService(taskRepo *repository.TaskRepo, notifRepo *repository.NotificationRepo, concurrency int, rateLimit float64) *ImportService {
+func NewImportService(taskRepo *repository.TaskRepo, customFieldRepo *repository.CustomFieldRepo, concurrency int, rateLimit float64) *ImportService {
 	if concurrency <= 0 {
 		concurrency = 10
 	}
@@ -28,20 +28,29 @@ func NewImportService(taskRepo *repository.TaskRepo, notifRepo *repository.Notif
 		rateLimit = 100
 	}
 	return &ImportService{
-		taskRepo:    taskRepo,
-		notifRepo:   notifRepo,
-		concurrency: concurrency,
-		rateLimit:   rate.Limit(rateLimit),
+		taskRepo:        taskRepo,
+		customFieldRepo: customFieldRepo,
+		concurrency:     concurrency,
+		rateLimit:       rate.Limit(rateLimit),
 	}
 }
 
-func (s *ImportService) BulkImport(ctx context.Context, workspaceID, projectID uuid.UUID, inputs []repository.TaskInput) *repository.ImportResult {
+// BulkImport creates the given tasks concurrently. Inputs that fail,
+// including those whose metadata does not match the project's custom
+// fields, are reported in the result; an error is returned only when the
+// project cannot be used at all.
+func (s *ImportService) BulkImport(ctx context.Context, workspaceID, projectID uuid.UUID, inputs []repository.TaskInput) (*repository.ImportResult, error) {
 	result := &repository.ImportResult{
 		Total: int32(len(inputs)),
 	}
 
 	if len(inputs) == 0 {
-		return result
+		return result, nil
+	}
+
+	schema, err := s.customFieldRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
 	}
 
 	slog.InfoContext(ctx, "starting bulk import",
@@ -71,13 +80,38 @@ func (s *ImportService) BulkImport(ctx context.Context, workspaceID, projectID u
 				return nil // don't cancel other goroutines
 			}
 
-			task, err := s.taskRepo.Create(ctx, repository.CreateTaskParams{
+			assignees, err := checkAssignees(input.Assignees)
+			if err != nil {
+				mu.Lock()
+				result.Failed++
+				result.Errors = append(result.Errors, repository.ImportError{
+					Index: int32(i),
+					Error: err.Error(),
+				})
+				mu.Unlock()
+				return nil
+			}
+
+			if violations := schema.Validate(input.Metadata); len(violations) > 0 {
+				verr := &repository.ValidationError{Violations: violations}
+				mu.Lock()
+				result.Failed++
+				result.Errors = append(result.Errors, repository.ImportError{
+					Index:      int32(i),
+					Error:      verr.Error(),
+					Violations: violations,
+				})
+				mu.Unlock()
+				return nil
+			}
+
+			_, err = s.taskRepo.Create(ctx, repository.CreateTaskParams{
 				WorkspaceID: workspaceID,
 				ProjectID:   projectID,
 				Title:       input.Title,
 				Description: input.Description,
 				Priority:    input.Priority,
-				AssignedTo:  input.AssignedTo,
+				Assignees:   assignees,
 				DueDate:     input.DueDate,
 				Metadata:    input.Metadata,
 			})
@@ -92,15 +126,6 @@ func (s *ImportService) BulkImport(ctx context.Context, workspaceID, projectID u
 				return nil
 			}
 
-			// Enqueue notification for each imported task
-			if s.notifRepo != nil {
-				_, _ = s.notifRepo.Create(ctx, repository.CreateNotificationParams{
-					WorkspaceID: workspaceID,
-					EventType:   "task.imported",
-					Payload:     []byte(fmt.Sprintf(`{"task_id":"%s","title":"%s"}`, task.ID, task.Title)),
-				})
-			}
-
 			mu.Lock()
 			result.Succeeded++
 			mu.Unlock()
@@ -117,5 +142,5 @@ func (s *ImportService) BulkImport(ctx context.Context, workspaceID, projectID u
 		"failed", result.Failed,
 	)
 
-	return result
+	return result, nil
 }
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+
+	"github.com/google/uuid"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+)
+
+const defaultLabelColor = "#808080"
+
+var labelColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+type LabelService struct {
+	repo     *repository.LabelRepo
+	taskRepo *repository.TaskRepo
+}
+
+func NewLabelService(repo *repository.LabelRepo, taskRepo *repository.TaskRepo) *LabelService {
+	return &LabelService{repo: repo, taskRepo: taskRepo}
+}
+
+func (s *LabelService) Create(ctx context.Context, params repository.CreateLabelParams) (*repository.Label, error) {
+	if params.WorkspaceID == uuid.Nil {
+		return nil, fmt.Errorf("%w: workspace_id is required", repository.ErrInvalidInput)
+	}
+	if params.Color == "" {
+		params.Color = defaultLabelColor
+	}
+	if err := validateLabelName(params.Name); err != nil {
+		return nil, err
+	}
+	if err := validateLabelColor(params.Color); err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "creating label", "name", params.Name, "workspace_id", params.WorkspaceID)
+	return s.repo.Create(ctx, params)
+}
+
+func (s *LabelService) GetByID(ctx context.Context, id uuid.UUID) (*repository.Label, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *LabelService) List(ctx context.Context, params repository.ListLabelsParams) (*repository.LabelList, error) {
+	return s.repo.List(ctx, params)
+}
+
+func (s *LabelService) Update(ctx context.Context, params repository.UpdateLabelParams) (*repository.Label, error) {
+	if inMask(params.UpdateMask, "name") {
+		if err := validateLabelName(params.Name); err != nil {
+			return nil, err
+		}
+	}
+	if inMask(params.UpdateMask, "color") {
+		if params.Color == "" {
+			params.Color = defaultLabelColor
+		}
+		if err := validateLabelColor(params.Color); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Update(ctx, params)
+}
+
+func (s *LabelService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *LabelService) AddToTask(ctx context.Context, taskID, labelID uuid.UUID) error {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return err
+	}
+	label, err := s.repo.GetByID(ctx, labelID)
+	if err != nil {
+		return err
+	}
+	if task.WorkspaceID != label.WorkspaceID {
+		return fmt.Errorf("%w: label belongs to a different workspace", repository.ErrInvalidInput)
+	}
+	return s.repo.AddToTask(ctx, taskID, labelID)
+}
+
+func (s *LabelService) RemoveFromTask(ctx context.Context, taskID, labelID uuid.UUID) error {
+	return s.repo.RemoveFromTask(ctx, taskID, labelID)
+}
+
+func validateLabelName(name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
+	}
+	if len(name) > 100 {
+		return fmt.Errorf("%w: name must be at most 100 characters", repository.ErrInvalidInput)
+	}
+	return nil
+}
+
+func validateLabelColor(color string) error {
+	if !labelColorPattern.MatchString(color) {
+		return fmt.Errorf("%w: color must be a #rrggbb hex value", repository.ErrInvalidInput)
+	}
+	return nil
+}
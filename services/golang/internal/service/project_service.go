@@ -4,18 +4,33 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"regexp"
 
 	"github.com/google/uuid"
 
 	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
 )
 
+// statusNamePattern matches workflow status names; they are stored in
+// VARCHAR(20) columns.
+var statusNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,19}$`)
+
+// customFieldKeyPattern matches custom field keys; they are stored in
+// VARCHAR(64) columns.
+var customFieldKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+const maxCustomFields = 50
+
+var customFieldTypes = map[string]bool{"text": true, "number": true, "date": true, "enum": true, "user": true}
+
 type ProjectService struct {
-	repo *repository.ProjectRepo
+	repo            *repository.ProjectRepo
+	workflowRepo    *repository.WorkflowRepo
+	customFieldRepo *repository.CustomFieldRepo
 }
 
-func NewProjectService(repo *repository.ProjectRepo) *ProjectService {
-	return &ProjectService{repo: repo}
+func NewProjectService(repo *repository.ProjectRepo, workflowRepo *repository.WorkflowRepo, customFieldRepo *repository.CustomFieldRepo) *ProjectService {
+	return &ProjectService{repo: repo, workflowRepo: workflowRepo, customFieldRepo: customFieldRepo}
 }
 
 func (s *ProjectService) Create(ctx context.Context, params repository.CreateProjectParams) (*repository.Project, error) {
@@ -38,16 +53,100 @@ func (s *ProjectService) List(ctx context.Context, params repository.ListProject
 }
 
 func (s *ProjectService) Update(ctx context.Context, params repository.UpdateProjectParams) (*repository.Project, error) {
-	if params.Name == "" {
+	if inMask(params.UpdateMask, "name") && params.Name == "" {
 		return nil, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
 	}
 	validStatuses := map[string]bool{"active": true, "archived": true}
-	if params.Status != "" && !validStatuses[params.Status] {
+	checkStatus := params.Status != "" || len(params.UpdateMask) > 0
+	if inMask(params.UpdateMask, "status") && checkStatus && !validStatuses[params.Status] {
 		return nil, fmt.Errorf("%w: invalid status: %s", repository.ErrInvalidInput, params.Status)
 	}
 	return s.repo.Update(ctx, params)
 }
 
-func (s *ProjectService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+func (s *ProjectService) Delete(ctx context.Context, id uuid.UUID, version int32) error {
+	return s.repo.Delete(ctx, id, version)
+}
+
+// ListDeleted lists a workspace's projects in the trash.
+func (s *ProjectService) ListDeleted(ctx context.Context, params repository.ListProjectsParams) (*repository.ProjectList, error) {
+	if params.WorkspaceID == uuid.Nil {
+		return nil, fmt.Errorf("%w: workspace_id is required", repository.ErrInvalidInput)
+	}
+	return s.repo.ListDeleted(ctx, params)
+}
+
+func (s *ProjectService) Restore(ctx context.Context, id uuid.UUID) (*repository.Project, error) {
+	slog.DebugContext(ctx, "restoring project", "project_id", id)
+	return s.repo.Restore(ctx, id)
+}
+
+func (s *ProjectService) GetWorkflow(ctx context.Context, projectID uuid.UUID) (*repository.Workflow, error) {
+	return s.workflowRepo.GetByProjectID(ctx, projectID)
+}
+
+func (s *ProjectService) UpdateWorkflow(ctx context.Context, params repository.ReplaceWorkflowParams) (*repository.Workflow, error) {
+	if len(params.Statuses) == 0 {
+		return nil, fmt.Errorf("%w: at least one status is required", repository.ErrInvalidInput)
+	}
+	seen := make(map[string]bool, len(params.Statuses))
+	for _, st := range params.Statuses {
+		if !statusNamePattern.MatchString(st.Name) {
+			return nil, fmt.Errorf("%w: invalid status name: %q", repository.ErrInvalidInput, st.Name)
+		}
+		if seen[st.Name] {
+			return nil, fmt.Errorf("%w: duplicate status: %s", repository.ErrInvalidInput, st.Name)
+		}
+		seen[st.Name] = true
+	}
+	for _, t := range params.Transitions {
+		if !seen[t.From] {
+			return nil, fmt.Errorf("%w: transition from unknown status: %s", repository.ErrInvalidInput, t.From)
+		}
+		if !seen[t.To] {
+			return nil, fmt.Errorf("%w: transition to unknown status: %s", repository.ErrInvalidInput, t.To)
+		}
+		if t.From == t.To {
+			return nil, fmt.Errorf("%w: transition from %s to itself", repository.ErrInvalidInput, t.From)
+		}
+	}
+	slog.DebugContext(ctx, "updating project workflow", "project_id", params.ProjectID, "statuses", len(params.Statuses))
+	return s.workflowRepo.Replace(ctx, params)
+}
+
+func (s *ProjectService) GetCustomFields(ctx context.Context, projectID uuid.UUID) (*repository.CustomFieldSchema, error) {
+	return s.customFieldRepo.GetByProjectID(ctx, projectID)
+}
+
+// UpdateCustomFields replaces a project's custom fields. An empty list
+// removes the schema, so task metadata is no longer checked.
+func (s *ProjectService) UpdateCustomFields(ctx context.Context, params repository.ReplaceCustomFieldsParams) (*repository.CustomFieldSchema, error) {
+	if len(params.Fields) > maxCustomFields {
+		return nil, fmt.Errorf("%w: at most %d custom fields are allowed", repository.ErrInvalidInput, maxCustomFields)
+	}
+	seen := make(map[string]bool, len(params.Fields))
+	for _, f := range params.Fields {
+		if !customFieldKeyPattern.MatchString(f.Key) {
+			return nil, fmt.Errorf("%w: invalid custom field key: %q", repository.ErrInvalidInput, f.Key)
+		}
+		if seen[f.Key] {
+			return nil, fmt.Errorf("%w: duplicate custom field: %s", repository.ErrInvalidInput, f.Key)
+		}
+		seen[f.Key] = true
+		if !customFieldTypes[f.Type] {
+			return nil, fmt.Errorf("%w: custom field %s: type is required", repository.ErrInvalidInput, f.Key)
+		}
+		if (f.Type == "enum") != (len(f.Options) > 0) {
+			return nil, fmt.Errorf("%w: custom field %s: options are required for enum fields and not allowed otherwise", repository.ErrInvalidInput, f.Key)
+		}
+		options := make(map[string]bool, len(f.Options))
+		for _, o := range f.Options {
+			if o == "" || options[o] {
+				return nil, fmt.Errorf("%w: custom field %s: options must be distinct and non-empty", repository.ErrInvalidInput, f.Key)
+			}
+			options[o] = true
+		}
+	}
+	slog.DebugContext(ctx, "updating project custom fields", "project_id", params.ProjectID, "fields", len(params.Fields))
+	return s.customFieldRepo.Replace(ctx, params)
 }
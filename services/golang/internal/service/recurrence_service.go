@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/rrule"
+)
+
+type RecurrenceService struct {
+	repo     *repository.RecurrenceRepo
+	taskRepo *repository.TaskRepo
+}
+
+func NewRecurrenceService(repo *repository.RecurrenceRepo, taskRepo *repository.TaskRepo) *RecurrenceService {
+	return &RecurrenceService{repo: repo, taskRepo: taskRepo}
+}
+
+// Set makes a task recurring, or replaces its rule. The series starts at the
+// task's due date, or today if it has none.
+func (s *RecurrenceService) Set(ctx context.Context, taskID uuid.UUID, rule string) (*repository.TaskRecurrence, error) {
+	parsed, err := rrule.Parse(rule)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", repository.ErrInvalidInput, err)
+	}
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	dtstart := today()
+	if task.DueDate != nil {
+		dtstart = *task.DueDate
+	}
+
+	slog.DebugContext(ctx, "setting task recurrence", "task_id", taskID, "rrule", parsed.String())
+	rec, err := s.repo.Set(ctx, repository.SetRecurrenceParams{
+		TaskID:  taskID,
+		RRule:   parsed.String(),
+		DTStart: dtstart,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rec.NextDueDate = nextOccurrence(parsed, rec, task.DueDate)
+	return rec, nil
+}
+
+func (s *RecurrenceService) Get(ctx context.Context, taskID uuid.UUID) (*repository.TaskRecurrence, error) {
+	task, err := s.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	rec, err := s.repo.GetByTaskID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if parsed, err := rrule.Parse(rec.RRule); err == nil {
+		rec.NextDueDate = nextOccurrence(parsed, rec, task.DueDate)
+	}
+	return rec, nil
+}
+
+// Cancel stops a series. Occurrences already created are left alone.
+func (s *RecurrenceService) Cancel(ctx context.Context, taskID uuid.UUID) error {
+	return s.repo.DeleteByTaskID(ctx, taskID)
+}
+
+// CreateDueOccurrences creates the next task for every series whose latest
+// occurrence has been completed or has reached its due date, and ends
+// series whose rule is exhausted. It returns how many tasks were created.
+func (s *RecurrenceService) CreateDueOccurrences(ctx context.Context, batchSize int) (int, error) {
+	due, err := s.repo.ListDue(ctx, today(), batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for i := range due {
+		d := &due[i]
+		parsed, err := rrule.Parse(d.RRule)
+		if err != nil {
+			slog.ErrorContext(ctx, "invalid stored recurrence rule", "recurrence_id", d.ID, "error", err)
+			if err := s.repo.Defer(ctx, d.ID); err != nil {
+				return created, err
+			}
+			continue
+		}
+		next, n, ok := parsed.Next(d.DTStart, occurrenceAfter(d.TaskDueDate))
+		if !ok {
+			slog.InfoContext(ctx, "recurrence ended", "recurrence_id", d.ID, "task_id", d.TaskID)
+			if err := s.repo.End(ctx, d.ID); err != nil {
+				return created, err
+			}
+			continue
+		}
+
+		task, err := s.repo.Advance(ctx, &d.TaskRecurrence, next, int32(n))
+		// Edited or advanced concurrently, or the project was archived or
+		// deleted since it was listed: picked up again by a later run if
+		// still due, behind the series not yet tried.
+		if errors.Is(err, repository.ErrConflict) || errors.Is(err, repository.ErrFailedPrecondition) {
+			if err := s.repo.Defer(ctx, d.ID); err != nil {
+				return created, err
+			}
+			continue
+		}
+		if err != nil {
+			return created, err
+		}
+		slog.DebugContext(ctx, "created recurring task", "recurrence_id", d.ID, "task_id", task.ID)
+		created++
+	}
+	return created, nil
+}
+
+// occurrenceAfter returns the date the next occurrence must fall after: the
+// current task's due date, or today if that is later. Occurrences missed
+// while nothing was running are skipped rather than created in a burst.
+func occurrenceAfter(currentDue *time.Time) time.Time {
+	after := today()
+	if currentDue != nil && currentDue.After(after) {
+		after = *currentDue
+	}
+	return after
+}
+
+func nextOccurrence(rule *rrule.Rule, rec *repository.TaskRecurrence, currentDue *time.Time) *time.Time {
+	next, _, ok := rule.Next(rec.DTStart, occurrenceAfter(currentDue))
+	if !ok {
+		return nil
+	}
+	return &next
+}
+
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
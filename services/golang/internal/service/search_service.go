@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+)
+
+const maxSearchQueryLength = 256
+
+type SearchService struct {
+	repo *repository.SearchRepo
+}
+
+func NewSearchService(repo *repository.SearchRepo) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+func (s *SearchService) Search(ctx context.Context, params repository.SearchParams) (*repository.SearchResultList, error) {
+	if params.WorkspaceID == uuid.Nil {
+		return nil, fmt.Errorf("%w: workspace_id is required", repository.ErrInvalidInput)
+	}
+	params.Query = strings.TrimSpace(params.Query)
+	if params.Query == "" {
+		return nil, fmt.Errorf("%w: query is required", repository.ErrInvalidInput)
+	}
+	if len(params.Query) > maxSearchQueryLength {
+		return nil, fmt.Errorf("%w: query must be at most %d characters", repository.ErrInvalidInput, maxSearchQueryLength)
+	}
+	return s.repo.Search(ctx, params)
+}
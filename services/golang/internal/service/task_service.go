@@ -2,21 +2,43 @@ package service
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"math"
+	"slices"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 
+	"github.com/igorrmotta/api-corestack/services/golang/internal/identity"
 	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/taskquery"
 )
 
+// maxTaskDepth bounds subtask nesting; a top-level task has depth 1.
+const maxTaskDepth = 3
+
+// maxMetadataFilters caps the metadata predicates of a task listing.
+const maxMetadataFilters = 10
+
+// maxBulkTasks caps how many tasks a single bulk update or delete touches.
+const maxBulkTasks = 1000
+
+// maxAssignees caps the assignees of a task.
+const maxAssignees = 10
+
 type TaskService struct {
-	repo      *repository.TaskRepo
-	notifRepo *repository.NotificationRepo
+	repo            *repository.TaskRepo
+	projectRepo     *repository.ProjectRepo
+	workflowRepo    *repository.WorkflowRepo
+	depRepo         *repository.DependencyRepo
+	historyRepo     *repository.HistoryRepo
+	customFieldRepo *repository.CustomFieldRepo
 }
 
-func NewTaskService(repo *repository.TaskRepo, notifRepo *repository.NotificationRepo) *TaskService {
-	return &TaskService{repo: repo, notifRepo: notifRepo}
+func NewTaskService(repo *repository.TaskRepo, projectRepo *repository.ProjectRepo, workflowRepo *repository.WorkflowRepo, depRepo *repository.DependencyRepo, historyRepo *repository.HistoryRepo, customFieldRepo *repository.CustomFieldRepo) *TaskService {
+	return &TaskService{repo: repo, projectRepo: projectRepo, workflowRepo: workflowRepo, depRepo: depRepo, historyRepo: historyRepo, customFieldRepo: customFieldRepo}
 }
 
 func (s *TaskService) Create(ctx context.Context, params repository.CreateTaskParams) (*repository.Task, error) {
@@ -30,54 +52,544 @@ func (s *TaskService) Create(ctx context.Context, params repository.CreateTaskPa
 		return nil, fmt.Errorf("%w: project_id is required", repository.ErrInvalidInput)
 	}
 
-	validPriorities := map[string]bool{"low": true, "medium": true, "high": true, "critical": true, "": true}
-	if !validPriorities[params.Priority] {
-		return nil, fmt.Errorf("%w: invalid priority: %s", repository.ErrInvalidInput, params.Priority)
+	if err := validatePriority("", params.Priority); err != nil {
+		return nil, err
 	}
-
-	slog.DebugContext(ctx, "creating task", "title", params.Title, "project_id", params.ProjectID)
-	task, err := s.repo.Create(ctx, params)
-	if err != nil {
+	if err := validateEstimates(params.OriginalEstimate, params.RemainingEstimate); err != nil {
+		return nil, err
+	}
+	var err error
+	if params.Assignees, err = checkAssignees(params.Assignees); err != nil {
+		return nil, err
+	}
+	if err := s.checkMetadata(ctx, params.ProjectID, params.Metadata); err != nil {
 		return nil, err
 	}
 
-	// Enqueue notification
-	if s.notifRepo != nil {
-		_, _ = s.notifRepo.Create(ctx, repository.CreateNotificationParams{
-			WorkspaceID: task.WorkspaceID,
-			EventType:   "task.created",
-			Payload:     []byte(fmt.Sprintf(`{"task_id":"%s","title":"%s"}`, task.ID, task.Title)),
-		})
+	if params.ParentTaskID != nil {
+		parent, err := s.repo.GetByID(ctx, *params.ParentTaskID)
+		if err != nil {
+			return nil, fmt.Errorf("parent task: %w", err)
+		}
+		if parent.ProjectID != params.ProjectID || parent.WorkspaceID != params.WorkspaceID {
+			return nil, fmt.Errorf("%w: parent task belongs to a different project", repository.ErrInvalidInput)
+		}
+		depth, err := s.repo.Depth(ctx, parent.ID)
+		if err != nil {
+			return nil, err
+		}
+		if depth >= maxTaskDepth {
+			return nil, fmt.Errorf("%w: subtasks cannot be nested more than %d levels deep", repository.ErrInvalidInput, maxTaskDepth)
+		}
 	}
 
-	return task, nil
+	slog.DebugContext(ctx, "creating task", "title", params.Title, "project_id", params.ProjectID)
+	return s.repo.Create(ctx, params)
 }
 
 func (s *TaskService) GetByID(ctx context.Context, id uuid.UUID) (*repository.Task, error) {
-	return s.repo.GetByID(ctx, id)
+	task, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := s.depRepo.ListByTask(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range deps.BlockedBy {
+		task.BlockedBy = append(task.BlockedBy, d.BlockerTaskID)
+	}
+	for _, d := range deps.Blocking {
+		task.Blocking = append(task.Blocking, d.BlockedTaskID)
+	}
+	return task, nil
 }
 
 func (s *TaskService) List(ctx context.Context, params repository.ListTasksParams) (*repository.TaskList, error) {
-	validStatuses := map[string]bool{"todo": true, "in_progress": true, "review": true, "done": true, "": true}
-	if !validStatuses[params.Status] {
-		return nil, fmt.Errorf("%w: invalid status filter: %s", repository.ErrInvalidInput, params.Status)
+	if err := validateTaskFilter(params); err != nil {
+		return nil, err
+	}
+	if params.OrderByRank && params.Sort.Field != "" {
+		return nil, fmt.Errorf("%w: sort_by cannot be combined with rank order", repository.ErrInvalidInput)
+	}
+	if err := s.prepareQuery(ctx, params); err != nil {
+		return nil, err
+	}
+	// Statuses are project-specific, so the filter can only be checked
+	// when the listing is scoped to a single project.
+	if params.Status != "" && params.ProjectID != uuid.Nil {
+		workflow, err := s.workflowRepo.GetByProjectID(ctx, params.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if !workflow.HasStatus(params.Status) {
+			return nil, fmt.Errorf("%w: invalid status filter: %s", repository.ErrInvalidInput, params.Status)
+		}
 	}
 	return s.repo.List(ctx, params)
 }
 
+func (s *TaskService) GetBoard(ctx context.Context, projectID uuid.UUID, pageSize int32) (*repository.Board, error) {
+	if projectID == uuid.Nil {
+		return nil, fmt.Errorf("%w: project_id is required", repository.ErrInvalidInput)
+	}
+	return s.repo.Board(ctx, projectID, pageSize)
+}
+
 func (s *TaskService) Update(ctx context.Context, params repository.UpdateTaskParams) (*repository.Task, error) {
-	if params.Title == "" {
+	if inMask(params.UpdateMask, "title") && params.Title == "" {
 		return nil, fmt.Errorf("%w: title is required", repository.ErrInvalidInput)
 	}
+	if err := validateEstimates(params.OriginalEstimate, params.RemainingEstimate); err != nil {
+		return nil, err
+	}
+	if inMask(params.UpdateMask, "assignees") {
+		var err error
+		if params.Assignees, err = checkAssignees(params.Assignees); err != nil {
+			return nil, err
+		}
+	}
 
-	validStatuses := map[string]bool{"todo": true, "in_progress": true, "review": true, "done": true, "": true}
-	if !validStatuses[params.Status] {
-		return nil, fmt.Errorf("%w: invalid status: %s", repository.ErrInvalidInput, params.Status)
+	current, err := s.repo.GetByID(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if inMask(params.UpdateMask, "status") {
+		if params.Status == "" {
+			if len(params.UpdateMask) > 0 {
+				return nil, fmt.Errorf("%w: status is required", repository.ErrInvalidInput)
+			}
+			params.Status = current.Status
+		}
+		if err := s.checkTransition(ctx, current, params.Status); err != nil {
+			return nil, err
+		}
+		params.FromStatus = current.Status
+	}
+	if inMask(params.UpdateMask, "metadata") {
+		if err := s.checkMetadata(ctx, current.ProjectID, params.Metadata); err != nil {
+			return nil, err
+		}
 	}
 
 	return s.repo.Update(ctx, params)
 }
 
-func (s *TaskService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+// Move places a task in a status column relative to another task of that
+// column. A status change must follow the project workflow.
+func (s *TaskService) Move(ctx context.Context, params repository.MoveTaskParams) (*repository.Task, error) {
+	if params.BeforeID != nil && params.AfterID != nil {
+		return nil, fmt.Errorf("%w: set at most one of before_task_id and after_task_id", repository.ErrInvalidInput)
+	}
+	for _, anchor := range []*uuid.UUID{params.BeforeID, params.AfterID} {
+		if anchor != nil && *anchor == params.ID {
+			return nil, fmt.Errorf("%w: a task cannot be placed next to itself", repository.ErrInvalidInput)
+		}
+	}
+
+	current, err := s.repo.GetByID(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if params.Status == "" {
+		params.Status = current.Status
+	}
+	if err := s.checkTransition(ctx, current, params.Status); err != nil {
+		return nil, err
+	}
+	params.FromStatus = current.Status
+	slog.DebugContext(ctx, "moving task", "task_id", params.ID, "status", params.Status)
+	return s.repo.Move(ctx, params)
+}
+
+// Clone copies a task, by default into its own project.
+func (s *TaskService) Clone(ctx context.Context, params repository.CloneTaskParams) (*repository.Task, error) {
+	src, err := s.repo.GetByID(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if params.ProjectID == uuid.Nil {
+		params.ProjectID = src.ProjectID
+	}
+	if err := s.checkTargetProject(ctx, src, params.ProjectID); err != nil {
+		return nil, err
+	}
+	if err := s.checkMetadata(ctx, params.ProjectID, src.Metadata); err != nil {
+		return nil, err
+	}
+
+	slog.DebugContext(ctx, "cloning task", "task_id", params.ID, "project_id", params.ProjectID)
+	return s.repo.Clone(ctx, params)
+}
+
+// MoveToProject moves a task and its subtasks to another project of its
+// workspace. Statuses are not checked against the workflow: tasks keep
+// theirs when the new project has it and start over otherwise.
+func (s *TaskService) MoveToProject(ctx context.Context, params repository.MoveTaskToProjectParams) (*repository.Task, error) {
+	current, err := s.repo.GetByID(ctx, params.ID)
+	if err != nil {
+		return nil, err
+	}
+	if current.ProjectID == params.ProjectID {
+		return nil, fmt.Errorf("%w: task is already in project %s", repository.ErrInvalidInput, params.ProjectID)
+	}
+	if err := s.checkTargetProject(ctx, current, params.ProjectID); err != nil {
+		return nil, err
+	}
+	schema, err := s.customFieldRepo.GetByProjectID(ctx, params.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every moved task, deleted subtasks included, must follow the new
+	// project's custom fields. The repository runs this on the locked
+	// subtree; subtask violations are reported under subtasks[<id>].
+	check := func(subtree []repository.Task) error {
+		var violations []repository.FieldViolation
+		for _, t := range subtree {
+			for _, v := range schema.Validate(t.Metadata) {
+				if t.ID != params.ID {
+					v.Field = fmt.Sprintf("subtasks[%s].%s", t.ID, v.Field)
+				}
+				violations = append(violations, v)
+			}
+		}
+		if len(violations) > 0 {
+			return &repository.ValidationError{Violations: violations}
+		}
+		return nil
+	}
+
+	slog.DebugContext(ctx, "moving task to project", "task_id", params.ID, "project_id", params.ProjectID)
+	return s.repo.MoveToProject(ctx, params, check)
+}
+
+// checkTargetProject checks that a task may be copied or moved into a
+// project: the project must be in the task's workspace.
+func (s *TaskService) checkTargetProject(ctx context.Context, task *repository.Task, projectID uuid.UUID) error {
+	project, err := s.projectRepo.GetByID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("project: %w", err)
+	}
+	if project.WorkspaceID != task.WorkspaceID {
+		return fmt.Errorf("%w: project belongs to a different workspace", repository.ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateTaskFilter checks the filters of a task listing that do not need
+// the database.
+func validateTaskFilter(params repository.ListTasksParams) error {
+	ranges := []struct {
+		name string
+		r    repository.TimeRange
+	}{{"due", params.Due}, {"created", params.Created}}
+	for _, rr := range ranges {
+		if rr.r.From != nil && rr.r.To != nil && !rr.r.To.After(*rr.r.From) {
+			return fmt.Errorf("%w: %s_before must be after %s_after", repository.ErrInvalidInput, rr.name, rr.name)
+		}
+	}
+	if params.NoDueDate && (params.Overdue || params.Due.From != nil || params.Due.To != nil) {
+		return fmt.Errorf("%w: no_due_date cannot be combined with due date filters", repository.ErrInvalidInput)
+	}
+	return validateMetadataFilters(params.Metadata)
+}
+
+func validateMetadataFilters(filters []repository.MetadataFilter) error {
+	if len(filters) > maxMetadataFilters {
+		return fmt.Errorf("%w: at most %d metadata filters are allowed", repository.ErrInvalidInput, maxMetadataFilters)
+	}
+	for _, f := range filters {
+		if f.Key == "" || len(f.Key) > 255 {
+			return fmt.Errorf("%w: metadata filter key must be 1 to 255 characters", repository.ErrInvalidInput)
+		}
+		switch f.Op {
+		case "equals", "contains":
+			if len(f.Value) == 0 {
+				return fmt.Errorf("%w: metadata filter %s: value is required", repository.ErrInvalidInput, f.Key)
+			}
+		case "exists":
+		case "range":
+			if f.Min == nil && f.Max == nil {
+				return fmt.Errorf("%w: metadata filter %s: range needs min or max", repository.ErrInvalidInput, f.Key)
+			}
+			for _, bound := range []*float64{f.Min, f.Max} {
+				if bound != nil && (math.IsNaN(*bound) || math.IsInf(*bound, 0)) {
+					return fmt.Errorf("%w: metadata filter %s: range bounds must be finite", repository.ErrInvalidInput, f.Key)
+				}
+			}
+			if f.Min != nil && f.Max != nil && *f.Min > *f.Max {
+				return fmt.Errorf("%w: metadata filter %s: min is greater than max", repository.ErrInvalidInput, f.Key)
+			}
+		default:
+			return fmt.Errorf("%w: metadata filter %s: a predicate is required", repository.ErrInvalidInput, f.Key)
+		}
+	}
+	return nil
+}
+
+// prepareQuery resolves "me" in a parsed task query to the caller and, when
+// the listing is scoped to one project, checks its statuses against the
+// project workflow.
+func (s *TaskService) prepareQuery(ctx context.Context, params repository.ListTasksParams) error {
+	if params.Query == nil {
+		return nil
+	}
+	var workflow *repository.Workflow
+	for i := range params.Query.Terms {
+		t := &params.Query.Terms[i]
+		for j := range t.Values {
+			v := &t.Values[j]
+			switch {
+			case v.Me:
+				if v.Text = identity.UserID(ctx); v.Text == "" {
+					return fmt.Errorf("%w: %w", repository.ErrInvalidInput,
+						taskquery.Errorf(v.Pos, "me needs the %s header", identity.Header))
+				}
+			case t.Field == "status" && params.ProjectID != uuid.Nil:
+				if workflow == nil {
+					var err error
+					if workflow, err = s.workflowRepo.GetByProjectID(ctx, params.ProjectID); err != nil {
+						return err
+					}
+				}
+				if !workflow.HasStatus(v.Text) {
+					return fmt.Errorf("%w: %w", repository.ErrInvalidInput,
+						taskquery.Errorf(v.Pos, "unknown status %q", v.Text))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkMetadata validates task metadata against the custom fields of the
+// project.
+func (s *TaskService) checkMetadata(ctx context.Context, projectID uuid.UUID, metadata json.RawMessage) error {
+	schema, err := s.customFieldRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if violations := schema.Validate(metadata); len(violations) > 0 {
+		return &repository.ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+// inMask reports whether an update with the given mask writes field. An
+// empty mask writes every field.
+func inMask(mask []string, field string) bool {
+	return len(mask) == 0 || slices.Contains(mask, field)
+}
+
+// checkAssignees validates the assignees of a task and drops repeated
+// ones, keeping the first occurrence.
+func checkAssignees(assignees []string) ([]string, error) {
+	var out []string
+	for _, a := range assignees {
+		if a == "" {
+			return nil, fmt.Errorf("%w: assignees cannot be empty", repository.ErrInvalidInput)
+		}
+		if utf8.RuneCountInString(a) > identity.MaxUserIDLength {
+			return nil, fmt.Errorf("%w: assignee IDs are at most %d characters", repository.ErrInvalidInput, identity.MaxUserIDLength)
+		}
+		if !slices.Contains(out, a) {
+			out = append(out, a)
+		}
+	}
+	if len(out) > maxAssignees {
+		return nil, fmt.Errorf("%w: at most %d assignees are allowed", repository.ErrInvalidInput, maxAssignees)
+	}
+	return out, nil
+}
+
+// validateEstimates checks that estimates, in seconds, are non-negative and
+// fit the INTEGER columns they are stored in.
+func validateEstimates(estimates ...*int64) error {
+	for _, e := range estimates {
+		if e != nil && (*e < 0 || *e > math.MaxInt32) {
+			return fmt.Errorf("%w: estimates must be between 0 and %d seconds", repository.ErrInvalidInput, math.MaxInt32)
+		}
+	}
+	return nil
+}
+
+// checkTransition validates a status change against the project's workflow.
+func (s *TaskService) checkTransition(ctx context.Context, task *repository.Task, status string) error {
+	if status == task.Status {
+		return nil
+	}
+	workflow, err := s.workflowRepo.GetByProjectID(ctx, task.ProjectID)
+	if err != nil {
+		return err
+	}
+	return s.checkWorkflowTransition(ctx, workflow, task, status)
+}
+
+func (s *TaskService) checkWorkflowTransition(ctx context.Context, workflow *repository.Workflow, task *repository.Task, status string) error {
+	if status == task.Status {
+		return nil
+	}
+	if !workflow.HasStatus(status) {
+		return fmt.Errorf("%w: invalid status: %s", repository.ErrInvalidInput, status)
+	}
+	if !workflow.CanTransition(task.Status, status) {
+		return fmt.Errorf("%w: transition from %s to %s is not allowed", repository.ErrFailedPrecondition, task.Status, status)
+	}
+	if workflow.IsDone(status) {
+		open, err := s.depRepo.CountOpenBlockers(ctx, task.ID)
+		if err != nil {
+			return err
+		}
+		if open > 0 {
+			return fmt.Errorf("%w: task has %d open blockers", repository.ErrFailedPrecondition, open)
+		}
+	}
+	return nil
+}
+
+func (s *TaskService) AddDependency(ctx context.Context, blockerID, blockedID uuid.UUID) (*repository.TaskDependency, error) {
+	if blockerID == uuid.Nil {
+		return nil, fmt.Errorf("%w: blocker_task_id is required", repository.ErrInvalidInput)
+	}
+	if blockedID == uuid.Nil {
+		return nil, fmt.Errorf("%w: blocked_task_id is required", repository.ErrInvalidInput)
+	}
+	if blockerID == blockedID {
+		return nil, fmt.Errorf("%w: a task cannot block itself", repository.ErrInvalidInput)
+	}
+	slog.DebugContext(ctx, "adding task dependency", "blocker_task_id", blockerID, "blocked_task_id", blockedID)
+	return s.depRepo.Create(ctx, blockerID, blockedID)
+}
+
+func (s *TaskService) RemoveDependency(ctx context.Context, blockerID, blockedID uuid.UUID) error {
+	return s.depRepo.Delete(ctx, blockerID, blockedID)
+}
+
+func (s *TaskService) ListDependencies(ctx context.Context, taskID uuid.UUID) (*repository.TaskDependencies, error) {
+	if _, err := s.repo.GetByID(ctx, taskID); err != nil {
+		return nil, err
+	}
+	return s.depRepo.ListByTask(ctx, taskID)
+}
+
+func (s *TaskService) GetHistory(ctx context.Context, params repository.ListTaskHistoryParams) (*repository.TaskHistoryList, error) {
+	return s.historyRepo.ListByTask(ctx, params)
+}
+
+func (s *TaskService) Delete(ctx context.Context, id uuid.UUID, version int32) error {
+	return s.repo.Delete(ctx, id, version)
+}
+
+// ListDeleted lists a workspace's tasks in the trash.
+func (s *TaskService) ListDeleted(ctx context.Context, params repository.ListDeletedTasksParams) (*repository.TaskList, error) {
+	if params.WorkspaceID == uuid.Nil {
+		return nil, fmt.Errorf("%w: workspace_id is required", repository.ErrInvalidInput)
+	}
+	return s.repo.ListDeleted(ctx, params)
+}
+
+// Restore brings a task back from the trash with the subtasks deleted
+// along with it.
+func (s *TaskService) Restore(ctx context.Context, id uuid.UUID) (*repository.Task, error) {
+	slog.DebugContext(ctx, "restoring task", "task_id", id)
+	return s.repo.Restore(ctx, id)
+}
+
+// BulkUpdate applies one update to every selected task. Tasks that cannot
+// take the update, such as a disallowed status transition, are reported
+// individually; all others are written in a single transaction. Each task
+// is checked while locked, so a concurrent change cannot fail the batch.
+func (s *TaskService) BulkUpdate(ctx context.Context, params repository.BulkUpdateTasksParams) (*repository.BulkResult, error) {
+	update := params.Update
+	if len(update.UpdateMask) == 0 {
+		return nil, fmt.Errorf("%w: update_mask is required", repository.ErrInvalidInput)
+	}
+	if inMask(update.UpdateMask, "status") && update.Status == "" {
+		return nil, fmt.Errorf("%w: status is required", repository.ErrInvalidInput)
+	}
+	if inMask(update.UpdateMask, "priority") {
+		if update.Priority == "" {
+			return nil, fmt.Errorf("%w: priority is required", repository.ErrInvalidInput)
+		}
+		if err := validatePriority("", update.Priority); err != nil {
+			return nil, err
+		}
+	}
+	if inMask(update.UpdateMask, "assignees") {
+		var err error
+		if update.Assignees, err = checkAssignees(update.Assignees); err != nil {
+			return nil, err
+		}
+	}
+	update.Version = 0
+	if err := s.checkSelection(ctx, params.Selection); err != nil {
+		return nil, err
+	}
+
+	workflows := make(map[uuid.UUID]*repository.Workflow)
+	schemas := make(map[uuid.UUID]*repository.CustomFieldSchema)
+	check := func(t *repository.Task) error {
+		if inMask(update.UpdateMask, "metadata") {
+			schema, ok := schemas[t.ProjectID]
+			if !ok {
+				var err error
+				if schema, err = s.customFieldRepo.GetByProjectID(ctx, t.ProjectID); err != nil {
+					return err
+				}
+				schemas[t.ProjectID] = schema
+			}
+			if violations := schema.Validate(update.Metadata); len(violations) > 0 {
+				return &repository.ValidationError{Violations: violations}
+			}
+		}
+		if inMask(update.UpdateMask, "status") && update.Status != t.Status {
+			workflow, ok := workflows[t.ProjectID]
+			if !ok {
+				var err error
+				if workflow, err = s.workflowRepo.GetByProjectID(ctx, t.ProjectID); err != nil {
+					return err
+				}
+				workflows[t.ProjectID] = workflow
+			}
+			return s.checkWorkflowTransition(ctx, workflow, t, update.Status)
+		}
+		return nil
+	}
+
+	slog.InfoContext(ctx, "bulk updating tasks", "workspace_id", params.Selection.WorkspaceID, "fields", update.UpdateMask)
+	return s.repo.BulkUpdate(ctx, params.Selection, maxBulkTasks, update, check)
+}
+
+// BulkDelete soft-deletes every selected task, with their subtasks, in a
+// single transaction.
+func (s *TaskService) BulkDelete(ctx context.Context, sel repository.TaskSelection) (*repository.BulkResult, error) {
+	if err := s.checkSelection(ctx, sel); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "bulk deleting tasks", "workspace_id", sel.WorkspaceID)
+	return s.repo.BulkDelete(ctx, sel, maxBulkTasks)
+}
+
+// checkSelection validates a bulk selection before the repository resolves
+// it to live tasks.
+func (s *TaskService) checkSelection(ctx context.Context, sel repository.TaskSelection) error {
+	if sel.WorkspaceID == uuid.Nil {
+		return fmt.Errorf("%w: workspace_id is required", repository.ErrInvalidInput)
+	}
+	if (len(sel.IDs) == 0) == (sel.Filter == nil) {
+		return fmt.Errorf("%w: set exactly one of task_ids and filter", repository.ErrInvalidInput)
+	}
+	if len(sel.IDs) > maxBulkTasks {
+		return fmt.Errorf("%w: at most %d task_ids per request", repository.ErrInvalidInput, maxBulkTasks)
+	}
+	if sel.Filter != nil {
+		if err := validateTaskFilter(*sel.Filter); err != nil {
+			return err
+		}
+		if err := s.prepareQuery(ctx, *sel.Filter); err != nil {
+			return err
+		}
+	}
+	return nil
 }
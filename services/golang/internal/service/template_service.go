@@ -0,0 +1,212 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/placeholder"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+)
+
+// maxTemplateSubtasks caps the subtasks of a task template.
+const maxTemplateSubtasks = 50
+
+// maxTitleLength is the size of tasks.title and task_templates.title.
+const maxTitleLength = 500
+
+type TemplateService struct {
+	repo            *repository.TemplateRepo
+	taskRepo        *repository.TaskRepo
+	customFieldRepo *repository.CustomFieldRepo
+}
+
+func NewTemplateService(repo *repository.TemplateRepo, taskRepo *repository.TaskRepo, customFieldRepo *repository.CustomFieldRepo) *TemplateService {
+	return &TemplateService{repo: repo, taskRepo: taskRepo, customFieldRepo: customFieldRepo}
+}
+
+func (s *TemplateService) Create(ctx context.Context, params repository.CreateTemplateParams) (*repository.TaskTemplate, error) {
+	if params.ProjectID == uuid.Nil {
+		return nil, fmt.Errorf("%w: project_id is required", repository.ErrInvalidInput)
+	}
+	if err := validateTemplateName(params.Name); err != nil {
+		return nil, err
+	}
+	if err := validateTemplateTitle("", params.Title); err != nil {
+		return nil, err
+	}
+	if err := validatePriority("", params.Priority); err != nil {
+		return nil, err
+	}
+	if err := validateTemplateSubtasks(params.Subtasks); err != nil {
+		return nil, err
+	}
+	slog.DebugContext(ctx, "creating template", "name", params.Name, "project_id", params.ProjectID)
+	return s.repo.Create(ctx, params)
+}
+
+func (s *TemplateService) GetByID(ctx context.Context, id uuid.UUID) (*repository.TaskTemplate, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *TemplateService) List(ctx context.Context, params repository.ListTemplatesParams) (*repository.TemplateList, error) {
+	return s.repo.List(ctx, params)
+}
+
+func (s *TemplateService) Update(ctx context.Context, params repository.UpdateTemplateParams) (*repository.TaskTemplate, error) {
+	if inMask(params.UpdateMask, "name") {
+		if err := validateTemplateName(params.Name); err != nil {
+			return nil, err
+		}
+	}
+	if inMask(params.UpdateMask, "title") {
+		if err := validateTemplateTitle("", params.Title); err != nil {
+			return nil, err
+		}
+	}
+	if inMask(params.UpdateMask, "priority") {
+		if err := validatePriority("", params.Priority); err != nil {
+			return nil, err
+		}
+	}
+	if inMask(params.UpdateMask, "subtasks") {
+		if err := validateTemplateSubtasks(params.Subtasks); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Update(ctx, params)
+}
+
+func (s *TemplateService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Instantiate creates a task and its subtasks from a template, with every
+// placeholder replaced by its value from vars, in one transaction. Their
+// metadata is checked against the project's current custom fields.
+func (s *TemplateService) Instantiate(ctx context.Context, id uuid.UUID, vars map[string]string) (*repository.Task, []repository.Task, error) {
+	tt, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	var missing []string
+	for _, name := range tt.Variables() {
+		if _, ok := vars[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, nil, fmt.Errorf("%w: missing template variables: %s", repository.ErrInvalidInput, strings.Join(missing, ", "))
+	}
+
+	params := repository.CreateTaskParams{
+		WorkspaceID: tt.WorkspaceID,
+		ProjectID:   tt.ProjectID,
+		Title:       placeholder.Fill(tt.Title, vars),
+		Description: placeholder.Fill(tt.Description, vars),
+		Priority:    tt.Priority,
+		Metadata:    tt.Metadata,
+	}
+	subtasks := make([]repository.CreateTaskParams, len(tt.Subtasks))
+	for i, sub := range tt.Subtasks {
+		subtasks[i] = repository.CreateTaskParams{
+			Title:       placeholder.Fill(sub.Title, vars),
+			Description: placeholder.Fill(sub.Description, vars),
+			Priority:    sub.Priority,
+			Metadata:    sub.Metadata,
+		}
+	}
+	if err := s.checkInstance(ctx, tt.ProjectID, params, subtasks); err != nil {
+		return nil, nil, err
+	}
+
+	slog.DebugContext(ctx, "instantiating template", "template_id", tt.ID, "project_id", tt.ProjectID)
+	return s.taskRepo.CreateWithSubtasks(ctx, params, subtasks)
+}
+
+// checkInstance validates the filled-in tasks of a template: titles must
+// still fit once placeholders are replaced, and metadata must follow the
+// project's custom fields. Subtask violations are reported under
+// subtasks[i].
+func (s *TemplateService) checkInstance(ctx context.Context, projectID uuid.UUID, task repository.CreateTaskParams, subtasks []repository.CreateTaskParams) error {
+	if err := validateInstanceTitle("", task.Title); err != nil {
+		return err
+	}
+	for i, sub := range subtasks {
+		if err := validateInstanceTitle(fmt.Sprintf("subtasks[%d].", i), sub.Title); err != nil {
+			return err
+		}
+	}
+
+	schema, err := s.customFieldRepo.GetByProjectID(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	violations := schema.Validate(task.Metadata)
+	for i, sub := range subtasks {
+		for _, v := range schema.Validate(sub.Metadata) {
+			v.Field = fmt.Sprintf("subtasks[%d].%s", i, v.Field)
+			violations = append(violations, v)
+		}
+	}
+	if len(violations) > 0 {
+		return &repository.ValidationError{Violations: violations}
+	}
+	return nil
+}
+
+func validateTemplateName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > 255 {
+		return fmt.Errorf("%w: name must be 1 to 255 characters", repository.ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateTemplateTitle checks the title of a template's task or, with
+// prefix subtasks[i]., of one of its subtasks.
+func validateTemplateTitle(prefix, title string) error {
+	if title == "" || utf8.RuneCountInString(title) > maxTitleLength {
+		return fmt.Errorf("%w: %stitle must be 1 to %d characters", repository.ErrInvalidInput, prefix, maxTitleLength)
+	}
+	return nil
+}
+
+// validatePriority checks a task priority; empty means the default,
+// medium.
+func validatePriority(prefix, priority string) error {
+	switch priority {
+	case "", "low", "medium", "high", "critical":
+		return nil
+	}
+	return fmt.Errorf("%w: %sinvalid priority: %s", repository.ErrInvalidInput, prefix, priority)
+}
+
+func validateTemplateSubtasks(subtasks []repository.TemplateSubtask) error {
+	if len(subtasks) > maxTemplateSubtasks {
+		return fmt.Errorf("%w: at most %d subtasks are allowed", repository.ErrInvalidInput, maxTemplateSubtasks)
+	}
+	for i, sub := range subtasks {
+		prefix := fmt.Sprintf("subtasks[%d].", i)
+		if err := validateTemplateTitle(prefix, sub.Title); err != nil {
+			return err
+		}
+		if err := validatePriority(prefix, sub.Priority); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateInstanceTitle(prefix, title string) error {
+	if strings.TrimSpace(title) == "" {
+		return fmt.Errorf("%w: %stitle is empty once variables are filled in", repository.ErrInvalidInput, prefix)
+	}
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		return fmt.Errorf("%w: %stitle is longer than %d characters once variables are filled in", repository.ErrInvalidInput, prefix, maxTitleLength)
+	}
+	return nil
+}
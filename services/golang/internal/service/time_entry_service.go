@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+)
+
+// maxTimeEntry is the longest stretch a single time entry may cover.
+const maxTimeEntry = 24 * time.Hour
+
+type TimeEntryService struct {
+	repo *repository.TimeEntryRepo
+}
+
+func NewTimeEntryService(repo *repository.TimeEntryRepo) *TimeEntryService {
+	return &TimeEntryService{repo: repo}
+}
+
+func (s *TimeEntryService) Create(ctx context.Context, params repository.CreateTimeEntryParams) (*repository.TimeEntry, error) {
+	userID, err := userOrCaller(ctx, params.UserID)
+	if err != nil {
+		return nil, err
+	}
+	params.UserID = userID
+	if params.StartedAt.IsZero() {
+		return nil, fmt.Errorf("%w: started_at is required", repository.ErrInvalidInput)
+	}
+	if err := validateEntryDuration(params.DurationSeconds); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, params)
+}
+
+func (s *TimeEntryService) GetByID(ctx context.Context, id uuid.UUID) (*repository.TimeEntry, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *TimeEntryService) List(ctx context.Context, params repository.ListTimeEntriesParams) (*repository.TimeEntryList, error) {
+	if err := validateTimeRange(params.Range); err != nil {
+		return nil, err
+	}
+	return s.repo.List(ctx, params)
+}
+
+func (s *TimeEntryService) Update(ctx context.Context, params repository.UpdateTimeEntryParams) (*repository.TimeEntry, error) {
+	if inMask(params.UpdateMask, "started_at") && params.StartedAt.IsZero() {
+		return nil, fmt.Errorf("%w: started_at is required", repository.ErrInvalidInput)
+	}
+	if inMask(params.UpdateMask, "duration") {
+		if err := validateEntryDuration(params.DurationSeconds); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Update(ctx, params)
+}
+
+func (s *TimeEntryService) Delete(ctx context.Context, id uuid.UUID) error {
+	return s.repo.Delete(ctx, id)
+}
+
+func (s *TimeEntryService) Totals(ctx context.Context, params repository.TimeTotalsParams) (*repository.TimeTotals, error) {
+	if err := validateTimeRange(params.Range); err != nil {
+		return nil, err
+	}
+	return s.repo.Totals(ctx, params)
+}
+
+func validateEntryDuration(seconds int64) error {
+	if seconds <= 0 || seconds > int64(maxTimeEntry/time.Second) {
+		return fmt.Errorf("%w: duration must be between 1s and %s", repository.ErrInvalidInput, maxTimeEntry)
+	}
+	return nil
+}
+
+func validateTimeRange(r repository.TimeRange) error {
+	if r.From != nil && r.To != nil && !r.To.After(*r.From) {
+		return fmt.Errorf("%w: end_time must be after start_time", repository.ErrInvalidInput)
+	}
+	return nil
+}
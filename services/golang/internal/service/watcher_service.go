@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/identity"
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+)
+
+type WatcherService struct {
+	repo *repository.WatcherRepo
+}
+
+func NewWatcherService(repo *repository.WatcherRepo) *WatcherService {
+	return &WatcherService{repo: repo}
+}
+
+func (s *WatcherService) WatchTask(ctx context.Context, taskID uuid.UUID, userID string) (*repository.Watcher, error) {
+	userID, err := userOrCaller(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.WatchTask(ctx, taskID, userID)
+}
+
+func (s *WatcherService) UnwatchTask(ctx context.Context, taskID uuid.UUID, userID string) error {
+	userID, err := userOrCaller(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.UnwatchTask(ctx, taskID, userID)
+}
+
+func (s *WatcherService) ListTaskWatchers(ctx context.Context, taskID uuid.UUID) ([]repository.Watcher, error) {
+	return s.repo.ListTaskWatchers(ctx, taskID)
+}
+
+func (s *WatcherService) WatchProject(ctx context.Context, projectID uuid.UUID, userID string) (*repository.Watcher, error) {
+	userID, err := userOrCaller(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.WatchProject(ctx, projectID, userID)
+}
+
+func (s *WatcherService) UnwatchProject(ctx context.Context, projectID uuid.UUID, userID string) error {
+	userID, err := userOrCaller(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return s.repo.UnwatchProject(ctx, projectID, userID)
+}
+
+func (s *WatcherService) ListProjectWatchers(ctx context.Context, projectID uuid.UUID) ([]repository.Watcher, error) {
+	return s.repo.ListProjectWatchers(ctx, projectID)
+}
+
+// userOrCaller defaults an empty user ID to the caller's.
+func userOrCaller(ctx context.Context, userID string) (string, error) {
+	if userID == "" {
+		userID = identity.UserID(ctx)
+	}
+	if userID == "" {
+		return "", fmt.Errorf("%w: user_id is required when no %s header is sent", repository.ErrInvalidInput, identity.Header)
+	}
+	if utf8.RuneCountInString(userID) > identity.MaxUserIDLength {
+		return "", fmt.Errorf("%w: user_id must be at most %d characters", repository.ErrInvalidInput, identity.MaxUserIDLength)
+	}
+	return userID, nil
+}
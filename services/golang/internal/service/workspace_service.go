@@ -10,12 +10,16 @@ import (
 	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
 )
 
+// maxReminderHours caps reminder thresholds at 30 days.
+const maxReminderHours = 720
+
 type WorkspaceService struct {
-	repo *repository.WorkspaceRepo
+	repo         *repository.WorkspaceRepo
+	reminderRepo *repository.ReminderRepo
 }
 
-func NewWorkspaceService(repo *repository.WorkspaceRepo) *WorkspaceService {
-	return &WorkspaceService{repo: repo}
+func NewWorkspaceService(repo *repository.WorkspaceRepo, reminderRepo *repository.ReminderRepo) *WorkspaceService {
+	return &WorkspaceService{repo: repo, reminderRepo: reminderRepo}
 }
 
 func (s *WorkspaceService) Create(ctx context.Context, params repository.CreateWorkspaceParams) (*repository.Workspace, error) {
@@ -38,12 +42,63 @@ func (s *WorkspaceService) List(ctx context.Context, params repository.ListWorks
 }
 
 func (s *WorkspaceService) Update(ctx context.Context, params repository.UpdateWorkspaceParams) (*repository.Workspace, error) {
-	if params.Name == "" {
+	if inMask(params.UpdateMask, "name") && params.Name == "" {
 		return nil, fmt.Errorf("%w: name is required", repository.ErrInvalidInput)
 	}
+	if inMask(params.UpdateMask, "slug") && params.Slug == "" {
+		return nil, fmt.Errorf("%w: slug is required", repository.ErrInvalidInput)
+	}
 	return s.repo.Update(ctx, params)
 }
 
-func (s *WorkspaceService) Delete(ctx context.Context, id uuid.UUID) error {
-	return s.repo.Delete(ctx, id)
+func (s *WorkspaceService) Delete(ctx context.Context, id uuid.UUID, version int32) error {
+	return s.repo.Delete(ctx, id, version)
+}
+
+// ListDeleted lists workspaces in the trash.
+func (s *WorkspaceService) ListDeleted(ctx context.Context, params repository.ListWorkspacesParams) (*repository.WorkspaceList, error) {
+	return s.repo.ListDeleted(ctx, params)
+}
+
+func (s *WorkspaceService) Restore(ctx context.Context, id uuid.UUID) (*repository.Workspace, error) {
+	slog.DebugContext(ctx, "restoring workspace", "workspace_id", id)
+	return s.repo.Restore(ctx, id)
+}
+
+func (s *WorkspaceService) GetReminderSettings(ctx context.Context, workspaceID uuid.UUID) (*repository.ReminderSettings, error) {
+	return s.reminderRepo.GetSettings(ctx, workspaceID)
+}
+
+func (s *WorkspaceService) UpdateReminderSettings(ctx context.Context, settings repository.ReminderSettings) (*repository.ReminderSettings, error) {
+	if err := validateThresholds("due_soon_hours", settings.DueSoonHours, 1); err != nil {
+		return nil, err
+	}
+	if err := validateThresholds("overdue_hours", settings.OverdueHours, 0); err != nil {
+		return nil, err
+	}
+	// Store empty lists rather than NULL so "no reminders" sticks.
+	if settings.DueSoonHours == nil {
+		settings.DueSoonHours = []int32{}
+	}
+	if settings.OverdueHours == nil {
+		settings.OverdueHours = []int32{}
+	}
+	return s.reminderRepo.UpdateSettings(ctx, settings)
+}
+
+func validateThresholds(field string, hours []int32, min int32) error {
+	if len(hours) > 10 {
+		return fmt.Errorf("%w: %s allows at most 10 thresholds", repository.ErrInvalidInput, field)
+	}
+	seen := make(map[int32]bool, len(hours))
+	for _, h := range hours {
+		if h < min || h > maxReminderHours {
+			return fmt.Errorf("%w: %s values must be between %d and %d", repository.ErrInvalidInput, field, min, maxReminderHours)
+		}
+		if seen[h] {
+			return fmt.Errorf("%w: duplicate %s value %d", repository.ErrInvalidInput, field, h)
+		}
+		seen[h] = true
+	}
+	return nil
 }
@@ -0,0 +1,348 @@
+// Package taskquery parses the query language of ListTasks, e.g.
+//
+//	status:in_progress priority>=high assignee:me due<2026-11-01 label:backend
+//
+// A query is a list of terms separated by spaces; a task matches when it
+// matches every term. A term is field, operator and value, optionally
+// negated with a leading '-'. A word without a field matches titles that
+// contain it. Values may be quoted ("needs review"), and ':' and '=' accept
+// a comma-separated list matching any of its values. The value none matches
+// tasks where the field is unset; me matches the calling user. Quoted, "none"
+// and "me" are plain values.
+//
+// Fields: status, priority (low < medium < high < critical), assignee
+// (any of the task's assignees), label (by name, ignoring case), due,
+// created and updated (dates, YYYY-MM-DD, in UTC) and title (contains,
+// ignoring case).
+package taskquery
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// ErrInvalid is wrapped by every parse error.
+var ErrInvalid = errors.New("invalid query")
+
+// MaxLength and MaxTerms bound the size of a query.
+const (
+	MaxLength = 1000
+	MaxTerms  = 20
+)
+
+// Error is a parse error at a position of the query, counted in characters
+// from 0.
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s at position %d: %s", ErrInvalid, e.Pos, e.Msg)
+}
+
+func (e *Error) Unwrap() error {
+	return ErrInvalid
+}
+
+// Errorf returns an Error at pos.
+func Errorf(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+// Kind is the type of a field's values.
+type Kind int
+
+const (
+	KindText     Kind = iota // compared for equality
+	KindPriority             // ordered: low < medium < high < critical
+	KindDate                 // a calendar day
+	KindContains             // substring match
+)
+
+// Op is a term's comparison operator. != is parsed as a negated OpEq.
+type Op string
+
+const (
+	OpEq Op = "="
+	OpLt Op = "<"
+	OpLe Op = "<="
+	OpGt Op = ">"
+	OpGe Op = ">="
+)
+
+// Priorities lists the task priorities in ascending order.
+var Priorities = []string{"low", "medium", "high", "critical"}
+
+type field struct {
+	kind     Kind
+	nullable bool // accepts none
+	me       bool // accepts me
+}
+
+var fields = map[string]field{
+	"status":   {kind: KindText},
+	"priority": {kind: KindPriority},
+	"assignee": {kind: KindText, nullable: true, me: true},
+	"label":    {kind: KindText, nullable: true},
+	"due":      {kind: KindDate, nullable: true},
+	"created":  {kind: KindDate},
+	"updated":  {kind: KindDate},
+	"title":    {kind: KindContains},
+}
+
+var aliases = map[string]string{
+	"assigned_to": "assignee",
+	"due_date":    "due",
+	"labels":      "label",
+}
+
+// Query is a parsed query. The zero value matches every task.
+type Query struct {
+	Terms []Term
+}
+
+// Term is one condition of a query.
+type Term struct {
+	Pos    int    // position of the term in the query
+	Field  string // canonical name, e.g. assignee for assigned_to
+	Kind   Kind
+	Op     Op
+	Negate bool
+	Values []Value // several only for OpEq, meaning any of them
+}
+
+// Value is one operand of a term.
+type Value struct {
+	Pos  int
+	Text string    // KindText, KindPriority and KindContains; lowercased except for KindText
+	Date time.Time // KindDate, at midnight UTC
+	None bool      // the field is unset
+	Me   bool      // the calling user; set Text once known
+
+	quoted bool // never a keyword
+}
+
+// Parse parses a query. An empty or blank query yields an empty Query.
+func Parse(s string) (*Query, error) {
+	p := &parser{src: []rune(s)}
+	if len(p.src) > MaxLength {
+		return nil, Errorf(MaxLength, "query is longer than %d characters", MaxLength)
+	}
+	q := &Query{}
+	for {
+		p.skipSpace()
+		if p.eof() {
+			return q, nil
+		}
+		if len(q.Terms) == MaxTerms {
+			return nil, Errorf(p.pos, "query has more than %d terms", MaxTerms)
+		}
+		t, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		if !p.eof() && !unicode.IsSpace(p.peek()) {
+			return nil, Errorf(p.pos, "unexpected %q", p.peek())
+		}
+		q.Terms = append(q.Terms, t)
+	}
+}
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+func (p *parser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *parser) peek() rune {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *parser) skipSpace() {
+	for !p.eof() && unicode.IsSpace(p.peek()) {
+		p.pos++
+	}
+}
+
+func (p *parser) term() (Term, error) {
+	t := Term{Pos: p.pos, Op: OpEq}
+	if p.peek() == '-' {
+		t.Negate = true
+		p.pos++
+	}
+
+	// A term is a field only if a name is directly followed by an operator;
+	// anything else is a word to look for in titles.
+	start := p.pos
+	for !p.eof() && (p.peek() == '_' || unicode.IsLetter(p.peek())) {
+		p.pos++
+	}
+	name := strings.ToLower(string(p.src[start:p.pos]))
+	op, opLen := p.operator()
+	if name == "" || opLen == 0 {
+		p.pos = start
+		v, err := p.value(false)
+		if err != nil {
+			return t, err
+		}
+		if v.Text == "" {
+			return t, Errorf(v.Pos, "expected a search term")
+		}
+		t.Field, t.Kind = "title", KindContains
+		v.Text = strings.ToLower(v.Text)
+		t.Values = []Value{v}
+		return t, nil
+	}
+
+	if canonical, ok := aliases[name]; ok {
+		name = canonical
+	}
+	f, ok := fields[name]
+	if !ok {
+		return t, Errorf(start, "unknown field %q", name)
+	}
+	t.Field, t.Kind = name, f.kind
+
+	opPos := p.pos
+	p.pos += opLen
+	if op == "!=" {
+		t.Negate = !t.Negate
+		op = OpEq
+	}
+	t.Op = op
+	if op != OpEq && f.kind != KindPriority && f.kind != KindDate {
+		return t, Errorf(opPos, "%s does not support %s", name, op)
+	}
+
+	for {
+		v, err := p.value(op == OpEq && f.kind != KindContains)
+		if err != nil {
+			return t, err
+		}
+		if err := f.check(name, &v); err != nil {
+			return t, err
+		}
+		if (v.None || v.Me) && (op != OpEq || len(t.Values) > 0 || p.peek() == ',') {
+			return t, Errorf(v.Pos, "%s cannot be combined with other values or operators", v.Text)
+		}
+		t.Values = append(t.Values, v)
+		if p.peek() != ',' || op != OpEq || f.kind == KindContains {
+			break
+		}
+		p.pos++
+	}
+	return t, nil
+}
+
+// operator reads the operator at the current position without consuming
+// it, returning its length, or 0 if there is none.
+func (p *parser) operator() (Op, int) {
+	rest := string(p.src[p.pos:min(p.pos+2, len(p.src))])
+	switch {
+	case strings.HasPrefix(rest, "!="):
+		return "!=", 2
+	case strings.HasPrefix(rest, "<="):
+		return OpLe, 2
+	case strings.HasPrefix(rest, ">="):
+		return OpGe, 2
+	case strings.HasPrefix(rest, ":"), strings.HasPrefix(rest, "="):
+		return OpEq, 1
+	case strings.HasPrefix(rest, "<"):
+		return OpLt, 1
+	case strings.HasPrefix(rest, ">"):
+		return OpGt, 1
+	}
+	return "", 0
+}
+
+// value reads a quoted or bare value. In a list, a bare value ends at a
+// comma.
+func (p *parser) value(list bool) (Value, error) {
+	v := Value{Pos: p.pos}
+	if p.peek() == '"' {
+		var b strings.Builder
+		p.pos++
+		for {
+			if p.eof() {
+				return v, Errorf(v.Pos, "unterminated quoted value")
+			}
+			r := p.src[p.pos]
+			p.pos++
+			if r == '"' {
+				break
+			}
+			if r == '\\' && !p.eof() {
+				r = p.src[p.pos]
+				p.pos++
+			}
+			b.WriteRune(r)
+		}
+		v.Text = b.String()
+		v.quoted = true
+		return v, nil
+	}
+	start := p.pos
+	for !p.eof() && !unicode.IsSpace(p.peek()) && !(list && p.peek() == ',') {
+		if p.peek() == '"' {
+			return v, Errorf(p.pos, "unexpected quote inside a value")
+		}
+		p.pos++
+	}
+	v.Text = string(p.src[start:p.pos])
+	if v.Text == "" {
+		return v, Errorf(v.Pos, "expected a value")
+	}
+	return v, nil
+}
+
+// check validates and converts a value of the field.
+func (f field) check(name string, v *Value) error {
+	keyword := ""
+	if !v.quoted {
+		keyword = strings.ToLower(v.Text)
+	}
+	switch keyword {
+	case "none":
+		if f.nullable {
+			v.None = true
+			return nil
+		}
+	case "me":
+		if f.me {
+			v.Me = true
+			return nil
+		}
+	}
+	switch f.kind {
+	case KindText:
+		if v.Text == "" {
+			return Errorf(v.Pos, "expected a value for %s", name)
+		}
+	case KindPriority:
+		v.Text = strings.ToLower(v.Text)
+		if !slices.Contains(Priorities, v.Text) {
+			return Errorf(v.Pos, "invalid priority %q; expected one of %s", v.Text, strings.Join(Priorities, ", "))
+		}
+	case KindDate:
+		d, err := time.Parse(time.DateOnly, v.Text)
+		if err != nil {
+			return Errorf(v.Pos, "invalid date %q; expected YYYY-MM-DD", v.Text)
+		}
+		v.Date = d
+	case KindContains:
+		v.Text = strings.ToLower(v.Text)
+		if v.Text == "" {
+			return Errorf(v.Pos, "expected a value for %s", name)
+		}
+	}
+	return nil
+}
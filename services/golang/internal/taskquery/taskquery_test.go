@@ -0,0 +1,212 @@
+package taskquery
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// describe renders a parsed query compactly, one term per element, e.g.
+// "-assignee=<me>" or "priority>=high".
+func describe(q *Query) []string {
+	var out []string
+	for _, t := range q.Terms {
+		var b strings.Builder
+		if t.Negate {
+			b.WriteByte('-')
+		}
+		b.WriteString(t.Field)
+		b.WriteString(string(t.Op))
+		for i, v := range t.Values {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			switch {
+			case v.None:
+				b.WriteString("<none>")
+			case v.Me:
+				b.WriteString("<me>")
+			case t.Kind == KindDate:
+				b.WriteString(v.Date.Format("2006-01-02"))
+			default:
+				b.WriteString(v.Text)
+			}
+		}
+		out = append(out, b.String())
+	}
+	return out
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"   ", nil},
+		{"status:in_progress", []string{"status=in_progress"}},
+		{"status=todo", []string{"status=todo"}},
+		{"STATUS:Todo", []string{"status=Todo"}},
+		{"assigned_to:alice due_date:none labels:backend", []string{"assignee=alice", "due=<none>", "label=backend"}},
+
+		// Words without a field search titles.
+		{"Crash", []string{"title=crash"}},
+		{"login crash", []string{"title=login", "title=crash"}},
+		{"-flaky", []string{"-title=flaky"}},
+		{"10:30", []string{"title=10:30"}},
+		{"title:Deploy", []string{"title=deploy"}},
+
+		// Operators.
+		{"priority>=high", []string{"priority>=high"}},
+		{"priority<Medium", []string{"priority<medium"}},
+		{"priority>low priority<=critical", []string{"priority>low", "priority<=critical"}},
+		{"due<2026-11-01", []string{"due<2026-11-01"}},
+		{"created>=2026-01-01 updated>2026-02-01", []string{"created>=2026-01-01", "updated>2026-02-01"}},
+		{"status!=done", []string{"-status=done"}},
+		{"-status:done", []string{"-status=done"}},
+		{"-status!=done", []string{"status=done"}},
+
+		// Quoting.
+		{`title:"needs review"`, []string{"title=needs review"}},
+		{`"needs review"`, []string{"title=needs review"}},
+		{`label:"a \"b\" c"`, []string{`label=a "b" c`}},
+		{`label:"ops, infra"`, []string{"label=ops, infra"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := describe(q); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Parse(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseLists(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"status:todo,review", []string{"status=todo,review"}},
+		{"-label=bug,Backend", []string{"-label=bug,Backend"}},
+		{`label:"a,b",c`, []string{"label=a,b,c"}},
+		{"priority:low,high", []string{"priority=low,high"}},
+		{"due:2026-01-01,2026-02-01", []string{"due=2026-01-01,2026-02-01"}},
+		// title takes no list: the comma is part of the text.
+		{"title:a,b", []string{"title=a,b"}},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := describe(q); strings.Join(got, "|") != strings.Join(tt.want, "|") {
+			t.Errorf("Parse(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+	q, err := Parse(`label:"a,b",c`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(q.Terms[0].Values); n != 2 {
+		t.Errorf(`Parse(label:"a,b",c) has %d values, want 2`, n)
+	}
+}
+
+func TestParseKeywords(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"assignee:me", "assignee=<me>"},
+		{"assignee:ME", "assignee=<me>"},
+		{"assignee:none", "assignee=<none>"},
+		{"-assignee:none", "-assignee=<none>"},
+		{"label:None", "label=<none>"},
+		{"due:none", "due=<none>"},
+		// Quoted, they are plain values.
+		{`assignee:"me"`, "assignee=me"},
+		{`assignee:"none"`, "assignee=none"},
+		{`label:"none"`, "label=none"},
+		// Fields that take neither keep them as values.
+		{"status:none", "status=none"},
+		{"label:me", "label=me"},
+		{"none", "title=none"},
+	}
+	for _, tt := range tests {
+		q, err := Parse(tt.in)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.in, err)
+			continue
+		}
+		if got := describe(q); len(got) != 1 || got[0] != tt.want {
+			t.Errorf("Parse(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParsePositions(t *testing.T) {
+	q, err := Parse(`  status:todo,"in review" -due<2026-11-01`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := q.Terms[0].Pos; got != 2 {
+		t.Errorf("first term at %d, want 2", got)
+	}
+	if got := []int{q.Terms[0].Values[0].Pos, q.Terms[0].Values[1].Pos}; got[0] != 9 || got[1] != 14 {
+		t.Errorf("first term values at %v, want [9 14]", got)
+	}
+	if got := q.Terms[1].Pos; got != 26 {
+		t.Errorf("second term at %d, want 26", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		in  string
+		pos int
+		msg string
+	}{
+		{"foo:bar", 0, `unknown field "foo"`},
+		{"status:todo foo:bar", 12, `unknown field "foo"`},
+		{"status<todo", 6, "status does not support <"},
+		{"title>=x", 5, "title does not support >="},
+		{"priority:urgent", 9, "invalid priority"},
+		{"priority>=none", 10, "invalid priority"},
+		{"due:2026-13-01", 4, "invalid date"},
+		{"created:none", 8, "invalid date"},
+		{"status:", 7, "expected a value"},
+		{"status:todo,", 12, "expected a value"},
+		{`status:""`, 7, "expected a value for status"},
+		{`title:""`, 6, "expected a value for title"},
+		{`""`, 0, "expected a search term"},
+		{"-", 1, "expected a value"},
+		{`title:"abc`, 6, "unterminated quoted value"},
+		{`status:a"b`, 8, "unexpected quote inside a value"},
+		{`"a"b`, 3, `unexpected 'b'`},
+		{"assignee:me,bob", 9, "cannot be combined"},
+		{"assignee:bob,none", 13, "cannot be combined"},
+		{"due<none", 4, "cannot be combined"},
+		// Positions count characters, not bytes.
+		{`"é" foo:bar`, 4, `unknown field "foo"`},
+		{strings.Repeat("a ", 20) + "b", 40, "more than 20 terms"},
+		{strings.Repeat("a", MaxLength+1), MaxLength, "longer than 1000 characters"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.in)
+		var perr *Error
+		if !errors.As(err, &perr) {
+			t.Errorf("Parse(%q) error = %v, want *Error", tt.in, err)
+			continue
+		}
+		if !errors.Is(err, ErrInvalid) {
+			t.Errorf("Parse(%q) error does not wrap ErrInvalid", tt.in)
+		}
+		if perr.Pos != tt.pos || !strings.Contains(perr.Msg, tt.msg) {
+			t.Errorf("Parse(%q) error = %d %q, want %d %q", tt.in, perr.Pos, perr.Msg, tt.pos, tt.msg)
+		}
+	}
+}
@@ -25,21 +25,20 @@ type ImportTaskInput struct {
 	Title       string          `json:"title"`
 	Description string          `json:"description"`
 	Priority    string          `json:"priority"`
-	AssignedTo  string          `json:"assigned_to"`
+	AssignedTo  string          `json:"assigned_to"` // used when Assignees is empty
+	Assignees   []string        `json:"assignees,omitempty"`
 	Metadata    json.RawMessage `json:"metadata,omitempty"`
 }
 
 // ImportWorker processes bulk import jobs asynchronously.
 type ImportWorker struct {
 	river.WorkerDefaults[ImportJobArgs]
-	taskRepo  *repository.TaskRepo
-	notifRepo *repository.NotificationRepo
+	taskRepo *repository.TaskRepo
 }
 
-func NewImportWorker(taskRepo *repository.TaskRepo, notifRepo *repository.NotificationRepo) *ImportWorker {
+func NewImportWorker(taskRepo *repository.TaskRepo) *ImportWorker {
 	return &ImportWorker{
-		taskRepo:  taskRepo,
-		notifRepo: notifRepo,
+		taskRepo: taskRepo,
 	}
 }
 
@@ -61,13 +60,17 @@ func (w *ImportWorker) Work(ctx context.Context, job *river.Job[ImportJobArgs])
 
 	var succeeded, failed int
 	for i, input := range job.Args.Tasks {
-		task, createErr := w.taskRepo.Create(ctx, repository.CreateTaskParams{
+		assignees := input.Assignees
+		if len(assignees) == 0 && input.AssignedTo != "" {
+			assignees = []string{input.AssignedTo}
+		}
+		_, createErr := w.taskRepo.Create(ctx, repository.CreateTaskParams{
 			WorkspaceID: workspaceID,
 			ProjectID:   projectID,
 			Title:       input.Title,
 			Description: input.Description,
 			Priority:    input.Priority,
-			AssignedTo:  input.AssignedTo,
+			Assignees:   assignees,
 			Metadata:    input.Metadata,
 		})
 		if createErr != nil {
@@ -80,15 +83,6 @@ func (w *ImportWorker) Work(ctx context.Context, job *river.Job[ImportJobArgs])
 		}
 
 		succeeded++
-
-		// Enqueue notification for imported task
-		if w.notifRepo != nil {
-			_, _ = w.notifRepo.Create(ctx, repository.CreateNotificationParams{
-				WorkspaceID: workspaceID,
-				EventType:   "task.imported",
-				Payload:     []byte(fmt.Sprintf(`{"task_id":"%s","title":"%s"}`, task.ID, task.Title)),
-			})
-		}
 	}
 
 	slog.InfoContext(ctx, "async bulk import completed",
@@ -0,0 +1,46 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/riverqueue/river"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/service"
+)
+
+// RecurrenceJobArgs triggers creation of the next occurrence of recurring
+// tasks. It is enqueued periodically by the worker.
+type RecurrenceJobArgs struct {
+	BatchSize int `json:"batch_size"`
+}
+
+func (RecurrenceJobArgs) Kind() string { return "task_recurrence" }
+
+// RecurrenceWorker materialises the next occurrence of recurring tasks whose
+// current occurrence was completed or has come due.
+type RecurrenceWorker struct {
+	river.WorkerDefaults[RecurrenceJobArgs]
+	svc *service.RecurrenceService
+}
+
+func NewRecurrenceWorker(svc *service.RecurrenceService) *RecurrenceWorker {
+	return &RecurrenceWorker{svc: svc}
+}
+
+func (w *RecurrenceWorker) Work(ctx context.Context, job *river.Job[RecurrenceJobArgs]) error {
+	batchSize := job.Args.BatchSize
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+
+	created, err := w.svc.CreateDueOccurrences(ctx, batchSize)
+	if err != nil {
+		return fmt.Errorf("create due occurrences: %w", err)
+	}
+	if created > 0 {
+		slog.InfoContext(ctx, "created recurring task occurrences", "count", created)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/riverqueue/river"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+)
+
+// DueReminderJobArgs triggers a scan for tasks that are due soon or overdue.
+// It is enqueued periodically by the worker.
+type DueReminderJobArgs struct{}
+
+func (DueReminderJobArgs) Kind() string { return "task_due_reminder" }
+
+// DueReminderWorker queues task.due_soon and task.overdue notifications.
+type DueReminderWorker struct {
+	river.WorkerDefaults[DueReminderJobArgs]
+	reminderRepo *repository.ReminderRepo
+}
+
+func NewDueReminderWorker(reminderRepo *repository.ReminderRepo) *DueReminderWorker {
+	return &DueReminderWorker{reminderRepo: reminderRepo}
+}
+
+func (w *DueReminderWorker) Work(ctx context.Context, job *river.Job[DueReminderJobArgs]) error {
+	queued, err := w.reminderRepo.EnqueueDue(ctx, time.Now())
+	if err != nil {
+		return fmt.Errorf("enqueue due reminders: %w", err)
+	}
+	if queued > 0 {
+		slog.InfoContext(ctx, "queued due-date reminders", "count", queued)
+	}
+	return nil
+}
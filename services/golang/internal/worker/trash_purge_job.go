@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/riverqueue/river"
+
+	"github.com/igorrmotta/api-corestack/services/golang/internal/repository"
+)
+
+// TrashPurgeJobArgs triggers a hard delete of trash older than the
+// retention period. It is enqueued periodically by the worker.
+type TrashPurgeJobArgs struct{}
+
+func (TrashPurgeJobArgs) Kind() string { return "trash_purge" }
+
+// TrashPurgeWorker permanently removes soft-deleted workspaces, projects and
+// tasks, with their comments, attachments and notifications, once they have
+// been in the trash for longer than retention.
+type TrashPurgeWorker struct {
+	river.WorkerDefaults[TrashPurgeJobArgs]
+	trashRepo *repository.TrashRepo
+	retention time.Duration
+}
+
+func NewTrashPurgeWorker(trashRepo *repository.TrashRepo, retention time.Duration) *TrashPurgeWorker {
+	return &TrashPurgeWorker{trashRepo: trashRepo, retention: retention}
+}
+
+func (w *TrashPurgeWorker) Work(ctx context.Context, job *river.Job[TrashPurgeJobArgs]) error {
+	res, err := w.trashRepo.Purge(ctx, time.Now().Add(-w.retention))
+	if err != nil {
+		return fmt.Errorf("purge trash: %w", err)
+	}
+	if res.Workspaces+res.Projects+res.Tasks > 0 {
+		slog.InfoContext(ctx, "purged expired trash",
+			"workspaces", res.Workspaces,
+			"projects", res.Projects,
+			"tasks", res.Tasks,
+			"comments", res.Comments,
+			"attachments", res.Attachments,
+			"notifications", res.Notifications,
+		)
+	}
+	return nil
+}